@@ -0,0 +1,256 @@
+package analysis
+
+import (
+	"strings"
+	"time"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// Codes of the access diagnostics, each of them can be disabled by the clients
+const (
+	CodeInaccessibleMethod    = "inaccessible-method"
+	CodeInaccessibleProperty  = "inaccessible-property"
+	CodeNonStaticCall         = "non-static-call"
+	CodeThisInStaticContext   = "this-in-static-context"
+	CodeAbstractInstantiation = "abstract-instantiation"
+)
+
+// AccessDiagnostics returns the diagnostics for the members which cannot be
+// accessed from where they are used, the instance methods which are called
+// statically, $this in static methods and the instantiations of abstract
+// classes or interfaces
+func AccessDiagnostics(ctx ResolveContext) []protocol.Diagnostic {
+	defer util.TimeTrack(time.Now(), "AccessDiagnostics")
+	doc := ctx.document
+	q := ctx.query
+	create := func(r protocol.Range, code string, message string) protocol.Diagnostic {
+		return newDiagnostic(code, r, message)
+	}
+	diagnostics := []protocol.Diagnostic{}
+	tr := newTraverser()
+	tr.traverseDocument(doc, func(_ *traverser, s Symbol, spine []Symbol) {
+		if _, ok := doc.getClassAtPos(s.GetLocation().Range.Start).(*Trait); ok {
+			// The members of traits are checked where they are used
+			return
+		}
+		switch v := s.(type) {
+		case *MethodAccess:
+			currentClass := doc.GetClassScopeAtSymbol(v)
+			scopeTypes := v.ResolveAndGetScope(ctx)
+			if m := q.inaccessibleMethod(currentClass, v, v.Name, scopeTypes, false); m != nil &&
+				len(q.GetMagicMethods(scopeTypes, MagicCall)) == 0 {
+				diagnostics = append(diagnostics, create(v.Location.Range, CodeInaccessibleMethod,
+					"Call to "+m.VisibilityModifier.ToString()+" method "+calleeLabel(m)+" from "+scopeLabel(currentClass)+"."))
+			}
+		case *ScopedMethodAccess:
+			currentClass := doc.GetClassScopeAtSymbol(v)
+			scopeTypes := v.ResolveAndGetScope(ctx)
+			if len(q.GetMagicMethods(scopeTypes, v.MagicMethodName())) > 0 {
+				break
+			}
+			if m := q.inaccessibleMethod(currentClass, v, v.Name, scopeTypes, true); m != nil {
+				diagnostics = append(diagnostics, create(v.Location.Range, CodeInaccessibleMethod,
+					"Call to "+m.VisibilityModifier.ToString()+" method "+calleeLabel(m)+" from "+scopeLabel(currentClass)+"."))
+				break
+			}
+			if m := q.nonStaticMethod(v, scopeTypes); m != nil &&
+				!(isInInstanceMethod(spine) && q.isSelfOrAncestor(currentClass, v, scopeTypes)) {
+				diagnostics = append(diagnostics, create(v.Location.Range, CodeNonStaticCall,
+					"Non-static method "+calleeLabel(m)+" cannot be called statically."))
+			}
+		case *PropertyAccess:
+			currentClass := doc.GetClassScopeAtSymbol(v)
+			scopeTypes := v.ResolveAndGetScope(ctx)
+			if p := q.inaccessibleProperty(currentClass, v, scopeTypes); p != nil &&
+				len(q.GetMagicMethods(scopeTypes, MagicGet)) == 0 {
+				diagnostics = append(diagnostics, create(v.Location.Range, CodeInaccessibleProperty,
+					"Cannot access "+p.VisibilityModifier.ToString()+" property "+
+						p.Scope.GetOriginal()+"::"+p.Name+"."))
+			}
+		case *Variable:
+			if v.Name == "$this" && isInStaticMethod(spine) {
+				diagnostics = append(diagnostics, create(v.Location.Range, CodeThisInStaticContext,
+					"Using $this when not in object context."))
+			}
+		case *ClassTypeDesignator:
+			if v.Name == "" || IsNameRelative(v.Name) || IsNameParent(v.Name) {
+				break
+			}
+			if message := q.instantiationError(v.GetTypes()); message != "" {
+				diagnostics = append(diagnostics, create(v.Location.Range, CodeAbstractInstantiation, message))
+			}
+		}
+	})
+	return diagnostics
+}
+
+func scopeLabel(currentClass string) string {
+	if currentClass == "" {
+		return "global scope"
+	}
+	return "scope " + strings.TrimPrefix(currentClass, "\\")
+}
+
+// isVisibleFrom checks whether the member is visible from the current class
+// regardless of the object it is accessed on
+func (q *Query) isVisibleFrom(currentClass string, member MemberSymbol) bool {
+	scope := member.ScopeTypeString().GetFQN()
+	switch member.Visibility() {
+	case Private:
+		return strings.EqualFold(currentClass, scope)
+	case Protected:
+		return currentClass != "" && (q.IsSubclassOf(currentClass, scope) || q.IsSubclassOf(scope, currentClass))
+	}
+	return true
+}
+
+// isAccessible checks whether the member is accessible, the private members
+// inherited from the parent classes are not accessible
+func (q *Query) isAccessible(currentClass string, member MemberSymbol, isInherited bool) bool {
+	if member.Visibility() == Private {
+		return q.isVisibleFrom(currentClass, member)
+	}
+	return isInherited || q.isVisibleFrom(currentClass, member)
+}
+
+// isTraitMember checks whether the member is declared in a trait, the
+// visibility of which depends on the class using the trait
+func (q *Query) isTraitMember(member MemberSymbol) bool {
+	return len(q.GetTraits(member.ScopeTypeString().GetFQN())) > 0
+}
+
+// inaccessibleMethod returns the method if none of the methods of the scope types
+// with the name can be accessed, methods which are not found are not reported
+func (q *Query) inaccessibleMethod(currentClass string, access MemberAccess, name string, scopeTypes TypeComposite, isStatic bool) *Method {
+	var inaccessible *Method
+	for _, scopeType := range scopeTypes.Resolve() {
+		methods := EmptyInheritedMethods()
+		for _, class := range q.GetClasses(scopeType.GetFQN()) {
+			methods.Merge(q.GetClassMethods(class, name, methods.SearchedFQNs))
+		}
+		for _, ms := range methods.Methods {
+			m := ms.Method
+			if m == nil || q.isTraitMember(m) || isStatic && !m.IsStatic() && !IsNameParent(access.ScopeName()) && !IsNameRelative(access.ScopeName()) {
+				continue
+			}
+			isInherited := IsInherited(currentClass, access, methods.RelationMap, m)
+			if isStatic {
+				isInherited = IsInheritedStatic(currentClass, access, methods.RelationMap, m)
+			}
+			if q.isAccessible(currentClass, m, isInherited) {
+				return nil
+			}
+			if inaccessible == nil {
+				inaccessible = m
+			}
+		}
+	}
+	return inaccessible
+}
+
+// inaccessibleProperty returns the property if none of the properties of the
+// scope types with the name can be accessed
+func (q *Query) inaccessibleProperty(currentClass string, access *PropertyAccess, scopeTypes TypeComposite) *Property {
+	var inaccessible *Property
+	for _, scopeType := range scopeTypes.Resolve() {
+		props := EmptyInheritedProps()
+		for _, class := range q.GetClasses(scopeType.GetFQN()) {
+			props.Merge(q.GetClassProps(class, "$"+access.Name, props.SearchedFQNs))
+		}
+		for _, ps := range props.Props {
+			p := ps.Prop
+			if p.IsStatic() || q.isTraitMember(p) {
+				continue
+			}
+			if q.isAccessible(currentClass, p, IsInherited(currentClass, access, props.RelationMap, p)) {
+				return nil
+			}
+			if inaccessible == nil {
+				inaccessible = p
+			}
+		}
+	}
+	return inaccessible
+}
+
+// nonStaticMethod returns the method if all the methods with the name are not static
+func (q *Query) nonStaticMethod(access *ScopedMethodAccess, scopeTypes TypeComposite) *Method {
+	var nonStatic *Method
+	for _, scopeType := range scopeTypes.Resolve() {
+		for _, class := range q.GetClasses(scopeType.GetFQN()) {
+			for _, ms := range q.GetClassMethods(class, access.Name, nil).Methods {
+				if ms.Method == nil {
+					continue
+				}
+				if ms.Method.IsStatic() {
+					return nil
+				}
+				if nonStatic == nil {
+					nonStatic = ms.Method
+				}
+			}
+		}
+	}
+	return nonStatic
+}
+
+// isSelfOrAncestor checks whether the scope of the access is the current class or its ancestors
+func (q *Query) isSelfOrAncestor(currentClass string, access *ScopedMethodAccess, scopeTypes TypeComposite) bool {
+	if IsNameParent(access.ScopeName()) || IsNameRelative(access.ScopeName()) {
+		return true
+	}
+	if currentClass == "" {
+		return false
+	}
+	for _, scopeType := range scopeTypes.Resolve() {
+		if q.IsSubclassOf(currentClass, scopeType.GetFQN()) {
+			return true
+		}
+	}
+	return false
+}
+
+// enclosingMethod returns the closest method of the spine
+func enclosingMethod(spine []Symbol) *Method {
+	for i := len(spine) - 1; i >= 0; i-- {
+		if method, ok := spine[i].(*Method); ok {
+			return method
+		}
+	}
+	return nil
+}
+
+func isInInstanceMethod(spine []Symbol) bool {
+	method := enclosingMethod(spine)
+	return method != nil && !method.IsStatic()
+}
+
+func isInStaticMethod(spine []Symbol) bool {
+	method := enclosingMethod(spine)
+	return method != nil && method.IsStatic()
+}
+
+// instantiationError returns the error message if the types cannot be instantiated
+func (q *Query) instantiationError(types TypeComposite) string {
+	for _, t := range types.Resolve() {
+		classes := q.GetClasses(t.GetFQN())
+		if len(classes) == 0 {
+			if len(q.GetInterfaces(t.GetFQN())) > 0 {
+				return "Cannot instantiate interface " + t.GetOriginal() + "."
+			}
+			continue
+		}
+		for _, class := range classes {
+			if class.Enum() != nil {
+				return "Cannot instantiate enum " + class.Name.GetOriginal() + "."
+			}
+			if class.Modifier != Abstract {
+				return ""
+			}
+		}
+		return "Cannot instantiate abstract class " + classes[0].Name.GetOriginal() + "."
+	}
+	return ""
+}
@@ -3,25 +3,31 @@ package analysis
 import (
 	"log"
 
+	"github.com/john-nguyen09/go-phpparser/lexer"
 	"github.com/john-nguyen09/go-phpparser/phrase"
 	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
 	"github.com/john-nguyen09/phpintel/util"
 )
 
+// AnonymousFunction contains information of closures and arrow functions
 type AnonymousFunction struct {
 	location protocol.Location
 	children []Symbol
 
-	Params []*Parameter
+	Params      []*Parameter
+	returnTypes TypeComposite
 }
 
 var _ BlockSymbol = (*AnonymousFunction)(nil)
+var _ HasTypes = (*AnonymousFunction)(nil)
 
-func newAnonymousFunction(a analyser, document *Document, node *phrase.Phrase) Symbol {
+func newAnonymousFunction(a analyser, document *Document, node *phrase.Phrase) (HasTypes, bool) {
 	prevVariableTable := document.getCurrentVariableTable()
 	anonFunc := &AnonymousFunction{
-		location: document.GetNodeLocation(node),
+		location:    document.GetNodeLocation(node),
+		returnTypes: newTypeComposite(),
 	}
+	args, argIndex := document.argumentOf(node)
 	document.pushVariableTable(node)
 	document.pushBlock(anonFunc)
 	variableTable := document.getCurrentVariableTable()
@@ -32,9 +38,7 @@ func newAnonymousFunction(a analyser, document *Document, node *phrase.Phrase) S
 			switch p.Type {
 			case phrase.AnonymousFunctionHeader:
 				anonFunc.analyseHeader(a, document, p, variableTable, prevVariableTable)
-				for _, param := range anonFunc.Params {
-					variableTable.add(a, param.ToVariable(), document.NodeRange(p).End, true)
-				}
+				anonFunc.addParamsToVariableTable(a, variableTable, args, argIndex, document.NodeRange(p).End)
 			case phrase.FunctionDeclarationBody:
 				scanForChildren(a, document, p)
 			}
@@ -43,7 +47,7 @@ func newAnonymousFunction(a analyser, document *Document, node *phrase.Phrase) S
 	}
 	document.popVariableTable()
 	document.popBlock()
-	return anonFunc
+	return anonFunc, true
 }
 
 func (s *AnonymousFunction) analyseHeader(a analyser, document *Document, node *phrase.Phrase,
@@ -57,6 +61,8 @@ func (s *AnonymousFunction) analyseHeader(a analyser, document *Document, node *
 				s.analyseParameterDeclarationList(a, document, p)
 			case phrase.AnonymousFunctionUseClause:
 				s.analyseUseClause(a, document, p, variableTable, prevVariableTable)
+			case phrase.ReturnType:
+				s.returnTypes.merge(analyseReturnType(document, p))
 			}
 		}
 		child = traverser.Advance()
@@ -75,6 +81,37 @@ func (s *AnonymousFunction) analyseParameterDeclarationList(a analyser, document
 	}
 }
 
+// analyseReturnType returns the declared return types and adds the type
+// declarations as symbols
+func analyseReturnType(document *Document, node *phrase.Phrase) TypeComposite {
+	types := newTypeComposite()
+	traverser := util.NewTraverser(node)
+	for child := traverser.Advance(); child != nil; child = traverser.Advance() {
+		if p, ok := child.(*phrase.Phrase); ok && p.Type == phrase.TypeDeclaration {
+			typeDeclaration := newTypeDeclaration(document, p)
+			types.merge(typeDeclaration.Type)
+			document.addSymbol(typeDeclaration)
+		}
+	}
+	return types
+}
+
+// addParamsToVariableTable adds the parameters as variables, parameters without
+// types will take the types from the callable that the closure is passed to
+func (s *AnonymousFunction) addParamsToVariableTable(a analyser, variableTable *VariableTable,
+	args *ArgumentList, argIndex int, pos protocol.Position) {
+	for index, param := range s.Params {
+		variable := param.ToVariable()
+		if args != nil && param.Type.IsEmpty() {
+			variable.setExpression(newClosureParameter(variable.Location, args, argIndex, index))
+		}
+		variableTable.add(a, variable, pos, true)
+		if param.IsReference() {
+			variableTable.setReference(param.Name)
+		}
+	}
+}
+
 func (s *AnonymousFunction) analyseUseClause(a analyser, document *Document, node *phrase.Phrase,
 	variableTable *VariableTable, prevVariableTable *VariableTable) {
 	traverser := util.NewTraverser(node)
@@ -100,6 +137,11 @@ func (s *AnonymousFunction) analyseUseClause(a analyser, document *Document, nod
 								if prevVariable != nil {
 									variable.mergeTypesWithVariable(prevVariable)
 								}
+								if isUseByReference(p) {
+									// The variable is shared with the parent scope
+									variableTable.setReference(variable.Name)
+									prevVariableTable.setReference(variable.Name)
+								}
 							}
 						}
 						child = traverser.Advance()
@@ -116,10 +158,39 @@ func (s *AnonymousFunction) analyseUseClause(a analyser, document *Document, nod
 	}
 }
 
+func isUseByReference(node *phrase.Phrase) bool {
+	for _, child := range node.Children {
+		if token, ok := child.(*lexer.Token); ok && token.Type == lexer.Ampersand {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *AnonymousFunction) GetLocation() protocol.Location {
 	return s.location
 }
 
+// GetTypes returns Closure with the signature of the function
+func (s *AnonymousFunction) GetTypes() TypeComposite {
+	types := newTypeComposite()
+	typeString := NewTypeString("\\Closure")
+	typeString.signature = &CallableSignature{
+		Params:  s.Params,
+		Returns: s.returnTypes,
+	}
+	types.add(typeString)
+	return types
+}
+
+func (s *AnonymousFunction) Resolve(ctx ResolveContext) {
+}
+
+// GetReturnTypes returns the declared return types
+func (s *AnonymousFunction) GetReturnTypes() TypeComposite {
+	return s.returnTypes
+}
+
 func (s *AnonymousFunction) addChild(child Symbol) {
 	s.children = append(s.children, child)
 }
@@ -127,3 +198,165 @@ func (s *AnonymousFunction) addChild(child Symbol) {
 func (s *AnonymousFunction) GetChildren() []Symbol {
 	return s.children
 }
+
+func newArrowFunction(a analyser, document *Document, node *phrase.Phrase) (HasTypes, bool) {
+	prevVariableTable := document.getCurrentVariableTable()
+	arrowFunc := &AnonymousFunction{
+		location:    document.GetNodeLocation(node),
+		returnTypes: newTypeComposite(),
+	}
+	args, argIndex := document.argumentOf(node)
+	document.pushVariableTable(node)
+	document.pushBlock(arrowFunc)
+	variableTable := document.getCurrentVariableTable()
+	traverser := util.NewTraverser(node)
+	child := traverser.Advance()
+	for child != nil {
+		if p, ok := child.(*phrase.Phrase); ok {
+			if p.Type == phrase.ArrowFunctionHeader {
+				arrowFunc.analyseHeader(a, document, p, variableTable, prevVariableTable)
+				arrowFunc.addParamsToVariableTable(a, variableTable, args, argIndex, document.NodeRange(p).End)
+				child = traverser.Advance()
+				continue
+			}
+		}
+		scanNode(a, document, child)
+		child = traverser.Advance()
+	}
+	document.popVariableTable()
+	document.popBlock()
+	arrowFunc.captureVariables(variableTable, prevVariableTable)
+	return arrowFunc, true
+}
+
+// captureVariables links the variables that are not parameters to the
+// variables in the parent scope because arrow functions capture them by value
+func (s *AnonymousFunction) captureVariables(variableTable *VariableTable, prevVariableTable *VariableTable) {
+	start := s.location.Range.Start
+	params := map[string]struct{}{}
+	for _, param := range s.Params {
+		params[param.Name] = struct{}{}
+	}
+	for name, ctxVars := range variableTable.variables {
+		if _, ok := params[name]; ok {
+			continue
+		}
+		prevVariable := prevVariableTable.get(name, start)
+		if prevVariable == nil {
+			continue
+		}
+		prevVariableTable.markUsed(name, start)
+		for _, ctxVar := range ctxVars {
+			ctxVar.v.mergeTypesWithVariable(prevVariable)
+		}
+	}
+}
+
+// closureParameter resolves the types of a closure parameter without type
+// declaration from the callable signature of the parameter that the closure
+// is passed to, e.g. callable(Foo): void or array_map's array argument
+type closureParameter struct {
+	Expression
+	args        *ArgumentList
+	argIndex    int
+	paramIndex  int
+	hasResolved bool
+}
+
+var _ HasTypes = (*closureParameter)(nil)
+
+func newClosureParameter(location protocol.Location, args *ArgumentList, argIndex int, paramIndex int) *closureParameter {
+	return &closureParameter{
+		Expression: Expression{
+			Location: location,
+		},
+		args:       args,
+		argIndex:   argIndex,
+		paramIndex: paramIndex,
+	}
+}
+
+func (s *closureParameter) GetLocation() protocol.Location {
+	return s.Location
+}
+
+func (s *closureParameter) GetTypes() TypeComposite {
+	return s.Type
+}
+
+func (s *closureParameter) Resolve(ctx ResolveContext) {
+	if s.hasResolved {
+		return
+	}
+	s.hasResolved = true
+	hasTypes := ctx.document.hasTypesBeforePos(s.args.GetLocation().Range.Start)
+	if hasTypes == nil {
+		return
+	}
+	if functionCall, ok := hasTypes.(*FunctionCall); ok {
+		s.resolveArrayCallback(ctx, functionCall)
+	}
+	resolvable, ok := hasTypes.(HasParamsResolvable)
+	if !ok {
+		return
+	}
+	for _, hasParams := range resolvable.ResolveToHasParams(ctx) {
+		params := hasParams.GetParams()
+		if s.argIndex >= len(params) {
+			continue
+		}
+		for _, typeString := range params[s.argIndex].Type.Resolve() {
+			signature := typeString.GetSignature()
+			if signature == nil || s.paramIndex >= len(signature.Params) {
+				continue
+			}
+			s.Type.merge(signature.Params[s.paramIndex].Type)
+		}
+	}
+}
+
+type arrayCallback struct {
+	callbackIndex int
+	arrayIndex    int
+	paramIndexes  []int
+}
+
+// arrayCallbacks contains the functions which pass the elements of an array
+// argument to a callback
+var /* const */ arrayCallbacks = map[string]arrayCallback{
+	"\\array_map":    {callbackIndex: 0, arrayIndex: 1, paramIndexes: []int{0}},
+	"\\array_filter": {callbackIndex: 1, arrayIndex: 0, paramIndexes: []int{0}},
+	"\\array_walk":   {callbackIndex: 1, arrayIndex: 0, paramIndexes: []int{0}},
+	"\\array_reduce": {callbackIndex: 1, arrayIndex: 0, paramIndexes: []int{1}},
+	"\\usort":        {callbackIndex: 1, arrayIndex: 0, paramIndexes: []int{0, 1}},
+	"\\uasort":       {callbackIndex: 1, arrayIndex: 0, paramIndexes: []int{0, 1}},
+}
+
+func (s *closureParameter) resolveArrayCallback(ctx ResolveContext, functionCall *FunctionCall) {
+	importTable := ctx.document.ImportTableAtPos(functionCall.Location.Range.Start)
+	fqn := importTable.GetFunctionReferenceFQN(ctx.query, NewTypeString(functionCall.Name))
+	callback, ok := arrayCallbacks[fqn]
+	if !ok || callback.callbackIndex != s.argIndex {
+		return
+	}
+	isElement := false
+	for _, index := range callback.paramIndexes {
+		if index == s.paramIndex {
+			isElement = true
+			break
+		}
+	}
+	if !isElement {
+		return
+	}
+	array := s.args.argumentHasTypes(ctx.document, callback.arrayIndex)
+	if array == nil {
+		return
+	}
+	array.Resolve(ctx)
+	for _, typeString := range array.GetTypes().Resolve() {
+		if element, ok := typeString.Dearray(); ok {
+			s.Type.add(element)
+		}
+	}
+}
@@ -38,3 +38,42 @@ func TestAnonymousFunction(t *testing.T) {
 	variable2 := variableTable.get("$var1", protocol.Position{Line: 10, Character: 32})
 	assert.Equal(t, "\\Schema", variable2.GetTypes().ToString())
 }
+
+func TestClosureParameterTypes(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		document := indexDocumentAndGet(store, "../cases/callableSignature.php", "test1")
+		ctx := NewResolveContext(NewQuery(store), document)
+		typesOf := func(name string, pos protocol.Position) string {
+			variable := document.GetVariableTableAt(pos).get(name, pos)
+			if variable == nil {
+				return ""
+			}
+			variable.Resolve(ctx)
+			return variable.GetTypes().ToString()
+		}
+		assert.Equal(t, "\\User", typesOf("$u", protocol.Position{Line: 27, Character: 33}))
+		assert.Equal(t, "\\User", typesOf("$item", protocol.Position{Line: 30, Character: 10}))
+		assert.Equal(t, "int", typesOf("$index", protocol.Position{Line: 30, Character: 10}))
+		assert.Equal(t, "\\Closure(\\User $user, int $times = 1): string",
+			typesOf("$fn", protocol.Position{Line: 35, Character: 0}))
+
+		args, resolvable := document.ArgumentListAndFunctionCallAt(protocol.Position{Line: 34, Character: 4})
+		assert.NotNil(t, args)
+		hasParams := resolvable.ResolveToHasParams(ctx)
+		assert.Equal(t, 1, len(hasParams))
+		assert.Equal(t, "$fn", hasParams[0].GetNameLabel())
+		assert.Equal(t, 2, len(hasParams[0].GetParams()))
+
+		tra := newTraverser()
+		tra.traverseDocument(document, func(tra *traverser, s Symbol, _ []Symbol) {
+			if m, ok := s.(*Method); ok && m.Name == "each" {
+				assert.Equal(t, "Called for each user", m.Params[0].GetDescription())
+			}
+		})
+		methods := store.GetMethods("\\Collection", "each")
+		assert.Equal(t, 1, len(methods))
+		assert.Equal(t, "\\Closure(\\User, int): void", methods[0].Params[0].Type.ToString())
+		found := store.GetMethods("\\Collection", "finder")
+		assert.Equal(t, "callable(int|string $key): \\User|null", found[0].GetReturnTypes().ToString())
+	})
+}
@@ -0,0 +1,221 @@
+package analysis
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/john-nguyen09/go-phpparser/phrase"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/stub"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// ArgumentDiagnostics returns the diagnostics for the arguments which do not
+// match the parameters of the callee, a call with several callees is only
+// reported when none of them accepts the arguments
+func ArgumentDiagnostics(ctx ResolveContext) []protocol.Diagnostic {
+	defer util.TimeTrack(time.Now(), "ArgumentDiagnostics")
+	diagnostics := []protocol.Diagnostic{}
+	traverseCalls(ctx.document, func(argumentList *ArgumentList, callee HasParamsResolvable) {
+		diagnostics = append(diagnostics, candidatesDiagnostics(ctx.resolveCallee(callee), func(candidate HasParams) []protocol.Diagnostic {
+			return candidateArgumentsDiagnostics(ctx.document, argumentList, candidate)
+		})...)
+	})
+	return diagnostics
+}
+
+// traverseCalls calls fn with the argument lists and their callees
+func traverseCalls(doc *Document, fn func(*ArgumentList, HasParamsResolvable)) {
+	var lastHasTypes HasTypes
+	TraverseDocument(doc, func(s Symbol) {
+		if argumentList, ok := s.(*ArgumentList); ok {
+			if resolvable, ok := lastHasTypes.(HasParamsResolvable); ok && isCalleeOf(doc, lastHasTypes, argumentList) {
+				fn(argumentList, resolvable)
+			}
+		}
+		if hasTypes, ok := s.(HasTypes); ok {
+			lastHasTypes = hasTypes
+		}
+	}, nil)
+}
+
+// isCalleeOf checks whether the argument list directly follows the callee
+func isCalleeOf(doc *Document, callee HasTypes, argumentList *ArgumentList) bool {
+	start := doc.OffsetAtPosition(callee.GetLocation().Range.End)
+	end := doc.OffsetAtPosition(argumentList.GetLocation().Range.Start)
+	if start > end {
+		return false
+	}
+	return len(bytes.TrimSpace(doc.text[start:end])) == 0
+}
+
+// candidatesDiagnostics returns the diagnostics of the first candidate
+// if every candidate has diagnostics
+func candidatesDiagnostics(candidates []HasParams, fn func(HasParams) []protocol.Diagnostic) []protocol.Diagnostic {
+	var diagnostics []protocol.Diagnostic
+	for i, candidate := range candidates {
+		results := fn(candidate)
+		if len(results) == 0 {
+			return nil
+		}
+		if i == 0 {
+			diagnostics = results
+		}
+	}
+	return diagnostics
+}
+
+func candidateArgumentsDiagnostics(doc *Document, argumentList *ArgumentList, hasParams HasParams) []protocol.Diagnostic {
+	create := func(r protocol.Range, code string, message string) protocol.Diagnostic {
+		return newDiagnostic(code, r, message)
+	}
+	diagnostics := []protocol.Diagnostic{}
+	params := hasParams.GetParams()
+	label := calleeLabel(hasParams)
+	// The parameter names of the stubs are not the names of PHP 8,
+	// so the named arguments of stub callees are not checked against them
+	isStub := false
+	if s, ok := hasParams.(Symbol); ok {
+		isStub = stub.IsStub(s.GetLocation().URI)
+	}
+	isVariadic := len(params) > 0 && params[len(params)-1].IsVariadic()
+	provided := make([]bool, len(params))
+	ranges := argumentList.GetArgumentRanges()
+	names := map[string]struct{}{}
+	hasNamed := false
+	isUnpacked := false
+	positionals := 0
+	var extraRange *protocol.Range
+	for i, argument := range argumentList.GetArguments() {
+		if i >= len(ranges) {
+			break
+		}
+		if p, ok := argument.(*phrase.Phrase); ok && p.Type == phrase.VariadicUnpacking {
+			isUnpacked = true
+			continue
+		}
+		name := argumentList.ArgumentName(i)
+		if name == "" {
+			if hasNamed {
+				diagnostics = append(diagnostics, create(ranges[i], CodeNamedArgument,
+					"Cannot use positional argument after named argument."))
+				continue
+			}
+			if positionals < len(params) && !params[positionals].IsVariadic() {
+				provided[positionals] = true
+			} else if positionals >= len(params) && !isVariadic {
+				if extraRange == nil {
+					extraRange = &protocol.Range{Start: ranges[i].Start}
+				}
+				extraRange.End = ranges[i].End
+			}
+			positionals++
+			continue
+		}
+		hasNamed = true
+		nameRange := argumentList.nameRange(doc, i)
+		if _, ok := names[name]; ok {
+			diagnostics = append(diagnostics, create(nameRange, CodeNamedArgument, "Duplicate named argument $"+name+"."))
+			continue
+		}
+		names[name] = struct{}{}
+		if isStub {
+			continue
+		}
+		index := paramIndex(params, name)
+		if index < 0 || params[index].IsVariadic() {
+			if !isVariadic {
+				diagnostics = append(diagnostics, create(nameRange, CodeNamedArgument, "Unknown named argument $"+name+" for "+label+"."))
+			}
+			continue
+		}
+		if provided[index] {
+			diagnostics = append(diagnostics, create(nameRange, CodeNamedArgument,
+				"Named argument $"+name+" overwrites previous argument."))
+			continue
+		}
+		provided[index] = true
+	}
+	if extraRange != nil && !usesFuncGetArgs(hasParams) {
+		expected := strconv.Itoa(len(params))
+		if countRequiredParams(params) < len(params) {
+			expected = "at most " + expected
+		}
+		diagnostics = append(diagnostics, create(*extraRange, CodeTooManyArguments,
+			"Too many arguments to "+label+", expected "+expected+" but got "+strconv.Itoa(positionals)+"."))
+	}
+	if isUnpacked {
+		return diagnostics
+	}
+	required := countRequiredParams(params)
+	if !hasNamed {
+		// Positional arguments are only counted because the stubs
+		// can have optional parameters before the required ones
+		if positionals < required {
+			expected := strconv.Itoa(required)
+			if len(params) > required {
+				expected = "at least " + expected
+			}
+			diagnostics = append(diagnostics, create(argumentList.GetLocation().Range, CodeMissingArgument,
+				"Too few arguments to "+label+", expected "+expected+" but got "+strconv.Itoa(positionals)+"."))
+		}
+		return diagnostics
+	}
+	if isStub {
+		return diagnostics
+	}
+	missing := []string{}
+	for i, param := range params {
+		if !provided[i] && !param.IsOptional() {
+			missing = append(missing, param.Name)
+		}
+	}
+	if len(missing) > 0 {
+		diagnostics = append(diagnostics, create(argumentList.GetLocation().Range, CodeMissingArgument,
+			"Missing argument "+strings.Join(missing, ", ")+" for "+label+"."))
+	}
+	return diagnostics
+}
+
+func calleeLabel(hasParams HasParams) string {
+	switch v := hasParams.(type) {
+	case *Function:
+		return v.Name.GetOriginal() + "()"
+	case *Method:
+		return v.Scope.GetOriginal() + "::" + v.Name + "()"
+	}
+	return hasParams.GetNameLabel() + "()"
+}
+
+func paramIndex(params []*Parameter, name string) int {
+	for i, param := range params {
+		if param.Name == "$"+name {
+			return i
+		}
+	}
+	return -1
+}
+
+func countRequiredParams(params []*Parameter) int {
+	count := 0
+	for _, param := range params {
+		if !param.IsOptional() {
+			count++
+		}
+	}
+	return count
+}
+
+// usesFuncGetArgs checks whether the callee reads its arguments with func_get_args()
+// or similar, so that it can take more arguments than its parameters
+func usesFuncGetArgs(hasParams HasParams) bool {
+	switch v := hasParams.(type) {
+	case *Function:
+		return v.usesFuncGetArgs
+	case *Method:
+		return v.usesFuncGetArgs
+	}
+	return false
+}
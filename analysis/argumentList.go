@@ -1,6 +1,9 @@
 package analysis
 
 import (
+	"bytes"
+	"regexp"
+
 	"github.com/john-nguyen09/go-phpparser/lexer"
 	"github.com/john-nguyen09/go-phpparser/phrase"
 	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
@@ -15,12 +18,17 @@ type ArgumentList struct {
 
 	arguments      []phrase.AstNode
 	argumentRanges []protocol.Range
+	argumentNames  []string
 	ranges         []protocol.Range
 }
 
 var _ BlockSymbol = (*ArgumentList)(nil)
 var IgnoreTokenSet mapset.Set[lexer.TokenType]
 
+// namedArgumentPattern matches the text before the value of a named argument, the names
+// are not supported by the parser so they are blanked out and read from the original text
+var /* const */ namedArgumentPattern = regexp.MustCompile(`^\(?\s*([a-zA-Z_\x80-\xff][a-zA-Z0-9_\x80-\xff]*)\s*:\s*$`)
+
 func init() {
 	ignoreTokens := []lexer.TokenType{
 		lexer.Whitespace,
@@ -70,8 +78,20 @@ func newArgumentList(a analyser, document *Document, node *phrase.Phrase) Symbol
 		Start: start,
 		End:   argumentList.location.Range.End,
 	})
-	for _, argument := range argumentList.GetArguments() {
-		argumentList.argumentRanges = append(argumentList.argumentRanges, document.NodeRange(argument))
+	for i, argument := range argumentList.GetArguments() {
+		argumentRange := document.NodeRange(argument)
+		argumentList.argumentRanges = append(argumentList.argumentRanges, argumentRange)
+		name := ""
+		if i < len(argumentList.ranges) {
+			text := document.text[document.OffsetAtPosition(argumentList.ranges[i].Start):document.OffsetAtPosition(argumentRange.Start)]
+			if match := namedArgumentPattern.FindSubmatch(text); match != nil {
+				name = string(match[1])
+			}
+		}
+		argumentList.argumentNames = append(argumentList.argumentNames, name)
+		if p, ok := argument.(*phrase.Phrase); ok && p.Type == phrase.SimpleVariable && !isVariableVariable(p) {
+			document.getCurrentVariableTable().addArgument(document.GetNodeText(p), argumentRange.Start)
+		}
 	}
 	for _, n := range nodesToScan {
 		scanNode(a, document, n)
@@ -97,6 +117,43 @@ func (s *ArgumentList) GetArgumentRanges() []protocol.Range {
 	return s.argumentRanges
 }
 
+// argumentHasTypes returns the expression of the argument at the given index
+func (s *ArgumentList) argumentHasTypes(document *Document, index int) HasTypes {
+	if index >= len(s.argumentRanges) {
+		return nil
+	}
+	argumentRange := s.argumentRanges[index]
+	hasTypes := document.hasTypesBeforePos(argumentRange.End)
+	if hasTypes == nil || protocol.IsInRange(hasTypes.GetLocation().Range.Start, argumentRange) != 0 {
+		return nil
+	}
+	return hasTypes
+}
+
+// ArgumentName returns the name of the argument at the given index
+// or an empty string if the argument is positional
+func (s *ArgumentList) ArgumentName(index int) string {
+	if index >= len(s.argumentNames) {
+		return ""
+	}
+	return s.argumentNames[index]
+}
+
+// nameRange returns the range of the name of the named argument at the given index
+func (s *ArgumentList) nameRange(document *Document, index int) protocol.Range {
+	name := s.ArgumentName(index)
+	if name == "" || index >= len(s.ranges) {
+		return s.argumentRanges[index]
+	}
+	start := document.OffsetAtPosition(s.ranges[index].Start)
+	end := document.OffsetAtPosition(s.argumentRanges[index].Start)
+	offset := start + bytes.Index(document.text[start:end], []byte(name))
+	return protocol.Range{
+		Start: document.positionAt(offset),
+		End:   document.positionAt(offset + len(name)),
+	}
+}
+
 func (s *ArgumentList) GetRanges() []protocol.Range {
 	return s.ranges
 }
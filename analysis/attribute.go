@@ -0,0 +1,225 @@
+package analysis
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/john-nguyen09/go-phpparser/lexer"
+	"github.com/john-nguyen09/go-phpparser/parser"
+	"github.com/john-nguyen09/go-phpparser/phrase"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// AttributeFQN is the attribute which marks a class as an attribute
+const AttributeFQN = "\\Attribute"
+
+var /* const */ identifierPattern = regexp.MustCompile(`^[a-zA-Z_\x80-\xff][a-zA-Z0-9_\x80-\xff]*$`)
+
+// Attribute represents an attribute of a declaration, e.g. #[Route('/path')]
+type Attribute struct {
+	Expression
+
+	argumentsRange protocol.Range
+	hasArguments   bool
+}
+
+var _ HasTypes = (*Attribute)(nil)
+var _ HasParamsResolvable = (*Attribute)(nil)
+
+func newAttribute(a analyser, document *Document, node *phrase.Phrase) *Attribute {
+	attribute := &Attribute{}
+	traverser := util.NewTraverser(node)
+	for child := traverser.Advance(); child != nil; child = traverser.Advance() {
+		if v, ok := child.(*phrase.Phrase); ok {
+			switch v.Type {
+			case phrase.ClassTypeDesignator:
+				attribute.analyseName(document, v)
+				document.addSymbol(attribute)
+			case phrase.ArgumentExpressionList:
+				attribute.analyseArgumentsRange(document, v)
+				newArgumentList(a, document, v)
+			}
+		}
+	}
+	return attribute
+}
+
+func (s *Attribute) analyseArgumentsRange(document *Document, node *phrase.Phrase) {
+	for _, child := range node.Children {
+		if t, ok := child.(*lexer.Token); ok {
+			switch t.Type {
+			case lexer.OpenParenthesis:
+				s.hasArguments = true
+				s.argumentsRange.Start = document.positionAt(t.Offset + t.Length)
+			case lexer.CloseParenthesis:
+				s.argumentsRange.End = document.positionAt(t.Offset)
+			}
+		}
+	}
+}
+
+func (s *Attribute) analyseName(document *Document, node *phrase.Phrase) {
+	s.Location = document.GetNodeLocation(node)
+	for _, child := range node.Children {
+		if p, ok := child.(*phrase.Phrase); ok && (p.Type == phrase.QualifiedName || p.Type == phrase.FullyQualifiedName) {
+			typeString := transformQualifiedName(p, document)
+			typeString.SetFQN(document.currImportTable().GetClassReferenceFQN(typeString))
+			s.Name = typeString.GetOriginal()
+			s.Type.add(typeString)
+		}
+	}
+}
+
+func (s *Attribute) GetLocation() protocol.Location {
+	return s.Location
+}
+
+func (s *Attribute) GetTypes() TypeComposite {
+	return s.Type
+}
+
+// IsInArguments returns whether the position is inside the parentheses of the attribute
+func (s *Attribute) IsInArguments(pos protocol.Position) bool {
+	if !s.hasArguments {
+		return false
+	}
+	return isInRangeInclusive(pos, s.argumentsRange)
+}
+
+// IsInName returns whether the position is at the name of the attribute
+func (s *Attribute) IsInName(pos protocol.Position) bool {
+	return isInRangeInclusive(pos, s.Location.Range)
+}
+
+func isInRangeInclusive(pos protocol.Position, theRange protocol.Range) bool {
+	return protocol.IsInRange(pos, theRange) == 0 || protocol.ComparePos(pos, theRange.End) == 0
+}
+
+// ResolveToHasParams returns the constructors of the attribute classes
+func (s *Attribute) ResolveToHasParams(ctx ResolveContext) []HasParams {
+	hasParams := []HasParams{}
+	q := ctx.query
+	for _, typeString := range s.GetTypes().Resolve() {
+		for _, class := range q.GetClasses(typeString.GetFQN()) {
+			constructor := q.GetClassConstructor(class)
+			if constructor.Method != nil {
+				hasParams = append(hasParams, constructor.Method)
+			}
+		}
+	}
+	return hasParams
+}
+
+// parseAttributeGroups parses the attribute groups as object creations, e.g.
+// #[Route('/path', name: 'home'), Cache] is parsed as new Route('/path', 'home'); new Cache;
+// the names of the named arguments are blanked out so they are read from the original text
+func parseAttributeGroups(source []byte, groups []attributeGroup) []*phrase.Phrase {
+	base := make([]byte, len(source))
+	blankRange(base, 0, len(base))
+	for i, c := range source {
+		if c == '\n' || c == '\r' {
+			base[i] = c
+		}
+	}
+	copy(base, "<?php")
+	for _, group := range groups {
+		copy(base[group.start+len(attributeGroupStart):group.end], source[group.start+len(attributeGroupStart):group.end])
+		if source[group.end-1] == ']' {
+			base[group.end-1] = ';'
+		}
+	}
+	tokens := significantTokens(base)
+	inserts := []sourceEdit{}
+	depth := 0
+	expectName := true
+	for i, t := range tokens {
+		switch t.Type {
+		case lexer.OpenParenthesis, lexer.OpenBracket, lexer.OpenBrace:
+			depth++
+		case lexer.CloseParenthesis, lexer.CloseBracket, lexer.CloseBrace:
+			depth--
+		case lexer.Semicolon:
+			depth = 0
+			expectName = true
+		case lexer.Comma:
+			if depth == 0 {
+				base[t.Offset] = ';'
+				expectName = true
+			}
+		case lexer.Name, lexer.Backslash, lexer.Namespace:
+			if depth == 0 && expectName {
+				inserts = append(inserts, sourceEdit{t.Offset, 0, "new "})
+				expectName = false
+			}
+		}
+		if depth > 0 && i > 0 && i+1 < len(tokens) && isNamedArgument(base, tokens[i-1], t, tokens[i+1]) {
+			blankRange(base, t.Offset, tokens[i+1].Offset+tokens[i+1].Length)
+		}
+	}
+	rootNode := parser.Parse(applyEdits(base, inserts))
+	restoreOffsets(rootNode, inserts, map[*lexer.Token]struct{}{})
+	nodes := []*phrase.Phrase{}
+	for _, child := range rootNode.Children {
+		if p, ok := child.(*phrase.Phrase); ok && p.Type == phrase.ExpressionStatement {
+			for _, c := range p.Children {
+				if objectCreation, ok := c.(*phrase.Phrase); ok && objectCreation.Type == phrase.ObjectCreationExpression {
+					nodes = append(nodes, objectCreation)
+				}
+			}
+		}
+	}
+	return nodes
+}
+
+// analyseAttributes analyses the attributes before the offset, the attributes
+// are not in the syntax tree so they are analysed before the next symbol
+func (s *Document) analyseAttributes(offset int) {
+	for s.attributeIndex < len(s.attributeNodes) {
+		node := s.attributeNodes[s.attributeIndex]
+		if util.FirstToken(node).Offset >= offset {
+			break
+		}
+		s.attributeIndex++
+		s.attributes = append(s.attributes, newAttribute(newAnalyser(), s, node))
+	}
+}
+
+// skipAttributeGroups returns the start of the attribute groups which
+// are right before the offset, or the offset if there is none
+func (s *Document) skipAttributeGroups(offset int) int {
+	for i := len(s.attributeGroups) - 1; i >= 0; i-- {
+		group := s.attributeGroups[i]
+		if group.end > offset {
+			continue
+		}
+		if len(bytes.TrimSpace(s.text[group.end:offset])) > 0 {
+			break
+		}
+		offset = group.start
+	}
+	return offset
+}
+
+// attributesBefore returns the attributes which are declared right before the offset
+func (s *Document) attributesBefore(offset int) []*Attribute {
+	start := s.skipAttributeGroups(offset)
+	results := []*Attribute{}
+	for _, attribute := range s.attributes {
+		attributeOffset := s.OffsetAtPosition(attribute.Location.Range.Start)
+		if attributeOffset >= start && attributeOffset < offset {
+			results = append(results, attribute)
+		}
+	}
+	return results
+}
+
+// AttributeAt returns the attribute at the position
+func (s *Document) AttributeAt(pos protocol.Position) *Attribute {
+	for _, attribute := range s.attributes {
+		if attribute.IsInName(pos) || attribute.IsInArguments(pos) {
+			return attribute
+		}
+	}
+	return nil
+}
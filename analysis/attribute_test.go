@@ -0,0 +1,113 @@
+package analysis
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttribute(t *testing.T) {
+	data, err := ioutil.ReadFile("../cases/attribute.php")
+	if err != nil {
+		panic(err)
+	}
+	document := NewDocument("test1", data)
+	document.Load()
+	assert.Empty(t, GetParserDiagnostics(document))
+
+	fqns := []string{}
+	for _, attribute := range document.attributes {
+		fqns = append(fqns, attribute.GetTypes().ToString())
+	}
+	assert.Equal(t, []string{
+		"\\Attribute",
+		"\\App\\Attributes\\Route",
+		"\\App\\Attributes\\Route",
+		"\\App\\Controller\\Cache",
+		"\\SensitiveParameter",
+		"\\App\\Attributes\\Route",
+	}, fqns)
+
+	methods := map[string]*Method{}
+	argumentNames := []string{}
+	tr := newTraverser()
+	tr.traverseDocument(document, func(_ *traverser, s Symbol, _ []Symbol) {
+		switch v := s.(type) {
+		case *Method:
+			methods[v.Name] = v
+		case *ArgumentList:
+			for i := range v.GetArguments() {
+				if name := v.ArgumentName(i); name != "" {
+					argumentNames = append(argumentNames, name)
+				}
+			}
+		}
+	})
+	assert.Contains(t, methods, "show")
+	if assert.Contains(t, methods, "index") {
+		assert.Equal(t, "Lists the users", methods["index"].description)
+	}
+	assert.Equal(t, []string{"name", "methods", "maxAge", "name"}, argumentNames)
+
+	attribute := document.AttributeAt(protocol.Position{Line: 19, Character: 58})
+	assert.NotNil(t, attribute)
+	assert.Equal(t, "\\App\\Controller\\Cache", attribute.GetTypes().ToString())
+	assert.True(t, attribute.IsInName(protocol.Position{Line: 19, Character: 58}))
+	attribute = document.AttributeAt(protocol.Position{Line: 19, Character: 70})
+	assert.NotNil(t, attribute)
+	assert.True(t, attribute.IsInArguments(protocol.Position{Line: 19, Character: 70}))
+	assert.Nil(t, document.AttributeAt(protocol.Position{Line: 20, Character: 10}))
+}
+
+func TestAttributeClassesAndReferences(t *testing.T) {
+	data, err := ioutil.ReadFile("../cases/attribute.php")
+	if err != nil {
+		panic(err)
+	}
+	stubData, err := ioutil.ReadFile("../stub/phpintel-stubs/Core/attribute.php")
+	if err != nil {
+		panic(err)
+	}
+	withTestStore("test", t.Name(), func(store *Store) {
+		stubDoc := NewDocument("stub", stubData)
+		stubDoc.Load()
+		store.SyncDocument(stubDoc)
+		doc := NewDocument("test1", data)
+		doc.Load()
+		store.SyncDocument(doc)
+		q := NewQuery(store)
+
+		attributes := q.GetClasses(AttributeFQN)
+		assert.Equal(t, 1, len(attributes))
+		assert.True(t, attributes[0].IsAttribute())
+		caches := q.GetClasses("\\App\\Controller\\Cache")
+		assert.Equal(t, 1, len(caches))
+		assert.True(t, caches[0].IsAttribute())
+		controllers := q.GetClasses("\\App\\Controller\\UserController")
+		assert.Equal(t, 1, len(controllers))
+		assert.False(t, controllers[0].IsAttribute())
+
+		assert.ElementsMatch(t, []protocol.Location{
+			{URI: "test1", Range: protocol.Range{
+				Start: protocol.Position{Line: 13, Character: 2},
+				End:   protocol.Position{Line: 13, Character: 7},
+			}},
+			{URI: "test1", Range: protocol.Range{
+				Start: protocol.Position{Line: 19, Character: 6},
+				End:   protocol.Position{Line: 19, Character: 11},
+			}},
+			{URI: "test1", Range: protocol.Range{
+				Start: protocol.Position{Line: 25, Character: 6},
+				End:   protocol.Position{Line: 25, Character: 11},
+			}},
+		}, store.GetReferences("\\App\\Attributes\\Route"))
+
+		ctx := NewResolveContext(q, doc)
+		attribute := doc.AttributeAt(protocol.Position{Line: 19, Character: 70})
+		hasParams := attribute.ResolveToHasParams(ctx)
+		assert.Equal(t, 1, len(hasParams))
+		assert.Equal(t, "maxAge", hasParams[0].GetParams()[0].Name[1:])
+	})
+}
@@ -0,0 +1,236 @@
+package analysis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+// BaselineFileName is the default name of the baseline file at the root of the workspace
+const BaselineFileName = "phpintel-baseline.json"
+
+// CodeStaleBaselineEntry is the code of the diagnostic for the baseline entries
+// which no longer match any diagnostic and can be removed
+const CodeStaleBaselineEntry = "stale-baseline-entry"
+
+const baselineVersion = 1
+
+// BaselineEntry is an existing diagnostic of a file, it is identified by
+// the fingerprint rather than the line so it survives unrelated changes
+type BaselineEntry struct {
+	File        string `json:"file"`
+	Code        string `json:"code,omitempty"`
+	Message     string `json:"message"`
+	Fingerprint string `json:"fingerprint"`
+	Count       int    `json:"count"`
+}
+
+type baselineFile struct {
+	Version int             `json:"version"`
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// Baseline contains the existing diagnostics which are hidden from the
+// results, the files are relative to the root of the workspace
+type Baseline struct {
+	mu      sync.Mutex
+	entries map[string]map[string]*BaselineEntry
+}
+
+// NewBaseline creates an empty baseline
+func NewBaseline() *Baseline {
+	return &Baseline{
+		entries: map[string]map[string]*BaselineEntry{},
+	}
+}
+
+// ReadBaseline reads the baseline from the file
+func ReadBaseline(path string) (*Baseline, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file baselineFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	b := NewBaseline()
+	for _, entry := range file.Entries {
+		b.add(entry)
+	}
+	return b, nil
+}
+
+// Write writes the baseline to the file, the entries are sorted so the
+// file has a stable diff in the version control
+func (b *Baseline) Write(path string) error {
+	b.mu.Lock()
+	file := baselineFile{
+		Version: baselineVersion,
+		Entries: []BaselineEntry{},
+	}
+	for _, entries := range b.entries {
+		for _, entry := range entries {
+			file.Entries = append(file.Entries, *entry)
+		}
+	}
+	b.mu.Unlock()
+	sort.Slice(file.Entries, func(i, j int) bool {
+		a, b := file.Entries[i], file.Entries[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		if a.Message != b.Message {
+			return a.Message < b.Message
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func (b *Baseline) add(entry BaselineEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	entries, ok := b.entries[entry.File]
+	if !ok {
+		entries = map[string]*BaselineEntry{}
+		b.entries[entry.File] = entries
+	}
+	if existing, ok := entries[entry.Fingerprint]; ok {
+		existing.Count += entry.Count
+		return
+	}
+	entries[entry.Fingerprint] = &entry
+}
+
+// Add records the diagnostics of the document as existing
+func (b *Baseline) Add(file string, document *Document, diagnostics []protocol.Diagnostic) {
+	for _, diagnostic := range diagnostics {
+		b.add(BaselineEntry{
+			File:        file,
+			Code:        DiagnosticCode(diagnostic),
+			Message:     diagnostic.Message,
+			Fingerprint: DiagnosticFingerprint(document, diagnostic),
+			Count:       1,
+		})
+	}
+}
+
+// Filter removes the diagnostics of the document which are in the baseline
+// and returns the baseline entries of the file which are not matched
+func (b *Baseline) Filter(file string, document *Document, diagnostics []protocol.Diagnostic) ([]protocol.Diagnostic, []BaselineEntry) {
+	b.mu.Lock()
+	remaining := map[string]int{}
+	for fingerprint, entry := range b.entries[file] {
+		remaining[fingerprint] = entry.Count
+	}
+	b.mu.Unlock()
+	results := []protocol.Diagnostic{}
+	for _, diagnostic := range diagnostics {
+		fingerprint := DiagnosticFingerprint(document, diagnostic)
+		if remaining[fingerprint] > 0 {
+			remaining[fingerprint]--
+			continue
+		}
+		results = append(results, diagnostic)
+	}
+	stale := []BaselineEntry{}
+	b.mu.Lock()
+	for fingerprint, count := range remaining {
+		if count > 0 {
+			entry := *b.entries[file][fingerprint]
+			entry.Count = count
+			stale = append(stale, entry)
+		}
+	}
+	b.mu.Unlock()
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Message < stale[j].Message
+	})
+	return results, stale
+}
+
+// Files returns the files which have entries in the baseline
+func (b *Baseline) Files() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	files := []string{}
+	for file := range b.entries {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Entries returns the entries of the file
+func (b *Baseline) Entries(file string) []BaselineEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	entries := []BaselineEntry{}
+	for _, entry := range b.entries[file] {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Message < entries[j].Message
+	})
+	return entries
+}
+
+// StaleDiagnostics returns the diagnostics for the stale baseline entries,
+// they are reported at the start of the file as the entries have no position
+func StaleDiagnostics(stale []BaselineEntry) []protocol.Diagnostic {
+	diagnostics := []protocol.Diagnostic{}
+	for _, entry := range stale {
+		message := "Baseline entry is no longer reported and can be removed: " + entry.Message
+		if entry.Code != "" {
+			message += " [" + entry.Code + "]"
+		}
+		if entry.Count > 1 {
+			message += " (" + strconv.Itoa(entry.Count) + " times)"
+		}
+		diagnostics = append(diagnostics, newDiagnostic(CodeStaleBaselineEntry, protocol.Range{}, message))
+	}
+	return diagnostics
+}
+
+// DiagnosticFingerprint returns a hash of the code and message of the
+// diagnostic with the code of its line and range, whitespaces are collapsed
+// so the fingerprint does not change when the code is moved or reindented
+func DiagnosticFingerprint(document *Document, diagnostic protocol.Diagnostic) string {
+	r := diagnostic.Range
+	text := document.GetText()
+	line := document.lineText(r.Start.Line)
+	rangeStart, rangeEnd := document.OffsetAtPosition(r.Start), document.OffsetAtPosition(r.End)
+	rangeText := ""
+	if rangeStart <= rangeEnd && rangeEnd <= len(text) {
+		rangeText = string(text[rangeStart:rangeEnd])
+	}
+	hash := sha256.New()
+	for _, part := range []string{DiagnosticCode(diagnostic), diagnostic.Message, line, rangeText} {
+		hash.Write([]byte(strings.Join(strings.Fields(part), " ")))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// DiagnosticCode returns the code of the diagnostic or empty if it has none
+func DiagnosticCode(diagnostic protocol.Diagnostic) string {
+	if code, ok := diagnostic.Code.(string); ok {
+		return code
+	}
+	return ""
+}
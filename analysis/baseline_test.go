@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseline(t *testing.T) {
+	data, err := ioutil.ReadFile("../cases/undefinedVariable.php")
+	if err != nil {
+		panic(err)
+	}
+	withTestStore("test", t.Name(), func(store *Store) {
+		diagnosticsOf := func(data []byte) (*Document, []protocol.Diagnostic) {
+			doc := NewDocument("test1", data)
+			doc.Load()
+			store.SyncDocument(doc)
+			return doc, UndefinedVariableDiagnostics(NewResolveContext(NewQuery(store), doc))
+		}
+		filter := func(baseline *Baseline, data []byte) (int, []BaselineEntry) {
+			doc, diagnostics := diagnosticsOf(data)
+			diagnostics, stale := baseline.Filter("src/undefinedVariable.php", doc, diagnostics)
+			return len(diagnostics), stale
+		}
+
+		doc, diagnostics := diagnosticsOf(data)
+		assert.Len(t, diagnostics, 11)
+		baseline := NewBaseline()
+		baseline.Add("src/undefinedVariable.php", doc, diagnostics)
+		path := filepath.Join(t.TempDir(), BaselineFileName)
+		assert.NoError(t, baseline.Write(path))
+		baseline, err := ReadBaseline(path)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"src/undefinedVariable.php"}, baseline.Files())
+
+		// moving and reindenting the code keeps the fingerprints
+		moved := bytes.Replace(data, []byte("<?php\n"), []byte("<?php\n\n\n"), 1)
+		moved = bytes.Replace(moved, []byte("    return $totla"), []byte("        return $totla"), 1)
+		remaining, stale := filter(baseline, moved)
+		assert.Equal(t, 0, remaining)
+		assert.Empty(t, stale)
+
+		fixed := bytes.Replace(data, []byte("$totla"), []byte("$total"), 1)
+		added := bytes.Replace(fixed, []byte("echo $collected;"), []byte("echo $collected, $missing;"), 1)
+		remaining, stale = filter(baseline, added)
+		assert.Equal(t, 1, remaining)
+		assert.Len(t, stale, 1)
+		assert.Equal(t, CodeUndefinedVariable, stale[0].Code)
+		assert.Equal(t, "Undefined variable $totla.", stale[0].Message)
+		diagnostics = StaleDiagnostics(stale)
+		assert.Equal(t, CodeStaleBaselineEntry, diagnostics[0].Code)
+		assert.Equal(t, "Baseline entry is no longer reported and can be removed: Undefined variable $totla. [undefined-variable]", diagnostics[0].Message)
+	})
+}
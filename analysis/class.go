@@ -22,8 +22,12 @@ type Class struct {
 	Extends    TypeString
 	Interfaces []TypeString
 	Use        []TypeString
+	Mixins     []TypeString
 
 	deprecatedTag *tag
+	enum          *Enum
+	isAttribute   bool
+	isGuarded     bool
 }
 
 var _ HasScope = (*Class)(nil)
@@ -62,7 +66,8 @@ func getMemberModifier(node *phrase.Phrase) (VisibilityModifierValue, bool, Clas
 
 func newClass(a analyser, document *Document, node *phrase.Phrase) Symbol {
 	class := &Class{
-		Location: document.GetNodeLocation(node),
+		Location:  document.GetNodeLocation(node),
+		isGuarded: isExistenceGuarded(a, document),
 	}
 	document.addClass(class)
 	phpDoc := document.getValidPhpDoc(class.Location)
@@ -70,6 +75,11 @@ func newClass(a analyser, document *Document, node *phrase.Phrase) Symbol {
 	child := traverser.Advance()
 	document.addSymbol(class)
 	document.pushBlock(class)
+	for _, attribute := range document.attributesBefore(document.OffsetAtPosition(class.Location.Range.Start)) {
+		if attribute.Type.hasFQN(AttributeFQN) {
+			class.isAttribute = true
+		}
+	}
 
 	for child != nil {
 		if p, ok := child.(*phrase.Phrase); ok {
@@ -116,6 +126,7 @@ func (s *Class) analyseHeader(a analyser, document *Document, classHeader *phras
 						method := newMethodFromPhpDocTag(document, s, methodTag, phpDoc.GetLocation())
 						document.addSymbol(method)
 					}
+					s.Mixins = phpDoc.mixinTypes(document)
 					s.deprecatedTag = phpDoc.deprecated()
 				}
 			case lexer.Abstract:
@@ -163,6 +174,11 @@ func (s *Class) extends(a analyser, document *Document, p *phrase.Phrase) {
 }
 
 func (s *Class) implements(document *Document, p *phrase.Phrase) {
+	s.Interfaces = append(s.Interfaces, analyseInterfaceClause(document, p)...)
+}
+
+func analyseInterfaceClause(document *Document, p *phrase.Phrase) []TypeString {
+	interfaces := []TypeString{}
 	traverser := util.NewTraverser(p)
 	child := traverser.Peek()
 	for child != nil {
@@ -173,7 +189,7 @@ func (s *Class) implements(document *Document, p *phrase.Phrase) {
 				if p, ok = child.(*phrase.Phrase); ok && (p.Type == phrase.QualifiedName || p.Type == phrase.FullyQualifiedName) {
 					typeString := transformQualifiedName(p, document)
 					typeString.SetFQN(document.currImportTable().GetClassReferenceFQN(typeString))
-					s.Interfaces = append(s.Interfaces, typeString)
+					interfaces = append(interfaces, typeString)
 
 					interfaceAccess := newInterfaceAccess(document, p)
 					document.addSymbol(interfaceAccess)
@@ -186,6 +202,7 @@ func (s *Class) implements(document *Document, p *phrase.Phrase) {
 		traverser.Advance()
 		child = traverser.Peek()
 	}
+	return interfaces
 }
 
 func (s *Class) GetLocation() protocol.Location {
@@ -249,7 +266,13 @@ func (s *Class) Serialise(e *storage.Encoder) {
 	for _, use := range s.Use {
 		use.Write(e)
 	}
+	e.WriteInt(len(s.Mixins))
+	for _, mixin := range s.Mixins {
+		mixin.Write(e)
+	}
 	serialiseDeprecatedTag(e, s.deprecatedTag)
+	e.WriteBool(s.isAttribute)
+	e.WriteBool(s.isGuarded)
 }
 
 func ReadClass(d *storage.Decoder) *Class {
@@ -269,10 +292,26 @@ func ReadClass(d *storage.Decoder) *Class {
 	for i := 0; i < numUse; i++ {
 		theClass.Use = append(theClass.Use, ReadTypeString(d))
 	}
+	numMixins := d.ReadInt()
+	for i := 0; i < numMixins; i++ {
+		theClass.Mixins = append(theClass.Mixins, ReadTypeString(d))
+	}
 	theClass.deprecatedTag = deserialiseDeprecatedTag(d)
+	theClass.isAttribute = d.ReadBool()
+	theClass.isGuarded = d.ReadBool()
 	return theClass
 }
 
+// IsAttribute returns whether the class is declared with #[Attribute]
+func (s *Class) IsAttribute() bool {
+	return s.isAttribute
+}
+
+// Enum returns the enum if the class is an enum looked up as a class
+func (s *Class) Enum() *Enum {
+	return s.enum
+}
+
 func (s *Class) AddUse(name TypeString) {
 	s.Use = append(s.Use, name)
 }
@@ -1,6 +1,8 @@
 package analysis
 
 import (
+	"strings"
+
 	"github.com/john-nguyen09/go-phpparser/lexer"
 	"github.com/john-nguyen09/go-phpparser/phrase"
 	"github.com/john-nguyen09/phpintel/analysis/storage"
@@ -20,6 +22,7 @@ type ClassConst struct {
 	VisibilityModifier VisibilityModifierValue
 
 	deprecatedTag *tag
+	isEnumCase    bool
 }
 
 var _ HasScope = (*ClassConst)(nil)
@@ -30,6 +33,7 @@ var _ MemberSymbol = (*ClassConst)(nil)
 func newClassConstDeclaration(a analyser, document *Document, node *phrase.Phrase) Symbol {
 	traverser := util.NewTraverser(node)
 	visibility := Public
+	isEnumCase := false
 	child := traverser.Advance()
 	for child != nil {
 		if p, ok := child.(*phrase.Phrase); ok {
@@ -37,25 +41,29 @@ func newClassConstDeclaration(a analyser, document *Document, node *phrase.Phras
 			case phrase.MemberModifierList:
 				visibility, _, _ = getMemberModifier(p)
 			case phrase.ClassConstElementList:
-				newClassConstList(a, document, p, visibility)
+				newClassConstList(a, document, p, visibility, isEnumCase)
 			}
+		} else if t, ok := child.(*lexer.Token); ok && t.Type == lexer.Const {
+			// Enum cases are parsed as constants
+			isEnumCase = strings.EqualFold(document.getTokenText(t), "case")
 		}
 		child = traverser.Advance()
 	}
 	return nil
 }
 
-func newClassConstList(a analyser, document *Document, node *phrase.Phrase, visibility VisibilityModifierValue) {
+func newClassConstList(a analyser, document *Document, node *phrase.Phrase, visibility VisibilityModifierValue, isEnumCase bool) {
 	traverser := util.NewTraverser(node)
 	for child := traverser.Advance(); child != nil; child = traverser.Advance() {
 		if p, ok := child.(*phrase.Phrase); ok && p.Type == phrase.ClassConstElement {
-			property := newClassConst(a, document, p, visibility)
-			document.addSymbol(property)
+			classConst := newClassConst(a, document, p, visibility)
+			classConst.isEnumCase = isEnumCase
+			document.addSymbol(classConst)
 		}
 	}
 }
 
-func newClassConst(a analyser, document *Document, node *phrase.Phrase, visibility VisibilityModifierValue) Symbol {
+func newClassConst(a analyser, document *Document, node *phrase.Phrase, visibility VisibilityModifierValue) *ClassConst {
 	classConst := &ClassConst{
 		location:           document.GetNodeLocation(node),
 		VisibilityModifier: visibility,
@@ -71,6 +79,8 @@ func newClassConst(a analyser, document *Document, node *phrase.Phrase, visibili
 	} else if trait, ok := lastClass.(*Trait); ok {
 		classConst.Scope = trait.Name
 		classConst.Scope.SetNamespace(document.currImportTable().GetNamespace())
+	} else if enum, ok := lastClass.(*Enum); ok {
+		classConst.Scope = enum.Name
 	}
 	traverser := util.NewTraverser(node)
 	child := traverser.Advance()
@@ -88,8 +98,12 @@ func newClassConst(a analyser, document *Document, node *phrase.Phrase, visibili
 			}
 		} else if p, ok := child.(*phrase.Phrase); ok {
 			if hasEquals {
-				classConst.Value += document.getPhraseText(p)
-				scanNode(a, document, p)
+				text := document.getPhraseText(p)
+				classConst.Value += text
+				// Cases of pure enums have a placeholder value without text
+				if text != "" {
+					scanNode(a, document, p)
+				}
 			} else {
 				switch p.Type {
 				case phrase.Identifier:
@@ -150,6 +164,7 @@ func (s *ClassConst) Serialise(e *storage.Encoder) {
 	e.WriteString(s.Value)
 	s.Scope.Write(e)
 	serialiseDeprecatedTag(e, s.deprecatedTag)
+	e.WriteBool(s.isEnumCase)
 }
 
 func ReadClassConst(d *storage.Decoder) *ClassConst {
@@ -160,6 +175,7 @@ func ReadClassConst(d *storage.Decoder) *ClassConst {
 		Value:         d.ReadString(),
 		Scope:         ReadTypeString(d),
 		deprecatedTag: deserialiseDeprecatedTag(d),
+		isEnumCase:    d.ReadBool(),
 	}
 }
 
@@ -171,6 +187,11 @@ func (s *ClassConst) ReferenceLocation() protocol.Location {
 	return s.refLocation
 }
 
+// IsEnumCase returns whether the constant is a case of an enum
+func (s *ClassConst) IsEnumCase() bool {
+	return s.isEnumCase
+}
+
 // IsStatic is always static for class const
 func (s *ClassConst) IsStatic() bool {
 	return true
@@ -0,0 +1,259 @@
+package analysis
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/john-nguyen09/phpintel/analysis/storage"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// TagsHashTag is the pseudo tag which records the hash of every document
+// in the tags file, the entries of the documents which have the same hash
+// are copied from the previous tags file instead of being regenerated
+const TagsHashTag = "!_PHPINTEL_FILE_HASH"
+
+var tagsHeaders = []string{
+	"!_TAG_FILE_FORMAT\t2\t/extended format; --format=1 will not append ;\" to lines/",
+	"!_TAG_FILE_SORTED\t1\t/0=unsorted, 1=sorted, 2=foldcase/",
+	"!_TAG_PROGRAM_NAME\tphpintel\t//",
+}
+
+// TagsResult counts the documents of a tags file
+type TagsResult struct {
+	Written int
+	Reused  int
+}
+
+// ctag is an entry of the tags file, the fields are written in order
+type ctag struct {
+	name   string
+	path   string
+	line   int
+	fields [][2]string
+}
+
+// tagsFieldEscaper escapes the values of the extension fields as
+// universal-ctags does, so the backslashes of the namespaces are doubled
+var tagsFieldEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\r", "\\r", "\n", "\\n")
+
+func (t ctag) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\t%s\t%d;\"", t.name, t.path, t.line)
+	for _, field := range t.fields {
+		if field[1] == "" {
+			continue
+		}
+		sb.WriteString("\t" + field[0] + ":" + tagsFieldEscaper.Replace(field[1]))
+	}
+	return sb.String()
+}
+
+// previousTags are the entries and the hashes of the documents of a tags file
+type previousTags struct {
+	hashes  map[string]string
+	entries map[string][]string
+}
+
+func readPreviousTags(r io.Reader) (previousTags, error) {
+	tags := previousTags{map[string]string{}, map[string][]string{}}
+	if r == nil {
+		return tags, nil
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		if fields[0] == TagsHashTag {
+			tags.hashes[fields[1]] = strings.Trim(fields[2], "/")
+			continue
+		}
+		if strings.HasPrefix(fields[0], "!_") {
+			continue
+		}
+		tags.entries[fields[1]] = append(tags.entries[fields[1]], scanner.Text())
+	}
+	return tags, scanner.Err()
+}
+
+// WriteTags writes a sorted ctags file of the symbols of the indexed
+// documents under the root of the store, the paths are relative to the
+// root. The entries of the documents which have not changed since the
+// previous tags file are copied from it, previous can be nil
+func (s *Store) WriteTags(w io.Writer, previous io.Reader, includeVendor bool) (TagsResult, error) {
+	result := TagsResult{}
+	prev, err := readPreviousTags(previous)
+	if err != nil {
+		return result, err
+	}
+	rootPath, err := util.URIToPath(s.uri)
+	if err != nil {
+		return result, err
+	}
+	lines := append([]string{}, tagsHeaders...)
+	for uri, hash := range s.getSyncedDocumentURIs() {
+		if !strings.HasPrefix(uri, s.uri+"/") || (!includeVendor && IsVendorURI(strings.TrimPrefix(uri, s.uri))) {
+			continue
+		}
+		path, err := util.URIToPath(uri)
+		if err != nil {
+			continue
+		}
+		if path, err = filepath.Rel(rootPath, path); err != nil {
+			continue
+		}
+		path = filepath.ToSlash(path)
+		currentHash := hex.EncodeToString(hash)
+		lines = append(lines, fmt.Sprintf("%s\t%s\t/%s/", TagsHashTag, path, currentHash))
+		if prev.hashes[path] == currentHash {
+			lines = append(lines, prev.entries[path]...)
+			result.Reused++
+			continue
+		}
+		for _, tag := range s.documentTags(uri, path) {
+			lines = append(lines, tag.String())
+		}
+		result.Written++
+	}
+	sort.Strings(lines)
+	writer := bufio.NewWriter(w)
+	for _, line := range lines {
+		writer.WriteString(line)
+		writer.WriteString("\n")
+	}
+	return result, writer.Flush()
+}
+
+// documentTags reads the symbols of the document from the collections,
+// the members are scoped by the classes, interfaces, traits and enums which
+// are declared before them in the same document
+func (s *Store) documentTags(uri string, path string) []ctag {
+	tags := []ctag{}
+	scopeKinds := map[string]string{}
+	readDocumentSymbols(s.greb, newEntry(documentSymbolsCollection, uri), func(symbol documentSymbol) bool {
+		value, err := s.db.Get(newEntry(symbol.collection, symbol.key).getKeyBytes())
+		if err != nil || value == nil {
+			return false
+		}
+		d := storage.NewDecoder(value)
+		var tag ctag
+		switch symbol.collection {
+		case classCollection:
+			class := ReadClass(d)
+			scopeKinds[class.Name.GetFQN()] = "class"
+			tag = newTypeTag(class.Name, "class", class.Location.Range.Start.Line)
+		case interfaceCollection:
+			theInterface := ReadInterface(d)
+			scopeKinds[theInterface.Name.GetFQN()] = "interface"
+			tag = newTypeTag(theInterface.Name, "interface", theInterface.location.Range.Start.Line)
+		case traitCollection:
+			trait := ReadTrait(d)
+			scopeKinds[trait.Name.GetFQN()] = "trait"
+			tag = newTypeTag(trait.Name, "trait", trait.location.Range.Start.Line)
+		case enumCollection:
+			// enum is the long name of the g kind of universal-ctags
+			enum := ReadEnum(d)
+			scopeKinds[enum.Name.GetFQN()] = "enum"
+			tag = newTypeTag(enum.Name, "enum", enum.Location.Range.Start.Line)
+		case functionCollection:
+			function := ReadFunction(d)
+			tag = newTypeTag(function.Name, "function", function.location.Range.Start.Line)
+			tag.fields = append(tag.fields, [2]string{"signature", paramsSignature(function.Params)})
+		case constCollection:
+			constant := ReadConst(d)
+			tag = newTypeTag(constant.Name, "define", constant.location.Range.Start.Line)
+		case defineCollection:
+			define := ReadDefine(d)
+			tag = newTypeTag(define.Name, "define", define.location.Range.Start.Line)
+		case methodCollection:
+			method := ReadMethod(d)
+			tag = newMemberTag(method.Name, "function", method.location.Range.Start.Line,
+				method.Scope, scopeKinds, method.VisibilityModifier)
+			tag.fields = append(tag.fields, [2]string{"signature", paramsSignature(method.Params)})
+		case propertyCollection:
+			prop := ReadProperty(d)
+			tag = newMemberTag(strings.TrimPrefix(prop.Name, "$"), "variable", prop.location.Range.Start.Line,
+				prop.Scope, scopeKinds, prop.VisibilityModifier)
+		case classConstCollection:
+			classConst := ReadClassConst(d)
+			tag = newMemberTag(classConst.Name, "define", classConst.location.Range.Start.Line,
+				classConst.Scope, scopeKinds, classConst.VisibilityModifier)
+		default:
+			return false
+		}
+		if tag.name == "" {
+			return false
+		}
+		tag.path = path
+		tags = append(tags, tag)
+		return false
+	})
+	return tags
+}
+
+func newTypeTag(name TypeString, kind string, line int) ctag {
+	fqn := name.GetFQN()
+	return ctag{
+		name: fqn[strings.LastIndex(fqn, "\\")+1:],
+		line: line + 1,
+		fields: [][2]string{
+			{"kind", kind},
+			{"namespace", tagsNamespace(name)},
+		},
+	}
+}
+
+func newMemberTag(name string, kind string, line int, scope TypeString,
+	scopeKinds map[string]string, visibility VisibilityModifierValue) ctag {
+	scopeKind, ok := scopeKinds[scope.GetFQN()]
+	if !ok {
+		scopeKind = "class"
+	}
+	return ctag{
+		name: name,
+		line: line + 1,
+		fields: [][2]string{
+			{"kind", kind},
+			{scopeKind, strings.TrimPrefix(scope.GetFQN(), "\\")},
+			{"namespace", tagsNamespace(scope)},
+			{"access", visibility.ToString()},
+		},
+	}
+}
+
+// tagsNamespace returns the namespace without the leading backslash, it is
+// empty for the global namespace
+func tagsNamespace(name TypeString) string {
+	return strings.TrimPrefix(name.GetNamespace(), "\\")
+}
+
+// paramsSignature formats the parameters as they are declared, e.g.
+// (string $name, int $count = 1)
+func paramsSignature(params []*Parameter) string {
+	contents := []string{}
+	for _, param := range params {
+		content := param.Name
+		if param.IsVariadic() {
+			content = "..." + content
+		}
+		if param.IsReference() {
+			content = "&" + content
+		}
+		if !param.Type.IsEmpty() {
+			content = param.Type.ToString() + " " + content
+		}
+		if param.HasValue() {
+			content += " = " + param.Value
+		}
+		contents = append(contents, content)
+	}
+	return "(" + strings.Join(contents, ", ") + ")"
+}
@@ -0,0 +1,389 @@
+package analysis
+
+import (
+	"strings"
+	"time"
+
+	"github.com/john-nguyen09/go-phpparser/lexer"
+	"github.com/john-nguyen09/go-phpparser/phrase"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// Codes of the dead code diagnostics, each of them can be disabled by the clients
+const (
+	CodeUnreachableCode      = "unreachable-code"
+	CodeConstantCondition    = "constant-condition"
+	CodeNeverThrownException = "never-thrown-exception"
+)
+
+// neverCallChecker checks whether the expression is a call to a function or
+// method which is declared to never return, nil assumes that all calls return
+type neverCallChecker func(expression *phrase.Phrase) bool
+
+type hasReturnTypes interface {
+	GetReturnTypes() TypeComposite
+}
+
+type hasThrowTypes interface {
+	GetThrowTypes() TypeComposite
+}
+
+// calleeNameNode returns the node where the symbol of the call is located,
+// nil if there is none e.g. anonymous classes
+func calleeNameNode(node *phrase.Phrase) phrase.AstNode {
+	var nameNode *phrase.Phrase
+	switch node.Type {
+	case phrase.FunctionCallExpression:
+		nameNode = firstPhrase(node)
+	case phrase.MethodCallExpression:
+		nameNode = childPhrase(node, phrase.MemberName)
+	case phrase.ScopedCallExpression:
+		nameNode = childPhrase(node, phrase.ScopedMemberName)
+	case phrase.ObjectCreationExpression:
+		nameNode = childPhrase(node, phrase.ClassTypeDesignator)
+	}
+	if nameNode == nil {
+		// A nil *phrase.Phrase is not a nil phrase.AstNode
+		return nil
+	}
+	return nameNode
+}
+
+// neverReturningCalls returns the checker of the calls of which all the
+// resolved functions or methods have never as the return type
+func neverReturningCalls(ctx ResolveContext) neverCallChecker {
+	return func(expression *phrase.Phrase) bool {
+		nameNode := calleeNameNode(expression)
+		if nameNode == nil || expression.Type == phrase.ObjectCreationExpression {
+			return false
+		}
+		callee, ok := ctx.calleeAt(ctx.document.NodeRange(nameNode).Start)
+		if !ok {
+			return false
+		}
+		candidates := ctx.resolveCallee(callee)
+		for _, candidate := range candidates {
+			r, ok := candidate.(hasReturnTypes)
+			if !ok || !isNeverType(r.GetReturnTypes()) {
+				return false
+			}
+		}
+		return len(candidates) > 0
+	}
+}
+
+func isNeverType(types TypeComposite) bool {
+	if types.IsEmpty() {
+		return false
+	}
+	for _, t := range types.Resolve() {
+		switch strings.ToLower(strings.TrimPrefix(t.GetOriginal(), "\\")) {
+		case "never", "no-return", "noreturn", "never-return", "never-returns":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+type deadCode struct {
+	ctx         ResolveContext
+	doc         *Document
+	classes     map[protocol.Position]HasTypes
+	isNeverCall neverCallChecker
+	diagnostics []protocol.Diagnostic
+}
+
+// DeadCodeDiagnostics returns the diagnostics for the statements after return,
+// throw, exit, break, continue or calls to never-returning functions, the if
+// conditions which are literally true or false and the catch clauses of which
+// exceptions are never thrown by the try block according to @throws
+func DeadCodeDiagnostics(ctx ResolveContext) []protocol.Diagnostic {
+	defer util.TimeTrack(time.Now(), "DeadCodeDiagnostics")
+	d := &deadCode{
+		ctx:         ctx,
+		doc:         ctx.document,
+		classes:     map[protocol.Position]HasTypes{},
+		isNeverCall: neverReturningCalls(ctx),
+		diagnostics: []protocol.Diagnostic{},
+	}
+	TraverseDocument(d.doc, func(s Symbol) {
+		switch v := s.(type) {
+		case *ClassAccess:
+			d.classes[v.Location.Range.Start] = v
+		case *ClassTypeDesignator:
+			d.classes[v.Location.Range.Start] = v
+		}
+	}, nil)
+	traverser := util.NewTraverser(d.doc.GetRootNode())
+	traverser.Traverse(func(node phrase.AstNode, _ []*phrase.Phrase) util.VisitorContext {
+		if p, ok := node.(*phrase.Phrase); ok {
+			switch p.Type {
+			case phrase.StatementList, phrase.CompoundStatement:
+				d.unreachableStatements(p.Children)
+			case phrase.IfStatement:
+				d.constantConditions(p)
+			case phrase.TryStatement:
+				d.neverThrownExceptions(p)
+			case phrase.DocumentComment:
+				return util.VisitorContext{ShouldAscend: false}
+			}
+		}
+		return util.VisitorContext{ShouldAscend: true}
+	})
+	return d.diagnostics
+}
+
+func (d *deadCode) unreachable(r protocol.Range) {
+	d.diagnostics = append(d.diagnostics, newDiagnostic(CodeUnreachableCode, r, "Unreachable code."))
+}
+
+// unreachableStatements reports the statements after a statement which never
+// completes, the declarations are hoisted so they are not unreachable
+func (d *deadCode) unreachableStatements(nodes []phrase.AstNode) {
+	isTerminated := false
+	var start, end *phrase.Phrase
+	report := func() {
+		if start != nil {
+			d.unreachable(protocol.Range{Start: d.doc.NodeRange(start).Start, End: d.doc.NodeRange(end).End})
+		}
+		start, end = nil, nil
+	}
+	for _, node := range nodes {
+		p, ok := node.(*phrase.Phrase)
+		if !ok {
+			continue
+		}
+		if !isTerminated {
+			isTerminated = p.Type == phrase.BreakStatement || p.Type == phrase.ContinueStatement ||
+				alwaysTerminates(d.doc, p, d.isNeverCall)
+			continue
+		}
+		switch p.Type {
+		case phrase.FunctionDeclaration, phrase.ClassDeclaration, phrase.InterfaceDeclaration,
+			phrase.TraitDeclaration, phrase.InlineText:
+			report()
+			continue
+		}
+		if start == nil {
+			start = p
+		}
+		end = p
+	}
+	report()
+}
+
+// literalCondition returns the condition of if or elseif and whether it is
+// literally true or false
+func (d *deadCode) literalCondition(node *phrase.Phrase) (*phrase.Phrase, bool, bool) {
+	isCondition := false
+	for _, child := range node.Children {
+		switch v := child.(type) {
+		case *lexer.Token:
+			if v.Type == lexer.OpenParenthesis {
+				isCondition = true
+			}
+		case *phrase.Phrase:
+			if !isCondition {
+				continue
+			}
+			if v.Type != phrase.ConstantAccessExpression {
+				return v, false, false
+			}
+			switch strings.ToLower(strings.TrimPrefix(d.doc.GetNodeText(v), "\\")) {
+			case "true":
+				return v, true, true
+			case "false":
+				return v, true, false
+			}
+			return v, false, false
+		}
+	}
+	return nil, false, false
+}
+
+// constantConditions reports the literal conditions of the if statement and the
+// branches which are never taken because of them
+func (d *deadCode) constantConditions(node *phrase.Phrase) {
+	clauses := []*phrase.Phrase{node}
+	var elseClause *phrase.Phrase
+	for _, child := range node.Children {
+		if p, ok := child.(*phrase.Phrase); ok {
+			switch p.Type {
+			case phrase.ElseIfClauseList:
+				for _, c := range p.Children {
+					if elseIf, ok := c.(*phrase.Phrase); ok && elseIf.Type == phrase.ElseIfClause {
+						clauses = append(clauses, elseIf)
+					}
+				}
+			case phrase.ElseClause:
+				elseClause = p
+			}
+		}
+	}
+	statementsRange := func(statements []phrase.AstNode) protocol.Range {
+		return protocol.Range{
+			Start: d.doc.NodeRange(statements[0]).Start,
+			End:   d.doc.NodeRange(statements[len(statements)-1]).End,
+		}
+	}
+	for i, clause := range clauses {
+		condition, isLiteral, value := d.literalCondition(clause)
+		if !isLiteral {
+			continue
+		}
+		message := "Condition is always false."
+		if value {
+			message = "Condition is always true."
+		}
+		d.diagnostics = append(d.diagnostics, newDiagnostic(CodeConstantCondition, d.doc.NodeRange(condition), message))
+		if !value {
+			if statements := statementsAfterCondition(clause); len(statements) > 0 {
+				d.unreachable(statementsRange(statements))
+			}
+			continue
+		}
+		// The following branches are never taken
+		var rest []phrase.AstNode
+		for _, c := range clauses[i+1:] {
+			rest = append(rest, c)
+		}
+		if elseClause != nil {
+			rest = append(rest, elseClause)
+		}
+		if len(rest) > 0 {
+			d.unreachable(statementsRange(rest))
+		}
+		return
+	}
+}
+
+// neverThrownExceptions reports the caught exceptions which are not related
+// to any of the exceptions thrown in the try block, the check is skipped when
+// the try block calls functions which cannot be resolved or rethrows
+func (d *deadCode) neverThrownExceptions(node *phrase.Phrase) {
+	q := d.ctx.query
+	body := childPhrase(node, phrase.CompoundStatement)
+	if body == nil {
+		return
+	}
+	thrown, ok := d.thrownTypes(body)
+	if !ok || len(thrown) == 0 {
+		return
+	}
+	isRelated := func(caught string) bool {
+		for _, t := range thrown {
+			if q.IsSubclassOf(t.GetFQN(), caught) || q.IsSubclassOf(caught, t.GetFQN()) {
+				return true
+			}
+		}
+		return false
+	}
+	// Catching all exceptions or the ones which are not documented
+	// by @throws usually, e.g. RuntimeException, is not reported
+	isUnchecked := func(caught string) bool {
+		if strings.EqualFold(caught, "\\Exception") || strings.EqualFold(caught, "\\Throwable") {
+			return true
+		}
+		for _, unchecked := range []string{"\\RuntimeException", "\\LogicException", "\\Error"} {
+			if q.IsSubclassOf(caught, unchecked) {
+				return true
+			}
+		}
+		return false
+	}
+	catchList := childPhrase(node, phrase.CatchClauseList)
+	if catchList == nil {
+		return
+	}
+	util.NewTraverser(catchList).Traverse(func(child phrase.AstNode, _ []*phrase.Phrase) util.VisitorContext {
+		p, ok := child.(*phrase.Phrase)
+		if !ok {
+			return util.VisitorContext{ShouldAscend: false}
+		}
+		switch p.Type {
+		case phrase.CatchClauseList, phrase.CatchClause, phrase.CatchNameList:
+			return util.VisitorContext{ShouldAscend: true}
+		case phrase.QualifiedName, phrase.FullyQualifiedName:
+			classAccess, ok := d.classes[d.doc.NodeRange(p).Start]
+			if !ok {
+				break
+			}
+			for _, t := range classAccess.GetTypes().Resolve() {
+				caught := t.GetFQN()
+				if !q.isClassLike(caught) || isUnchecked(caught) || isRelated(caught) {
+					continue
+				}
+				d.diagnostics = append(d.diagnostics, newDiagnostic(CodeNeverThrownException, d.doc.NodeRange(p),
+					"Exception "+t.GetOriginal()+" is never thrown in the try block."))
+			}
+		}
+		return util.VisitorContext{ShouldAscend: false}
+	})
+}
+
+// thrownTypes returns the types of the exceptions which are thrown or declared
+// by @throws of the callees in the block, it is not ok if any of the callees
+// is unknown or has no @throws
+func (d *deadCode) thrownTypes(body *phrase.Phrase) ([]TypeString, bool) {
+	thrown := []TypeString{}
+	isKnown := true
+	util.NewTraverser(body).Traverse(func(node phrase.AstNode, _ []*phrase.Phrase) util.VisitorContext {
+		p, ok := node.(*phrase.Phrase)
+		if !ok || !isKnown {
+			return util.VisitorContext{ShouldAscend: false}
+		}
+		if isFunctionLike(p) || p.Type == phrase.DocumentComment {
+			return util.VisitorContext{ShouldAscend: false}
+		}
+		switch p.Type {
+		case phrase.ThrowStatement:
+			creation := childPhrase(p, phrase.ObjectCreationExpression)
+			if creation == nil {
+				isKnown = false
+				break
+			}
+			nameNode := calleeNameNode(creation)
+			if nameNode == nil {
+				isKnown = false
+				break
+			}
+			designator, ok := d.classes[d.doc.NodeRange(nameNode).Start]
+			if !ok {
+				isKnown = false
+				break
+			}
+			thrown = append(thrown, designator.GetTypes().Resolve()...)
+		case phrase.FunctionCallExpression, phrase.MethodCallExpression, phrase.ScopedCallExpression,
+			phrase.ObjectCreationExpression:
+			nameNode := calleeNameNode(p)
+			if nameNode == nil {
+				isKnown = false
+				break
+			}
+			callee, ok := d.ctx.calleeAt(d.doc.NodeRange(nameNode).Start)
+			if !ok {
+				isKnown = false
+				break
+			}
+			candidates := d.ctx.resolveCallee(callee)
+			if len(candidates) == 0 && p.Type != phrase.ObjectCreationExpression {
+				// Classes without constructors do not throw
+				isKnown = false
+				break
+			}
+			for _, candidate := range candidates {
+				h, ok := candidate.(hasThrowTypes)
+				if !ok || h.GetThrowTypes().IsEmpty() {
+					// The callees without @throws can throw anything
+					isKnown = false
+					break
+				}
+				thrown = append(thrown, h.GetThrowTypes().Resolve()...)
+			}
+		}
+		return util.VisitorContext{ShouldAscend: true}
+	})
+	return thrown, isKnown
+}
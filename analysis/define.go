@@ -16,6 +16,7 @@ type Define struct {
 	deprecatedTag *tag
 	Name          TypeString
 	Value         string
+	isGuarded     bool
 }
 
 var _ HasTypes = (*Define)(nil)
@@ -23,7 +24,8 @@ var _ HasParamsResolvable = (*Define)(nil)
 
 func newDefine(a analyser, document *Document, node *phrase.Phrase) Symbol {
 	define := &Define{
-		location: document.GetNodeLocation(node),
+		location:  document.GetNodeLocation(node),
+		isGuarded: isExistenceGuarded(a, document),
 	}
 	phpDoc := document.getValidPhpDoc(define.location)
 	document.addSymbol(define)
@@ -129,6 +131,7 @@ func (s *Define) Serialise(e *storage.Encoder) {
 	serialiseDeprecatedTag(e, s.deprecatedTag)
 	s.Name.Write(e)
 	e.WriteString(s.Value)
+	e.WriteBool(s.isGuarded)
 }
 
 func ReadDefine(d *storage.Decoder) *Define {
@@ -138,5 +141,6 @@ func ReadDefine(d *storage.Decoder) *Define {
 		deprecatedTag: deserialiseDeprecatedTag(d),
 		Name:          ReadTypeString(d),
 		Value:         d.ReadString(),
+		isGuarded:     d.ReadBool(),
 	}
 }
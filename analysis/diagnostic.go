@@ -11,6 +11,30 @@ import (
 
 const source = "phpintel"
 
+// DocumentDiagnostics returns the diagnostics which only need the document
+// itself, they are cheap enough to be provided on every change
+func DocumentDiagnostics(document *Document) []protocol.Diagnostic {
+	diagnostics := GetParserDiagnostics(document)
+	diagnostics = append(diagnostics, UnusedDiagnostics(document)...)
+	diagnostics = append(diagnostics, SuppressionDiagnostics(document)...)
+	return SuppressDiagnostics(document, diagnostics)
+}
+
+// ResolvedDiagnostics returns the diagnostics which need to resolve the
+// symbols against the store
+func ResolvedDiagnostics(ctx ResolveContext) []protocol.Diagnostic {
+	diagnostics := DeprecatedDiagnostics(ctx)
+	diagnostics = append(diagnostics, TypeDiagnostics(ctx)...)
+	diagnostics = append(diagnostics, AccessDiagnostics(ctx)...)
+	diagnostics = append(diagnostics, UnusedMemberDiagnostics(ctx)...)
+	diagnostics = append(diagnostics, UnusedArgumentDiagnostics(ctx)...)
+	diagnostics = append(diagnostics, UndefinedVariableDiagnostics(ctx)...)
+	diagnostics = append(diagnostics, DeadCodeDiagnostics(ctx)...)
+	diagnostics = append(diagnostics, DuplicateDeclarationDiagnostics(ctx)...)
+	diagnostics = append(diagnostics, ArgumentDiagnostics(ctx)...)
+	return SuppressDiagnostics(ctx.document, diagnostics)
+}
+
 // GetParserDiagnostics returns the diagnostics for the syntax error
 func GetParserDiagnostics(document *Document) []protocol.Diagnostic {
 	rootNode := document.GetRootNode()
@@ -43,55 +67,86 @@ func parserErrorToDiagnostic(document *Document, err *phrase.ParseError) protoco
 		message += "Unknown error"
 	}
 
-	return protocol.Diagnostic{
-		Range:    document.errorRange(err),
-		Message:  message,
-		Severity: protocol.SeverityError,
-		Source:   source,
-	}
+	return newDiagnostic(CodeSyntaxError, document.errorRange(err), message)
 }
 
 // UnusedDiagnostics returns the diagnostics for unused variables or imports
 // TODO: provide unused imports
 func UnusedDiagnostics(document *Document) []protocol.Diagnostic {
 	diagnostics := []protocol.Diagnostic{}
-	unusedVariables := document.UnusedVariables()
-	for _, unusedVariable := range unusedVariables {
-		diagnostics = append(diagnostics, protocol.Diagnostic{
-			Range:    unusedVariable.GetLocation().Range,
-			Message:  unusedVariable.Name + " is declared but its value is never read.",
-			Source:   source,
-			Severity: protocol.SeverityHint,
-			Tags:     []protocol.DiagnosticTag{protocol.Unnecessary},
-		})
+	for _, unusedVariable := range document.UnusedVariables() {
+		diagnostics = append(diagnostics, unusedVariableDiagnostic(unusedVariable))
 	}
 	for _, importTable := range document.importTables {
 		for _, unusedImport := range importTable.unusedImportItems() {
-			diagnostics = append(diagnostics, protocol.Diagnostic{
-				Range:    unusedImport.locationRange,
-				Message:  unusedImport.name + " is declared but its value is never used.",
-				Source:   source,
-				Severity: protocol.SeverityHint,
-				Tags:     []protocol.DiagnosticTag{protocol.Unnecessary},
-			})
+			diagnostics = append(diagnostics, newDiagnostic(CodeUnusedImport, unusedImport.locationRange,
+				unusedImport.name+" is declared but its value is never used."))
 		}
 	}
 	return diagnostics
 }
 
+// UnusedArgumentDiagnostics returns the diagnostics for the unused variables which are
+// passed as arguments, they are not reported if any callee takes them by reference
+func UnusedArgumentDiagnostics(ctx ResolveContext) []protocol.Diagnostic {
+	defer util.TimeTrack(time.Now(), "UnusedArgumentDiagnostics")
+	diagnostics := []protocol.Diagnostic{}
+	for _, unusedVariable := range ctx.document.unusedArgumentVariables(byRefArguments(ctx)) {
+		diagnostics = append(diagnostics, unusedVariableDiagnostic(unusedVariable))
+	}
+	return diagnostics
+}
+
+func unusedVariableDiagnostic(variable *Variable) protocol.Diagnostic {
+	return newDiagnostic(CodeUnusedVariable, variable.GetLocation().Range,
+		variable.Name+" is declared but its value is never read.")
+}
+
+// byRefArguments returns the start positions of the arguments which are passed
+// to the parameters by reference, the arguments of unknown callees are assumed to be
+func byRefArguments(ctx ResolveContext) map[protocol.Position]struct{} {
+	results := map[protocol.Position]struct{}{}
+	traverseCalls(ctx.document, func(argumentList *ArgumentList, callee HasParamsResolvable) {
+		candidates := ctx.resolveCallee(callee)
+		ranges := argumentList.GetArgumentRanges()
+		positionals := 0
+		for i := range argumentList.GetArguments() {
+			if i >= len(ranges) {
+				break
+			}
+			name := argumentList.ArgumentName(i)
+			isByRef := len(candidates) == 0
+			for _, candidate := range candidates {
+				params := candidate.GetParams()
+				index := positionals
+				if name != "" {
+					index = paramIndex(params, name)
+				}
+				if index >= len(params) && len(params) > 0 && params[len(params)-1].IsVariadic() {
+					index = len(params) - 1
+				}
+				if index >= 0 && index < len(params) && params[index].IsReference() {
+					isByRef = true
+				}
+			}
+			if isByRef {
+				results[ranges[i].Start] = struct{}{}
+			}
+			if name == "" {
+				positionals++
+			}
+		}
+	})
+	return results
+}
+
 // DeprecatedDiagnostics returns the diagnostics for deprecated references
 func DeprecatedDiagnostics(ctx ResolveContext) []protocol.Diagnostic {
 	defer util.TimeTrack(time.Now(), "DeprecatedDiagnostics")
 	doc := ctx.document
 	q := ctx.query
 	create := func(r protocol.Range, message string) protocol.Diagnostic {
-		return protocol.Diagnostic{
-			Range:    r,
-			Message:  message,
-			Source:   source,
-			Severity: protocol.SeverityHint,
-			Tags:     []protocol.DiagnosticTag{protocol.Deprecated},
-		}
+		return newDiagnostic(CodeDeprecated, r, message)
 	}
 	diagnostics := []protocol.Diagnostic{}
 	TraverseDocument(doc, func(s Symbol) {
@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"io/ioutil"
+	"strconv"
 	"testing"
 
 	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
@@ -43,3 +44,367 @@ func TestDeprecatedReferences(t *testing.T) {
 		}, results)
 	})
 }
+
+func TestArgumentDiagnostics(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := openDocument(store, "../cases/argumentDiagnostic.php", "test1")
+		diagnostics := ArgumentDiagnostics(NewResolveContext(NewQuery(store), doc))
+		type result struct {
+			r       protocol.Range
+			message string
+		}
+		results := []result{}
+		for _, diagnostic := range diagnostics {
+			results = append(results, result{diagnostic.Range, diagnostic.Message})
+		}
+		r := func(line int, start int, end int) protocol.Range {
+			return protocol.Range{
+				Start: protocol.Position{Line: line, Character: start},
+				End:   protocol.Position{Line: line, Character: end},
+			}
+		}
+		assert.Equal(t, []result{
+			{r(20, 5, 7), "Too few arguments to greet(), expected at least 1 but got 0."},
+			{r(21, 19, 26), "Too many arguments to greet(), expected at most 2 but got 3."},
+			{r(23, 5, 21), "Missing argument $name for greet()."},
+			{r(24, 19, 24), "Unknown named argument $title for greet()."},
+			{r(25, 19, 23), "Duplicate named argument $name."},
+			{r(26, 13, 17), "Named argument $name overwrites previous argument."},
+			{r(27, 19, 23), "Cannot use positional argument after named argument."},
+			{r(31, 20, 22), "Too few arguments to Mailer::__construct(), expected at least 1 but got 0."},
+			{r(32, 13, 22), "Too few arguments to Mailer::send(), expected at least 2 but got 1."},
+			{r(33, 19, 20), "Too many arguments to Mailer::create(), expected 1 but got 2."},
+		}, results)
+	})
+}
+
+func TestStubArgumentDiagnostics(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		// The stubs are older than PHP 8 so their parameter names are not checked
+		indexDocument(store, "../cases/stubArgument.php", "phpstorm-stubs://standard/stubArgument.php")
+		doc := NewDocument("test1", []byte("<?php\npad(string: 'a', length: 5, pad_string: '0');\npad(string: 'a', string: 'b');\npad('a');\n"))
+		doc.Load()
+		store.SyncDocument(doc)
+		messages := []string{}
+		for _, diagnostic := range ArgumentDiagnostics(NewResolveContext(NewQuery(store), doc)) {
+			messages = append(messages, diagnostic.Message)
+		}
+		assert.Equal(t, []string{
+			"Duplicate named argument $string.",
+			"Too few arguments to pad(), expected at least 2 but got 1.",
+		}, messages)
+	})
+}
+
+func TestTypeDiagnostics(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := openDocument(store, "../cases/typeDiagnostic.php", "test1")
+		diagnostics := TypeDiagnostics(NewResolveContext(NewQuery(store), doc))
+		type result struct {
+			r       protocol.Range
+			message string
+		}
+		results := []result{}
+		for _, diagnostic := range diagnostics {
+			results = append(results, result{diagnostic.Range, diagnostic.Message})
+		}
+		r := func(line int, start int, end int) protocol.Range {
+			return protocol.Range{
+				Start: protocol.Position{Line: line, Character: start},
+				End:   protocol.Position{Line: line, Character: end},
+			}
+		}
+		assert.Equal(t, []result{
+			{r(14, 6, 9), "Argument 1 ($factor) of scale() expects int, string given."},
+			{r(16, 5, 17), "Argument 1 ($shape) of draw() expects \\App\\Shape, \\App\\Report given."},
+			{r(17, 19, 22), "Argument 2 ($label) of draw() expects string, float given."},
+			{r(18, 12, 24), "Argument 1 ($shape) of draw() expects \\App\\Shape, \\App\\Report given."},
+			{r(19, 15, 16), "Argument 3 ($messages) of note() expects string, int given."},
+			{r(22, 5, 12), "Argument 1 ($shape) of draw() expects \\App\\Shape, \\App\\Report given."},
+			{r(96, 6, 18), "Argument 1 ($shape) of frame() expects \\App\\Shape|null, \\App\\Report given."},
+			{r(27, 4, 13), "A void function must not return a value."},
+			{r(30, 9, 14), "Missing return statement in a function with return type int."},
+			{r(78, 4, 11), "A function with return type string must return a value."},
+			{r(85, 20, 28), "Missing return statement in a function with return type string."},
+		}, results)
+
+		q := NewQuery(store)
+		assert.True(t, q.IsSubclassOf("\\App\\Square", "\\App\\Shape"))
+		assert.False(t, q.IsSubclassOf("\\App\\Report", "\\App\\Shape"))
+		assert.True(t, q.IsSubclassOf("\\App\\Sketch", "\\App\\Shape"))
+
+		union := newTypeComposite()
+		union.add(NewTypeString("string"))
+		union.add(NewTypeString("string[]"))
+		assert.Equal(t, "string|string[]", union.ToString())
+		callback := newTypeComposite()
+		callback.add(NewTypeString("callback"))
+		assert.True(t, q.IsAssignable(NewTypeString("string"), callback, true))
+		assert.False(t, q.IsAssignable(NewTypeString("int"), callback, true))
+	})
+}
+
+func TestIsNullableReturnType(t *testing.T) {
+	assert.True(t, isNullableReturnType("?int"))
+	assert.True(t, isNullableReturnType("mixed"))
+	assert.True(t, isNullableReturnType("int|null"))
+	assert.True(t, isNullableReturnType("NULL|Foo"))
+	assert.False(t, isNullableReturnType("int"))
+	assert.False(t, isNullableReturnType("int|string"))
+}
+
+func TestAccessDiagnostics(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := openDocument(store, "../cases/accessDiagnostic.php", "test1")
+		diagnostics := AccessDiagnostics(NewResolveContext(NewQuery(store), doc))
+		type result struct {
+			line    int
+			code    interface{}
+			message string
+		}
+		results := []result{}
+		for _, diagnostic := range diagnostics {
+			results = append(results, result{diagnostic.Range.Start.Line, diagnostic.Code, diagnostic.Message})
+		}
+		assert.Equal(t, []result{
+			{30, CodeInaccessibleMethod, "Call to private method Model::hidden() from scope App\\User."},
+			{38, CodeThisInStaticContext, "Using $this when not in object context."},
+			{39, CodeNonStaticCall, "Non-static method Model::visible() cannot be called statically."},
+			{48, CodeInaccessibleMethod, "Call to protected method Model::guarded() from scope App\\Report."},
+			{49, CodeInaccessibleProperty, "Cannot access protected property Model::$table."},
+			{55, CodeInaccessibleMethod, "Call to private method Model::hidden() from global scope."},
+			{56, CodeInaccessibleProperty, "Cannot access private property Model::$secret."},
+			{58, CodeNonStaticCall, "Non-static method Model::visible() cannot be called statically."},
+			{59, CodeAbstractInstantiation, "Cannot instantiate abstract class Model."},
+			{60, CodeAbstractInstantiation, "Cannot instantiate interface Shape."},
+		}, results)
+	})
+}
+
+func TestUnusedMemberDiagnostics(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := openDocument(store, "../cases/unusedMember.php", "test1")
+		diagnostics := UnusedMemberDiagnostics(NewResolveContext(NewQuery(store), doc))
+		type result struct {
+			line    int
+			code    interface{}
+			message string
+		}
+		results := []result{}
+		for _, diagnostic := range diagnostics {
+			assert.Equal(t, []protocol.DiagnosticTag{protocol.Unnecessary}, diagnostic.Tags)
+			results = append(results, result{diagnostic.Range.Start.Line, diagnostic.Code, diagnostic.Message})
+		}
+		assert.Equal(t, []result{
+			{13, CodeUnusedParameter, "$data is declared but its value is never read."},
+			{32, CodeUnusedPrivateMember, "Private constant Service::UNUSED is declared but never used."},
+			{34, CodeUnusedPrivateMember, "Private property Service::$cache is declared but never used."},
+			{38, CodeUnusedPrivateMember, "Private property Service::$config is declared but never used."},
+			{64, CodeUnusedPrivateMember, "Private method Service::forgotten() is declared but never used."},
+			{38, CodeUnusedParameter, "$options is declared but its value is never read."},
+			{56, CodeUnusedParameter, "$a is declared but its value is never read."},
+			{72, CodeUnusedParameter, "$first is declared but its value is never read."},
+			{77, CodeUnusedParameter, "$key is declared but its value is never read."},
+			{88, CodeUnusedPrivateMember, "Private method Report::export() is declared but never used."},
+			{99, CodeUnusedPrivateMember, "Private property Circle::$scale is declared but never used."},
+		}, results)
+		assert.Empty(t, UnusedDiagnostics(doc))
+	})
+}
+
+func TestUnusedArgumentDiagnostics(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := openDocument(store, "../cases/byRefArgument.php", "test1")
+		assert.Empty(t, UnusedDiagnostics(doc))
+		diagnostics := UnusedArgumentDiagnostics(NewResolveContext(NewQuery(store), doc))
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, 13, diagnostics[0].Range.Start.Line)
+		assert.Equal(t, "$name is declared but its value is never read.", diagnostics[0].Message)
+	})
+}
+
+func TestUndefinedVariableDiagnostics(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := openDocument(store, "../cases/undefinedVariable.php", "test1")
+		diagnostics := UndefinedVariableDiagnostics(NewResolveContext(NewQuery(store), doc))
+		type result struct {
+			line     int
+			code     interface{}
+			severity protocol.DiagnosticSeverity
+			message  string
+		}
+		results := []result{}
+		for _, diagnostic := range diagnostics {
+			results = append(results, result{diagnostic.Range.Start.Line, diagnostic.Code, diagnostic.Severity, diagnostic.Message})
+		}
+		assert.Equal(t, []result{
+			{11, CodeUndefinedVariable, protocol.SeverityWarning, "Undefined variable $totla."},
+			{24, CodePossiblyUndefinedVariable, protocol.SeverityInformation, "Variable $partial might not be defined."},
+			{47, CodePossiblyUndefinedVariable, protocol.SeverityInformation, "Variable $result might not be defined."},
+			{57, CodeUndefinedVariable, protocol.SeverityWarning, "Undefined variable $outer."},
+			{59, CodeUndefinedVariable, protocol.SeverityWarning, "Undefined variable $notCaptured."},
+			{66, CodeUndefinedVariable, protocol.SeverityWarning, "Undefined variable $before."},
+			{77, CodeUndefinedVariable, protocol.SeverityWarning, "Undefined variable $step."},
+			{81, CodePossiblyUndefinedVariable, protocol.SeverityInformation, "Variable $last might not be defined."},
+			{100, CodeUndefinedVariable, protocol.SeverityWarning, "Undefined variable $level."},
+			{101, CodeUndefinedVariable, protocol.SeverityWarning, "Undefined variable $message."},
+			{118, CodeUndefinedVariable, protocol.SeverityWarning, "Undefined variable $absent."},
+		}, results)
+	})
+}
+
+func TestDeadCodeDiagnostics(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := openDocument(store, "../cases/deadCode.php", "test1")
+		diagnostics := DeadCodeDiagnostics(NewResolveContext(NewQuery(store), doc))
+		type result struct {
+			start   protocol.Position
+			end     protocol.Position
+			code    interface{}
+			message string
+		}
+		results := []result{}
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Code == CodeUnreachableCode {
+				assert.Equal(t, []protocol.DiagnosticTag{protocol.Unnecessary}, diagnostic.Tags)
+			}
+			results = append(results, result{diagnostic.Range.Start, diagnostic.Range.End, diagnostic.Code, diagnostic.Message})
+		}
+		assert.Equal(t, []result{
+			{protocol.Position{Line: 33, Character: 4}, protocol.Position{Line: 33, Character: 23}, CodeUnreachableCode, "Unreachable code."},
+			{protocol.Position{Line: 24, Character: 8}, protocol.Position{Line: 24, Character: 21}, CodeUnreachableCode, "Unreachable code."},
+			{protocol.Position{Line: 29, Character: 12}, protocol.Position{Line: 29, Character: 22}, CodeUnreachableCode, "Unreachable code."},
+			{protocol.Position{Line: 40, Character: 8}, protocol.Position{Line: 40, Character: 13}, CodeConstantCondition, "Condition is always false."},
+			{protocol.Position{Line: 40, Character: 15}, protocol.Position{Line: 42, Character: 5}, CodeUnreachableCode, "Unreachable code."},
+			{protocol.Position{Line: 43, Character: 8}, protocol.Position{Line: 43, Character: 12}, CodeConstantCondition, "Condition is always true."},
+			{protocol.Position{Line: 45, Character: 6}, protocol.Position{Line: 49, Character: 5}, CodeUnreachableCode, "Unreachable code."},
+			{protocol.Position{Line: 58, Character: 13}, protocol.Position{Line: 58, Character: 29}, CodeNeverThrownException, "Exception StorageException is never thrown in the try block."},
+		}, results)
+	})
+}
+
+func TestDuplicateDeclarationDiagnostics(t *testing.T) {
+	// The workspace inside a vendor folder is not the vendor of another project
+	for i, root := range []string{"file:///project", "file:///srv/vendor/project"} {
+		withTestStore(root, t.Name()+strconv.Itoa(i), func(store *Store) {
+			testDuplicateDeclarationDiagnostics(t, store)
+		})
+	}
+}
+
+func testDuplicateDeclarationDiagnostics(t *testing.T, store *Store) {
+	root := store.GetURI()
+	indexDocument(store, "../cases/duplicate/copy.php", root+"/src/copy.php")
+	indexDocument(store, "../cases/duplicate/polyfill.php", root+"/src/polyfill.php")
+	indexDocument(store, "../cases/duplicate/vendor.php", root+"/vendor/acme/collection/vendor.php")
+	doc := openDocument(store, "../cases/duplicate/workspace.php", root+"/src/workspace.php")
+	diagnostics := DuplicateDeclarationDiagnostics(NewResolveContext(NewQuery(store), doc))
+	type result struct {
+		start   protocol.Position
+		message string
+		related []string
+	}
+	results := []result{}
+	for _, diagnostic := range diagnostics {
+		assert.Equal(t, CodeDuplicateDeclaration, diagnostic.Code)
+		related := []string{}
+		for _, info := range diagnostic.RelatedInformation {
+			related = append(related, info.Location.URI)
+		}
+		results = append(results, result{diagnostic.Range.Start, diagnostic.Message, related})
+	}
+	copyURI := []string{root + "/src/copy.php"}
+	assert.Equal(t, []result{
+		{protocol.Position{Line: 2, Character: 6}, "Class \\Collection is declared more than once.", copyURI},
+		{protocol.Position{Line: 9, Character: 10}, "Interface \\Arrayable is declared more than once.", copyURI},
+		{protocol.Position{Line: 13, Character: 9}, "Function \\helper is declared more than once.", copyURI},
+		{protocol.Position{Line: 17, Character: 6}, "Constant \\VERSION is declared more than once.", copyURI},
+	}, results)
+
+	vendorDoc := indexDocumentAndGet(store, "../cases/duplicate/vendor.php", root+"/vendor/acme/collection/vendor.php")
+	assert.Empty(t, DuplicateDeclarationDiagnostics(NewResolveContext(NewQuery(store), vendorDoc)))
+
+	// Anonymous classes are not declared more than once
+	anonymous := openDocument(store, "../cases/deadCode.php", root+"/src/deadCode.php")
+	assert.Empty(t, DuplicateDeclarationDiagnostics(NewResolveContext(NewQuery(store), anonymous)))
+}
+
+func TestSuppressDiagnostics(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := openDocument(store, "../cases/suppression.php", "test1")
+		diagnostics := DocumentDiagnostics(doc)
+		diagnostics = append(diagnostics, ResolvedDiagnostics(NewResolveContext(NewQuery(store), doc))...)
+		type result struct {
+			line    int
+			code    interface{}
+			message string
+		}
+		results := []result{}
+		for _, diagnostic := range diagnostics {
+			results = append(results, result{diagnostic.Range.Start.Line, diagnostic.Code, diagnostic.Message})
+		}
+		assert.ElementsMatch(t, []result{
+			{7, CodeUndefinedVariable, "Undefined variable $second."},
+			{9, CodeUndefinedVariable, "Undefined variable $third."},
+			{29, CodeUndefinedVariable, "Undefined variable $output."},
+			{47, CodeUnknownSuppressionCode, "Unknown diagnostic code undefined-varible."},
+			{48, CodeUndefinedVariable, "Undefined variable $typo."},
+			{50, CodeUndefinedVariable, "Undefined variable $reasonOnly."},
+		}, results)
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Code == CodeUnknownSuppressionCode {
+				assert.Equal(t, protocol.Range{
+					Start: protocol.Position{Line: 47, Character: 34},
+					End:   protocol.Position{Line: 47, Character: 51},
+				}, diagnostic.Range)
+			}
+		}
+	})
+}
+
+func TestSuppressionEdit(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := openDocument(store, "../cases/suppression.php", "test1")
+		diagnostic := func(line int, code string) protocol.Diagnostic {
+			return protocol.Diagnostic{
+				Range: protocol.Range{Start: protocol.Position{Line: line, Character: 9}},
+				Code:  code,
+			}
+		}
+		edit, ok := SuppressionEdit(doc, diagnostic(7, CodeUndefinedVariable))
+		assert.True(t, ok)
+		assert.Equal(t, protocol.TextEdit{
+			Range:   protocol.Range{Start: protocol.Position{Line: 7}, End: protocol.Position{Line: 7}},
+			NewText: "    // @phpintel-ignore-next-line undefined-variable\n",
+		}, edit)
+
+		edit, ok = SuppressionEdit(doc, diagnostic(6, CodeUnusedVariable))
+		assert.True(t, ok)
+		end := protocol.Position{Line: 5, Character: 52}
+		assert.Equal(t, protocol.TextEdit{
+			Range:   protocol.Range{Start: end, End: end},
+			NewText: ", unused-variable",
+		}, edit)
+
+		edit, ok = SuppressionEdit(doc, diagnostic(42, CodeUnusedVariable))
+		assert.True(t, ok)
+		end = protocol.Position{Line: 41, Character: 52}
+		assert.Equal(t, protocol.TextEdit{
+			Range:   protocol.Range{Start: end, End: end},
+			NewText: ", unused-variable",
+		}, edit)
+
+		edit, ok = SuppressionEdit(doc, diagnostic(50, CodeUndefinedVariable))
+		assert.True(t, ok)
+		end = protocol.Position{Line: 49, Character: 33}
+		assert.Equal(t, protocol.TextEdit{
+			Range:   protocol.Range{Start: end, End: end},
+			NewText: " undefined-variable",
+		}, edit)
+
+		_, ok = SuppressionEdit(doc, diagnostic(6, CodeUndefinedVariable))
+		assert.False(t, ok)
+		_, ok = SuppressionEdit(doc, diagnostic(7, ""))
+		assert.False(t, ok)
+	})
+}
@@ -10,7 +10,6 @@ import (
 	"unicode/utf8"
 
 	"github.com/john-nguyen09/go-phpparser/lexer"
-	"github.com/john-nguyen09/go-phpparser/parser"
 	"github.com/john-nguyen09/go-phpparser/phrase"
 	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
 	"github.com/john-nguyen09/phpintel/util"
@@ -38,6 +37,11 @@ type Document struct {
 	insertUseContext   *InsertUseContext
 
 	blockStack []BlockSymbol
+
+	attributeGroups []attributeGroup
+	attributeNodes  []*phrase.Phrase
+	attributeIndex  int
+	attributes      []*Attribute
 }
 
 // MarshalJSON is used for json.Marshal
@@ -83,7 +87,10 @@ func (s *Document) IsOpen() bool {
 func (s *Document) GetRootNode() *phrase.Phrase {
 	if s.rootNode == nil {
 		defer util.TimeTrack(time.Now(), "GetRootNode")
-		s.rootNode = parser.Parse(s.GetText())
+		s.rootNode, s.attributeGroups = parseSource(s.GetText())
+		if len(s.attributeGroups) > 0 {
+			s.attributeNodes = parseAttributeGroups(s.GetText(), s.attributeGroups)
+		}
 	}
 	return s.rootNode
 }
@@ -97,6 +104,7 @@ func (s *Document) Load() {
 	rootNode := s.GetRootNode()
 	s.pushVariableTable(rootNode)
 	scanForChildren(newAnalyser(), s, rootNode)
+	s.analyseAttributes(len(s.text) + 1)
 
 	if len(s.importTables) == 0 {
 		s.pushImportTable(rootNode)
@@ -181,6 +189,18 @@ func (s *Document) offsetAtLine(line int) int {
 	return s.lineOffsets[line]
 }
 
+// lineText returns the text of the line including the line ending
+func (s *Document) lineText(line int) string {
+	start, end := s.offsetAtLine(line), len(s.text)
+	if line+1 < len(s.lineOffsets) {
+		end = s.lineOffsets[line+1]
+	}
+	if start > end {
+		return ""
+	}
+	return string(s.text[start:end])
+}
+
 func (s *Document) positionAt(offset int) protocol.Position {
 	line := s.lineAt(offset)
 	return protocol.Position{
@@ -277,6 +297,9 @@ func (s *Document) addSymbol(other Symbol) {
 		s.lastPhpDoc = doc
 		return
 	}
+	if s.attributeIndex < len(s.attributeNodes) {
+		s.analyseAttributes(s.OffsetAtPosition(other.GetLocation().Range.Start))
+	}
 	if s.currentBlock() != nil {
 		s.currentBlock().addChild(other)
 	} else {
@@ -299,6 +322,35 @@ func (s *Document) currentBlock() BlockSymbol {
 	return nil
 }
 
+// currentMethod returns the method which the current node is in, the
+// closures of the method are in the method too
+func (s *Document) currentMethod() *Method {
+	for i := len(s.blockStack) - 1; i >= 0; i-- {
+		switch block := s.blockStack[i].(type) {
+		case *Method:
+			return block
+		case *Function:
+			return nil
+		}
+	}
+	return nil
+}
+
+// argumentOf returns the current argument list and the index of the node
+// if the node is one of its arguments
+func (s *Document) argumentOf(node phrase.AstNode) (*ArgumentList, int) {
+	args, ok := s.currentBlock().(*ArgumentList)
+	if !ok {
+		return nil, -1
+	}
+	for i, argument := range args.GetArguments() {
+		if argument == node {
+			return args, i
+		}
+	}
+	return nil, -1
+}
+
 func (s *Document) pushVariableTable(node *phrase.Phrase) {
 	newVarTable := newVariableTable(s.NodeRange(node), s.variableTableLevel)
 	if s.variableTableLevel > 0 {
@@ -372,7 +424,7 @@ func (s *Document) ClassAt(pos protocol.Position) Symbol {
 			return
 		} else if relativeRange == 0 {
 			switch s.(type) {
-			case *Class, *Interface, *Trait:
+			case *Class, *Interface, *Trait, *Enum:
 				found = s
 			}
 		} else {
@@ -390,6 +442,8 @@ func (s *Document) addClass(other Symbol) {
 		s.classStack = append(s.classStack, instance)
 	case *Trait:
 		s.classStack = append(s.classStack, instance)
+	case *Enum:
+		s.classStack = append(s.classStack, instance)
 	}
 }
 
@@ -408,6 +462,8 @@ func (s *Document) GetClassScopeAtSymbol(symbol Symbol) string {
 		return v.Name.GetFQN()
 	case *Interface:
 		return v.Name.GetFQN()
+	case *Enum:
+		return v.Name.GetFQN()
 	}
 	return ""
 }
@@ -629,6 +685,10 @@ func (s *Document) getValidPhpDoc(location protocol.Location) *phpDocComment {
 	}
 	endOfPhpDoc := s.lastPhpDoc.GetLocation().Range.End
 	start := location.Range.Start
+	if len(s.attributeGroups) > 0 {
+		// The attributes can be between the phpDoc and the declaration
+		start = s.positionAt(s.skipAttributeGroups(s.OffsetAtPosition(start)))
+	}
 	if endOfPhpDoc.Line < start.Line && endOfPhpDoc.Line == (start.Line-1) {
 		return s.lastPhpDoc
 	}
@@ -671,15 +731,58 @@ func (s *Document) setNamespace(namespace *Namespace) {
 	s.currImportTable().setNamespace(namespace)
 }
 
-// UnusedVariables return unused declared variables in the doc
+// UnusedVariables return unused declared variables in the doc, the variables
+// passed as arguments are excluded because the callees can take them by reference
 func (s *Document) UnusedVariables() []*Variable {
+	return s.unusedLocals(func(vt *VariableTable, name string) bool {
+		return len(vt.arguments[name]) == 0
+	})
+}
+
+// unusedArgumentVariables returns the unused variables which are passed as arguments
+// but none of them at the positions of byRef, i.e. to the parameters by reference
+func (s *Document) unusedArgumentVariables(byRef map[protocol.Position]struct{}) []*Variable {
+	return s.unusedLocals(func(vt *VariableTable, name string) bool {
+		positions := vt.arguments[name]
+		for _, pos := range positions {
+			if _, ok := byRef[pos]; ok {
+				return false
+			}
+		}
+		return len(positions) > 0
+	})
+}
+
+func (s *Document) unusedLocals(include func(*VariableTable, string) bool) []*Variable {
+	results := []*Variable{}
+	for _, variable := range s.unusedDeclarations(include) {
+		if !variable.isParameter {
+			results = append(results, variable)
+		}
+	}
+	return results
+}
+
+// unusedParameters returns the ranges of the parameters which are never read
+func (s *Document) unusedParameters() map[protocol.Range]struct{} {
+	results := map[protocol.Range]struct{}{}
+	all := func(*VariableTable, string) bool { return true }
+	for _, variable := range s.unusedDeclarations(all) {
+		if variable.isParameter {
+			results[variable.Location.Range] = struct{}{}
+		}
+	}
+	return results
+}
+
+func (s *Document) unusedDeclarations(include func(*VariableTable, string) bool) []*Variable {
 	results := []*Variable{}
 	queue := append([]*VariableTable(nil), s.variableTables...)
 	for len(queue) > 0 {
 		var varTable *VariableTable
 		varTable, queue = queue[0], queue[1:]
 		queue = append(queue, varTable.children...)
-		results = append(results, varTable.unusedVariables()...)
+		results = append(results, varTable.unusedVariables(include)...)
 	}
 	return results
 }
@@ -0,0 +1,105 @@
+package analysis
+
+import (
+	"time"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/stub"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// CodeDuplicateDeclaration is the code of the diagnostic for the classes,
+// functions or constants which are declared more than once in the workspace
+const CodeDuplicateDeclaration = "duplicate-declaration"
+
+type declaration struct {
+	location  protocol.Location
+	isGuarded bool
+}
+
+// isWorkspaceDeclaration checks whether the declaration belongs to the
+// workspace, which means it is neither in vendor, stubs nor guarded
+func (s *Store) isWorkspaceDeclaration(uri string, isGuarded bool) bool {
+	return !isGuarded && !s.isVendorURI(uri) && !stub.IsStub(uri)
+}
+
+// DuplicateDeclarationDiagnostics returns the diagnostics for the classes,
+// interfaces, traits, functions and constants of the document which are also
+// declared in other workspace files, vendor, stubs and guarded declarations
+// such as polyfills are not reported
+func DuplicateDeclarationDiagnostics(ctx ResolveContext) []protocol.Diagnostic {
+	defer util.TimeTrack(time.Now(), "DuplicateDeclarationDiagnostics")
+	diagnostics := []protocol.Diagnostic{}
+	doc := ctx.document
+	store := ctx.query.store
+	if !store.isWorkspaceDeclaration(doc.GetURI(), false) {
+		return diagnostics
+	}
+	report := func(kind string, name string, own declaration, others []declaration) {
+		if own.isGuarded {
+			return
+		}
+		related := []protocol.DiagnosticRelatedInformation{}
+		for _, other := range others {
+			if other.location.URI == own.location.URI || !store.isWorkspaceDeclaration(other.location.URI, other.isGuarded) {
+				continue
+			}
+			related = append(related, protocol.DiagnosticRelatedInformation{
+				Location: other.location,
+				Message:  "Also declared here.",
+			})
+		}
+		if len(related) == 0 {
+			return
+		}
+		diagnostic := newDiagnostic(CodeDuplicateDeclaration, own.location.Range, kind+" "+name+" is declared more than once.")
+		diagnostic.RelatedInformation = related
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	TraverseDocument(doc, func(s Symbol) {
+		others := []declaration{}
+		switch v := s.(type) {
+		case *Class:
+			if v.ReferenceLocation().URI == "" {
+				// Anonymous classes have no names to be declared again
+				break
+			}
+			for _, other := range store.GetClasses(GetClassFQNLowerCase(v.Name.GetFQN())) {
+				others = append(others, declaration{other.Location, other.isGuarded})
+			}
+			report("Class", v.Name.GetFQN(), declaration{v.ReferenceLocation(), v.isGuarded}, others)
+		case *Interface:
+			for _, other := range store.GetInterfaces(v.Name.GetFQN()) {
+				others = append(others, declaration{other.location, other.isGuarded})
+			}
+			report("Interface", v.Name.GetFQN(), declaration{v.ReferenceLocation(), v.isGuarded}, others)
+		case *Trait:
+			for _, other := range store.GetTraits(v.Name.GetFQN()) {
+				others = append(others, declaration{other.location, other.isGuarded})
+			}
+			report("Trait", v.Name.GetFQN(), declaration{v.location, v.isGuarded}, others)
+		case *Function:
+			for _, other := range store.GetFunctions(v.Name.GetFQN()) {
+				others = append(others, declaration{other.location, other.isGuarded})
+			}
+			report("Function", v.Name.GetFQN(), declaration{v.ReferenceLocation(), v.isGuarded}, others)
+		case *Const:
+			for _, other := range store.GetConsts(v.GetName()) {
+				others = append(others, declaration{other.location, false})
+			}
+			for _, other := range store.GetDefines(v.GetName()) {
+				others = append(others, declaration{other.location, other.isGuarded})
+			}
+			report("Constant", v.GetName(), declaration{v.location, false}, others)
+		case *Define:
+			for _, other := range store.GetDefines(v.GetName()) {
+				others = append(others, declaration{other.location, other.isGuarded})
+			}
+			for _, other := range store.GetConsts(v.GetName()) {
+				others = append(others, declaration{other.location, false})
+			}
+			report("Constant", v.GetName(), declaration{v.location, v.isGuarded}, others)
+		}
+	}, nil)
+	return diagnostics
+}
@@ -0,0 +1,266 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/john-nguyen09/go-phpparser/lexer"
+	"github.com/john-nguyen09/go-phpparser/phrase"
+	"github.com/john-nguyen09/phpintel/analysis/storage"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// UnitEnumFQN and BackedEnumFQN are the interfaces every enum implements implicitly
+const (
+	UnitEnumFQN   = "\\UnitEnum"
+	BackedEnumFQN = "\\BackedEnum"
+)
+
+// Enum contains information of enums
+type Enum struct {
+	description string
+	children    []Symbol
+	refLocation protocol.Location
+	Location    protocol.Location
+
+	Name       TypeString
+	BackedType TypeString
+	Interfaces []TypeString
+	Use        []TypeString
+
+	deprecatedTag *tag
+}
+
+var _ HasScope = (*Enum)(nil)
+var _ Symbol = (*Enum)(nil)
+var _ BlockSymbol = (*Enum)(nil)
+var _ SymbolReference = (*Enum)(nil)
+
+// isEnumDeclaration checks whether the class declaration is rewritten from an enum
+func isEnumDeclaration(document *Document, node *phrase.Phrase) bool {
+	for _, child := range node.Children {
+		if p, ok := child.(*phrase.Phrase); ok && p.Type == phrase.ClassDeclarationHeader {
+			for _, headerChild := range p.Children {
+				if t, ok := headerChild.(*lexer.Token); ok && t.Type == lexer.Class {
+					return strings.EqualFold(document.getTokenText(t), "enum")
+				}
+			}
+		}
+	}
+	return false
+}
+
+func newClassOrEnum(a analyser, document *Document, node *phrase.Phrase) Symbol {
+	if isEnumDeclaration(document, node) {
+		return newEnum(a, document, node)
+	}
+	return newClass(a, document, node)
+}
+
+func newEnum(a analyser, document *Document, node *phrase.Phrase) Symbol {
+	enum := &Enum{
+		Location: document.GetNodeLocation(node),
+	}
+	document.addClass(enum)
+	phpDoc := document.getValidPhpDoc(enum.Location)
+	document.addSymbol(enum)
+	document.pushBlock(enum)
+	traverser := util.NewTraverser(node)
+	for child := traverser.Advance(); child != nil; child = traverser.Advance() {
+		if p, ok := child.(*phrase.Phrase); ok {
+			switch p.Type {
+			case phrase.ClassDeclarationHeader:
+				enum.analyseHeader(document, p, phpDoc)
+				for _, prop := range enum.implicitProps() {
+					document.addSymbol(prop)
+				}
+			case phrase.ClassDeclarationBody:
+				scanForChildren(a, document, p)
+			}
+		}
+	}
+	document.popBlock()
+	return nil
+}
+
+func (s *Enum) analyseHeader(document *Document, node *phrase.Phrase, phpDoc *phpDocComment) {
+	traverser := util.NewTraverser(node)
+	for child := traverser.Advance(); child != nil; child = traverser.Advance() {
+		if token, ok := child.(*lexer.Token); ok && token.Type == lexer.Name {
+			s.Name = NewTypeString(document.getTokenText(token))
+			s.Name.SetNamespace(document.currImportTable().GetNamespace())
+			s.refLocation = document.GetNodeLocation(token)
+			if phpDoc != nil {
+				s.description = phpDoc.Description
+				s.deprecatedTag = phpDoc.deprecated()
+			}
+		} else if p, ok := child.(*phrase.Phrase); ok {
+			switch p.Type {
+			case phrase.ClassBaseClause:
+				// The backed type is parsed as the base class
+				for _, baseChild := range p.Children {
+					if p, ok := baseChild.(*phrase.Phrase); ok && p.Type == phrase.QualifiedName {
+						s.BackedType = transformQualifiedName(p, document)
+					}
+				}
+			case phrase.ClassInterfaceClause:
+				s.Interfaces = analyseInterfaceClause(document, p)
+			}
+		}
+	}
+}
+
+// implicitProps returns the readonly name and value properties of the cases
+func (s *Enum) implicitProps() []*Property {
+	props := []*Property{
+		s.newImplicitProp("$name", NewTypeString("string")),
+	}
+	if s.IsBacked() {
+		props = append(props, s.newImplicitProp("$value", s.BackedType))
+	}
+	return props
+}
+
+func (s *Enum) newImplicitProp(name string, typeString TypeString) *Property {
+	types := newTypeComposite()
+	types.add(typeString)
+	return &Property{
+		location:    s.refLocation,
+		refLocation: s.refLocation,
+
+		Name:               name,
+		Scope:              s.Name,
+		VisibilityModifier: Public,
+		Types:              types,
+	}
+}
+
+// IsBacked returns whether the enum has a backed type, e.g. enum Status: string
+func (s *Enum) IsBacked() bool {
+	return !s.BackedType.IsEmpty()
+}
+
+// AllInterfaces returns the declared interfaces and the implicit UnitEnum
+// or BackedEnum interfaces
+func (s *Enum) AllInterfaces() []TypeString {
+	interfaces := append([]TypeString{}, s.Interfaces...)
+	if s.IsBacked() {
+		interfaces = append(interfaces, NewTypeString(BackedEnumFQN))
+	} else {
+		interfaces = append(interfaces, NewTypeString(UnitEnumFQN))
+	}
+	return interfaces
+}
+
+// asClass returns the enum as a final class so that the members
+// are looked up in the same way as classes
+func (s *Enum) asClass() *Class {
+	return &Class{
+		description: s.description,
+		refLocation: s.refLocation,
+		Location:    s.Location,
+
+		Modifier:   Final,
+		Name:       s.Name,
+		Interfaces: s.AllInterfaces(),
+		Use:        s.Use,
+
+		deprecatedTag: s.deprecatedTag,
+		enum:          s,
+	}
+}
+
+func (s *Enum) GetLocation() protocol.Location {
+	return s.Location
+}
+
+func (s *Enum) GetName() string {
+	return s.Name.original
+}
+
+func (s *Enum) GetDescription() string {
+	return s.description
+}
+
+func (s *Enum) GetCollection() string {
+	return enumCollection
+}
+
+func (s *Enum) GetKey() string {
+	return GetClassFQNLowerCase(s.Name.GetFQN()) + KeySep + s.Location.URI
+}
+
+func (s *Enum) GetIndexableName() string {
+	return s.Name.GetOriginal()
+}
+
+func (s *Enum) GetIndexCollection() string {
+	return enumCompletionIndex
+}
+
+func (s *Enum) GetScope() string {
+	return s.Name.GetNamespace()
+}
+
+func (s *Enum) IsScopeSymbol() bool {
+	return false
+}
+
+func (s *Enum) Serialise(e *storage.Encoder) {
+	e.WriteLocation(s.Location)
+	e.WriteLocation(s.refLocation)
+	e.WriteString(s.description)
+	s.Name.Write(e)
+	s.BackedType.Write(e)
+	e.WriteInt(len(s.Interfaces))
+	for _, theInterface := range s.Interfaces {
+		theInterface.Write(e)
+	}
+	e.WriteInt(len(s.Use))
+	for _, use := range s.Use {
+		use.Write(e)
+	}
+	serialiseDeprecatedTag(e, s.deprecatedTag)
+}
+
+func ReadEnum(d *storage.Decoder) *Enum {
+	enum := &Enum{
+		Location:    d.ReadLocation(),
+		refLocation: d.ReadLocation(),
+		description: d.ReadString(),
+		Name:        ReadTypeString(d),
+		BackedType:  ReadTypeString(d),
+	}
+	numInterfaces := d.ReadInt()
+	for i := 0; i < numInterfaces; i++ {
+		enum.Interfaces = append(enum.Interfaces, ReadTypeString(d))
+	}
+	numUse := d.ReadInt()
+	for i := 0; i < numUse; i++ {
+		enum.Use = append(enum.Use, ReadTypeString(d))
+	}
+	enum.deprecatedTag = deserialiseDeprecatedTag(d)
+	return enum
+}
+
+func (s *Enum) AddUse(name TypeString) {
+	s.Use = append(s.Use, name)
+}
+
+func (s *Enum) addChild(child Symbol) {
+	s.children = append(s.children, child)
+}
+
+func (s *Enum) GetChildren() []Symbol {
+	return s.children
+}
+
+// ReferenceFQN returns the FQN of the enum's name
+func (s *Enum) ReferenceFQN() string {
+	return s.Name.GetFQN()
+}
+
+// ReferenceLocation returns the location of the enum's name
+func (s *Enum) ReferenceLocation() protocol.Location {
+	return s.refLocation
+}
@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy"
+	"github.com/john-nguyen09/phpintel/analysis/storage"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnum(t *testing.T) {
+	data, err := ioutil.ReadFile("../cases/enum.php")
+	if err != nil {
+		panic(err)
+	}
+	document := NewDocument("test1", data)
+	document.Load()
+	cupaloy.SnapshotT(t, document.Children)
+	assert.Empty(t, GetParserDiagnostics(document))
+}
+
+func TestEnumSerialiseAndDeserialise(t *testing.T) {
+	data, err := ioutil.ReadFile("../cases/enum.php")
+	if err != nil {
+		panic(err)
+	}
+	document := NewDocument("test1", data)
+	document.Load()
+	for _, child := range document.Children {
+		if enum, ok := child.(*Enum); ok {
+			e := storage.NewEncoder()
+			enum.Serialise(e)
+			deserialised := ReadEnum(storage.NewDecoder(e.Bytes()))
+			enum.children = nil
+			assert.Equal(t, enum, deserialised)
+		}
+	}
+}
+
+func TestEnumMembers(t *testing.T) {
+	data, err := ioutil.ReadFile("../cases/enum.php")
+	if err != nil {
+		panic(err)
+	}
+	stubData, err := ioutil.ReadFile("../stub/phpintel-stubs/Core/enum.php")
+	if err != nil {
+		panic(err)
+	}
+	withTestStore("test", t.Name(), func(store *Store) {
+		stubDoc := NewDocument("stub", stubData)
+		stubDoc.Load()
+		store.SyncDocument(stubDoc)
+		doc := NewDocument("test1", data)
+		doc.Load()
+		store.SyncDocument(doc)
+		q := NewQuery(store)
+		ctx := NewResolveContext(q, doc)
+
+		enums := q.GetEnums("\\App\\Enums\\Status")
+		assert.Equal(t, 1, len(enums))
+		assert.Equal(t, "string", enums[0].BackedType.GetOriginal())
+		classes := q.GetClasses("\\App\\Enums\\Status")
+		assert.Equal(t, 1, len(classes))
+		assert.Equal(t, enums[0].Name, classes[0].Enum().Name)
+
+		cases := q.SearchClassClassConsts(classes[0], "", nil).Consts
+		names := []string{}
+		for _, c := range cases {
+			if c.Const.IsEnumCase() {
+				names = append(names, c.Const.Name)
+			}
+		}
+		assert.ElementsMatch(t, []string{"Pending", "Shipped"}, names)
+
+		status := doc.HasTypesAtPos(protocol.Position{Line: 39, Character: 3})
+		status.Resolve(ctx)
+		assert.Equal(t, "\\App\\Enums\\Status", status.GetTypes().ToString())
+		value := doc.HasTypesAtPos(protocol.Position{Line: 40, Character: 11})
+		value.Resolve(ctx)
+		assert.Equal(t, "string", value.GetTypes().ToString())
+		label := doc.HasTypesAtPos(protocol.Position{Line: 41, Character: 11})
+		label.Resolve(ctx)
+		assert.Equal(t, "string", label.GetTypes().ToString())
+		from := doc.HasTypesAtPos(protocol.Position{Line: 42, Character: 10})
+		from.Resolve(ctx)
+		assert.Equal(t, "\\App\\Enums\\Status", from.GetTypes().ToString())
+		suitCases := doc.HasTypesAtPos(protocol.Position{Line: 43, Character: 8})
+		suitCases.Resolve(ctx)
+		assert.Equal(t, "\\App\\Enums\\Suit[]", suitCases.GetTypes().ToString())
+	})
+}
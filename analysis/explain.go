@@ -0,0 +1,225 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+// SymbolDump is the debugging view of a symbol and its children, the
+// types are the ones known when the document is analysed
+type SymbolDump struct {
+	Kind     string         `json:"kind"`
+	Name     string         `json:"name,omitempty"`
+	Range    protocol.Range `json:"range"`
+	Types    []string       `json:"types,omitempty"`
+	Children []SymbolDump   `json:"children,omitempty"`
+}
+
+// ImportTableDump is the debugging view of the namespace and the use
+// statements of a part of a document
+type ImportTableDump struct {
+	Start     protocol.Position `json:"start"`
+	Namespace string            `json:"namespace"`
+	Classes   map[string]string `json:"classes"`
+	Functions map[string]string `json:"functions"`
+	Constants map[string]string `json:"constants"`
+}
+
+// VariableTableDump is the debugging view of the variables of a scope,
+// every assignment of a variable is listed
+type VariableTableDump struct {
+	Range     protocol.Range      `json:"range"`
+	Level     int                 `json:"level"`
+	Variables []VariableDump      `json:"variables"`
+	Children  []VariableTableDump `json:"children,omitempty"`
+}
+
+// VariableDump is a variable from the position where it is assigned
+type VariableDump struct {
+	Name          string            `json:"name"`
+	Start         protocol.Position `json:"start"`
+	Types         []string          `json:"types"`
+	IsDeclaration bool              `json:"isDeclaration"`
+}
+
+// DocumentDump is the debugging view of a document
+type DocumentDump struct {
+	URI           string            `json:"uri"`
+	Symbols       []SymbolDump      `json:"symbols"`
+	ImportTables  []ImportTableDump `json:"importTables"`
+	VariableTable VariableTableDump `json:"variableTable"`
+}
+
+// PositionExplanation explains the symbol at a position, the trace has the
+// lookups of the query which resolved the symbol
+type PositionExplanation struct {
+	Spine         []string          `json:"spine"`
+	Symbol        *SymbolDump       `json:"symbol"`
+	ScopeTypes    []string          `json:"scopeTypes,omitempty"`
+	Types         []string          `json:"types"`
+	ImportTable   ImportTableDump   `json:"importTable"`
+	VariableTable VariableTableDump `json:"variableTable"`
+	Definitions   []TraceSymbol     `json:"definitions"`
+	Trace         *Trace            `json:"trace"`
+}
+
+// DumpDocument returns the symbol tree, the import tables and the variable
+// tables of the loaded document
+func DumpDocument(document *Document) DocumentDump {
+	dump := DocumentDump{
+		URI:          document.GetURI(),
+		Symbols:      []SymbolDump{},
+		ImportTables: []ImportTableDump{},
+	}
+	var stack []*SymbolDump
+	TraverseDocument(document, func(s Symbol) {
+		symbol := newSymbolDump(s)
+		if len(stack) == 0 {
+			dump.Symbols = append(dump.Symbols, symbol)
+			stack = append(stack, &dump.Symbols[len(dump.Symbols)-1])
+			return
+		}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, symbol)
+		stack = append(stack, &parent.Children[len(parent.Children)-1])
+	}, func(s Symbol) {
+		stack = stack[:len(stack)-1]
+	})
+	for _, importTable := range document.importTables {
+		dump.ImportTables = append(dump.ImportTables, newImportTableDump(importTable))
+	}
+	if len(document.variableTables) > 0 {
+		dump.VariableTable = newVariableTableDump(document.variableTables[0], true)
+	}
+	return dump
+}
+
+// ExplainPosition explains the symbol at the position, the lookups of its
+// resolution are recorded into the trace of the query so the callers can
+// look up more, e.g. the definitions, and have them in the same trace
+func ExplainPosition(q *Query, document *Document, pos protocol.Position) PositionExplanation {
+	if q.trace == nil {
+		q.WithTrace()
+	}
+	explanation := PositionExplanation{
+		Spine:       []string{},
+		Types:       []string{},
+		ImportTable: newImportTableDump(document.ImportTableAtPos(pos)),
+		Definitions: []TraceSymbol{},
+		Trace:       q.trace,
+	}
+	spine := document.NodeSpineAt(document.OffsetAtPosition(pos))
+	if token := spine.Token(); token.Length > 0 {
+		explanation.Spine = append(explanation.Spine, token.Type.String())
+	}
+	parents := spine.Parents()
+	for i := len(parents) - 1; i >= 0; i-- {
+		explanation.Spine = append(explanation.Spine, parents[i].Type.String())
+	}
+	if len(document.variableTables) > 0 {
+		explanation.VariableTable = newVariableTableDump(document.GetVariableTableAt(pos), false)
+	}
+	h := document.HasTypesAtPos(pos)
+	if h == nil {
+		return explanation
+	}
+	symbol := newSymbolDump(h)
+	explanation.Symbol = &symbol
+	resolveCtx := NewResolveContext(q, document)
+	if m, ok := h.(MemberAccess); ok {
+		if scope, ok := h.(interface {
+			ResolveAndGetScope(ResolveContext) TypeComposite
+		}); ok {
+			explanation.ScopeTypes = typeStrings(scope.ResolveAndGetScope(resolveCtx))
+		} else {
+			explanation.ScopeTypes = typeStrings(m.ScopeTypes())
+		}
+	}
+	h.Resolve(resolveCtx)
+	explanation.Types = typeStrings(h.GetTypes())
+	return explanation
+}
+
+func newSymbolDump(s Symbol) SymbolDump {
+	dump := SymbolDump{
+		Kind:  strings.TrimPrefix(fmt.Sprintf("%T", s), "*analysis."),
+		Name:  SymbolFQN(s),
+		Range: s.GetLocation().Range,
+	}
+	if dump.Name == "" {
+		switch v := s.(type) {
+		case HasName:
+			dump.Name = v.GetName()
+		case HasTypesHasScope:
+			dump.Name = v.MemberName()
+		case *Variable:
+			dump.Name = v.Name
+		}
+	}
+	if h, ok := s.(HasTypes); ok {
+		dump.Types = typeStrings(h.GetTypes())
+	}
+	return dump
+}
+
+func typeStrings(types TypeComposite) []string {
+	results := []string{}
+	for _, typeString := range types.Resolve() {
+		results = append(results, typeString.ToString())
+	}
+	return results
+}
+
+func newImportTableDump(importTable *ImportTable) ImportTableDump {
+	dump := ImportTableDump{
+		Start:     importTable.start,
+		Namespace: importTable.GetNamespace(),
+		Classes:   map[string]string{},
+		Functions: map[string]string{},
+		Constants: map[string]string{},
+	}
+	for alias, item := range importTable.classes {
+		dump.Classes[alias] = item.name
+	}
+	for alias, item := range importTable.functions {
+		dump.Functions[alias] = item.name
+	}
+	for alias, item := range importTable.constants {
+		dump.Constants[alias] = item.name
+	}
+	return dump
+}
+
+func newVariableTableDump(vt *VariableTable, withChildren bool) VariableTableDump {
+	dump := VariableTableDump{
+		Range:     vt.locationRange,
+		Level:     vt.level,
+		Variables: []VariableDump{},
+	}
+	for name, ctxVars := range vt.variables {
+		for _, ctxVar := range ctxVars {
+			dump.Variables = append(dump.Variables, VariableDump{
+				Name:          name,
+				Start:         ctxVar.start,
+				Types:         typeStrings(ctxVar.v.GetTypes()),
+				IsDeclaration: ctxVar.isDeclaration,
+			})
+		}
+	}
+	sort.Slice(dump.Variables, func(i, j int) bool {
+		a, b := dump.Variables[i], dump.Variables[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return protocol.ComparePos(a.Start, b.Start) < 0
+	})
+	if withChildren {
+		for _, child := range vt.children {
+			dump.Children = append(dump.Children, newVariableTableDump(child, true))
+		}
+	}
+	return dump
+}
@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpDocument(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := openDocument(store, "../cases/argumentDiagnostic.php", "test1")
+		dump := DumpDocument(doc)
+		assert.Equal(t, "test1", dump.URI)
+		var mailer *SymbolDump
+		for i, symbol := range dump.Symbols {
+			if symbol.Kind == "Class" && symbol.Name == "\\App\\Mailer" {
+				mailer = &dump.Symbols[i]
+			}
+		}
+		if assert.NotNil(t, mailer) {
+			methods := []string{}
+			for _, child := range mailer.Children {
+				if child.Kind == "Method" {
+					methods = append(methods, child.Name)
+				}
+			}
+			assert.Equal(t, []string{"\\App\\Mailer::__construct()", "\\App\\Mailer::send()", "\\App\\Mailer::create()"}, methods)
+		}
+		assert.Equal(t, "App", dump.ImportTables[len(dump.ImportTables)-1].Namespace)
+		names := map[string]struct{}{}
+		for _, variable := range dump.VariableTable.Variables {
+			names[variable.Name] = struct{}{}
+		}
+		assert.Contains(t, names, "$mailer")
+	})
+}
+
+func TestExplainPosition(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := openDocument(store, "../cases/argumentDiagnostic.php", "test1")
+		q := NewQuery(store)
+		explanation := ExplainPosition(q, doc, protocol.Position{Line: 32, Character: 11})
+		if assert.NotNil(t, explanation.Symbol) {
+			assert.Equal(t, "MethodAccess", explanation.Symbol.Kind)
+			assert.Equal(t, "send()", explanation.Symbol.Name)
+		}
+		assert.Equal(t, []string{"\\App\\Mailer"}, explanation.ScopeTypes)
+		assert.Contains(t, explanation.Spine, "MethodCallExpression")
+		assert.Equal(t, "App", explanation.ImportTable.Namespace)
+
+		var methods *TraceStep
+		for i, step := range explanation.Trace.Steps {
+			if step.Lookup == "methods" && step.Name == "\\App\\Mailer::send" {
+				methods = &explanation.Trace.Steps[i]
+			}
+		}
+		if assert.NotNil(t, methods) {
+			assert.Len(t, methods.Found, 1)
+			assert.Equal(t, "\\App\\Mailer::send()", methods.Found[0].Name)
+		}
+	})
+}
+
+func TestStoreStats(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		openDocument(store, "../cases/argumentDiagnostic.php", "test1")
+		stats := store.Stats()
+		assert.Equal(t, 1, stats.Collections[documentCollection])
+		assert.Equal(t, 2, stats.Collections[classCollection])
+		assert.Equal(t, 4, stats.Collections[methodCollection])
+		assert.NotZero(t, stats.CompletionCount)
+	})
+}
+
+func TestStorageStats(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		openDocument(store, "../cases/argumentDiagnostic.php", "test1")
+		stats := store.StorageStats()
+		assert.Equal(t, "test", stats.URI)
+		assert.Empty(t, stats.SymbolsLevelDB)
+		assert.NotZero(t, stats.DocumentCount)
+		assert.NotZero(t, stats.DocumentPuts)
+	})
+}
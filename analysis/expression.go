@@ -18,11 +18,58 @@ type Expression struct {
 type ResolveContext struct {
 	query    *Query
 	document *Document
+	callees  *callees
 }
 
 // NewResolveContext creates a resolve context
 func NewResolveContext(query *Query, document *Document) ResolveContext {
-	return ResolveContext{query, document}
+	return ResolveContext{query, document, &callees{}}
+}
+
+// callees are the calls of the document by the start of their names, they
+// are collected and resolved once for all the diagnostics of the document
+type callees struct {
+	symbols    map[protocol.Position]HasParamsResolvable
+	candidates map[protocol.Position][]HasParams
+}
+
+// calleeAt returns the callee of which the name starts at the position
+func (ctx ResolveContext) calleeAt(pos protocol.Position) (HasParamsResolvable, bool) {
+	if ctx.callees == nil {
+		return nil, false
+	}
+	if ctx.callees.symbols == nil {
+		ctx.callees.symbols = map[protocol.Position]HasParamsResolvable{}
+		ctx.callees.candidates = map[protocol.Position][]HasParams{}
+		TraverseDocument(ctx.document, func(s Symbol) {
+			if callee, ok := s.(HasParamsResolvable); ok {
+				ctx.callees.symbols[s.GetLocation().Range.Start] = callee
+			}
+		}, nil)
+	}
+	callee, ok := ctx.callees.symbols[pos]
+	return callee, ok
+}
+
+// resolveCallee returns the functions or methods of the callee
+func (ctx ResolveContext) resolveCallee(callee HasParamsResolvable) []HasParams {
+	if ctx.callees == nil {
+		return callee.ResolveToHasParams(ctx)
+	}
+	s, ok := callee.(Symbol)
+	if !ok {
+		return callee.ResolveToHasParams(ctx)
+	}
+	pos := s.GetLocation().Range.Start
+	if c, ok := ctx.calleeAt(pos); !ok || c != callee {
+		return callee.ResolveToHasParams(ctx)
+	}
+	if candidates, ok := ctx.callees.candidates[pos]; ok {
+		return candidates
+	}
+	candidates := callee.ResolveToHasParams(ctx)
+	ctx.callees.candidates[pos] = candidates
+	return candidates
 }
 
 func (e *Expression) Resolve(ctx ResolveContext) {
@@ -84,6 +131,9 @@ func init() {
 		phrase.CompoundAssignmentExpression:   newAssignment,
 		phrase.InstanceOfExpression:           processInstanceOfExpression,
 		phrase.InstanceofTypeDesignator:       newInstanceOfTypeDesignator,
+
+		phrase.AnonymousFunctionCreationExpression: newAnonymousFunction,
+		phrase.ArrowFunctionCreationExpression:     newArrowFunction,
 	}
 }
 
@@ -16,8 +16,12 @@ type Function struct {
 	Name          TypeString `json:"Name"`
 	Params        []*Parameter
 	returnTypes   TypeComposite
+	throwTypes    TypeComposite
 	description   string
 	deprecatedTag *tag
+
+	usesFuncGetArgs bool
+	isGuarded       bool
 }
 
 var _ HasScope = (*Function)(nil)
@@ -30,6 +34,7 @@ func newFunction(a analyser, document *Document, node *phrase.Phrase) Symbol {
 		location:    document.GetNodeLocation(node),
 		Params:      make([]*Parameter, 0),
 		returnTypes: newTypeComposite(),
+		isGuarded:   isExistenceGuarded(a, document),
 	}
 	phpDoc := document.getValidPhpDoc(function.location)
 	document.pushVariableTable(node)
@@ -48,6 +53,9 @@ func newFunction(a analyser, document *Document, node *phrase.Phrase) Symbol {
 				for _, param := range function.Params {
 					lastToken := util.LastToken(p)
 					variableTable.add(a, param.ToVariable(), document.positionAt(lastToken.Offset+lastToken.Length), true)
+					if param.IsReference() {
+						variableTable.setReference(param.Name)
+					}
 				}
 			case phrase.FunctionDeclarationBody:
 				scanForChildren(a, document, p)
@@ -107,6 +115,9 @@ func (s *Function) applyPhpDoc(document *Document, phpDoc phpDocComment) {
 			s.Params[index].description = tag.Description
 		}
 	}
+	for _, tag := range phpDoc.Throws {
+		s.throwTypes.merge(typesFromPhpDoc(document, tag.TypeString))
+	}
 	s.description = phpDoc.Description
 	s.deprecatedTag = phpDoc.deprecated()
 }
@@ -127,6 +138,11 @@ func (s *Function) GetDetail() string {
 	return s.returnTypes.ToString()
 }
 
+// GetThrowTypes returns the exception types from @throws
+func (s *Function) GetThrowTypes() TypeComposite {
+	return s.throwTypes
+}
+
 func (s *Function) GetReturnTypes() TypeComposite {
 	return s.returnTypes
 }
@@ -173,6 +189,9 @@ func (s *Function) Serialise(e *storage.Encoder) {
 	s.returnTypes.Write(e)
 	e.WriteString(s.description)
 	serialiseDeprecatedTag(e, s.deprecatedTag)
+	e.WriteBool(s.usesFuncGetArgs)
+	s.throwTypes.Write(e)
+	e.WriteBool(s.isGuarded)
 }
 
 func ReadFunction(d *storage.Decoder) *Function {
@@ -188,6 +207,9 @@ func ReadFunction(d *storage.Decoder) *Function {
 	function.returnTypes = ReadTypeComposite(d)
 	function.description = d.ReadString()
 	function.deprecatedTag = deserialiseDeprecatedTag(d)
+	function.usesFuncGetArgs = d.ReadBool()
+	function.throwTypes = ReadTypeComposite(d)
+	function.isGuarded = d.ReadBool()
 	return &function
 }
 
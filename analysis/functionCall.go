@@ -3,6 +3,7 @@ package analysis
 import (
 	"strings"
 
+	"github.com/john-nguyen09/go-phpparser/lexer"
 	"github.com/john-nguyen09/go-phpparser/phrase"
 	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
 	"github.com/john-nguyen09/phpintel/util"
@@ -43,22 +44,33 @@ func newFunctionCall(a analyser, document *Document, node *phrase.Phrase) (HasTy
 	functionCall := &FunctionCall{
 		Expression: Expression{},
 	}
-	document.addSymbol(functionCall)
 	traverser := util.NewTraverser(node)
 	child := traverser.Advance()
 	firstChild := child
+	if p, ok := firstChild.(*phrase.Phrase); ok && p.Type == phrase.SimpleVariable {
+		// The variable is added before the call so that the call is the
+		// closest symbol to the argument list
+		functionCall.Scope = scanForExpression(a, document, p)
+	}
+	document.addSymbol(functionCall)
 	if firstChild != nil {
-		if p, ok := firstChild.(*phrase.Phrase); ok && p.Type == phrase.SimpleVariable {
-			scanForExpression(a, document, p)
-		}
 		functionCall.Location = document.GetNodeLocation(firstChild)
 		functionCall.Name = document.GetNodeText(firstChild)
 		document.currImportTable().useFunctionOrClass(NewTypeString(functionCall.Name))
+		if isFuncGetArgs(functionCall.Name) {
+			document.markFuncGetArgs()
+		}
+		if readsAllVariables(functionCall.Name) {
+			document.getCurrentVariableTable().setDynamic()
+		}
 	}
 	for child != nil {
 		if p, ok := child.(*phrase.Phrase); ok {
 			if p.Type == phrase.ArgumentExpressionList {
 				scanNode(a, document, p)
+				if isCompact(functionCall.Name) {
+					document.markCompactVariablesUsed(p)
+				}
 				break
 			}
 		}
@@ -67,6 +79,69 @@ func newFunctionCall(a analyser, document *Document, node *phrase.Phrase) (HasTy
 	return functionCall, false
 }
 
+// isFuncGetArgs checks whether the function reads the arguments dynamically
+func isFuncGetArgs(name string) bool {
+	switch strings.ToLower(strings.TrimPrefix(name, "\\")) {
+	case "func_get_args", "func_get_arg", "func_num_args":
+		return true
+	}
+	return false
+}
+
+// readsAllVariables checks whether the function can read any variable of the scope
+func readsAllVariables(name string) bool {
+	switch strings.ToLower(strings.TrimPrefix(name, "\\")) {
+	case "extract", "get_defined_vars":
+		return true
+	}
+	return false
+}
+
+func isCompact(name string) bool {
+	return strings.ToLower(strings.TrimPrefix(name, "\\")) == "compact"
+}
+
+// markCompactVariablesUsed marks the variables named by the string literals
+// of compact() arguments as used, the names can also be in nested arrays
+func (s *Document) markCompactVariablesUsed(args *phrase.Phrase) {
+	variableTable := s.getCurrentVariableTable()
+	pos := s.NodeRange(args).Start
+	util.NewTraverser(args).Traverse(func(node phrase.AstNode, _ []*phrase.Phrase) util.VisitorContext {
+		switch v := node.(type) {
+		case *lexer.Token:
+			if v.Type == lexer.StringLiteral {
+				text := s.getTokenText(v)
+				if len(text) >= 2 {
+					variableTable.markUsed("$"+text[1:len(text)-1], pos)
+				}
+			}
+		case *phrase.Phrase:
+			switch v.Type {
+			case phrase.ArgumentExpressionList, phrase.ArrayCreationExpression, phrase.ArrayInitialiserList,
+				phrase.ArrayElement, phrase.ArrayValue:
+				return util.VisitorContext{ShouldAscend: true}
+			}
+		}
+		return util.VisitorContext{ShouldAscend: false}
+	})
+}
+
+// markFuncGetArgs marks the enclosing function or method as reading its arguments dynamically
+func (s *Document) markFuncGetArgs() {
+	for i := len(s.blockStack) - 1; i >= 0; i-- {
+		switch v := s.blockStack[i].(type) {
+		case *Function:
+			v.usesFuncGetArgs = true
+			return
+		case *Method:
+			v.usesFuncGetArgs = true
+			return
+		case *AnonymousFunction:
+			return
+		}
+	}
+}
+
 func (s *FunctionCall) GetLocation() protocol.Location {
 	return s.Location
 }
@@ -78,6 +153,12 @@ func (s *FunctionCall) Resolve(ctx ResolveContext) {
 	document := ctx.document
 	q := ctx.query
 	s.hasResolved = true
+	if s.Scope != nil {
+		for _, signature := range s.resolveSignatures(ctx) {
+			s.Type.merge(signature.Returns)
+		}
+		return
+	}
 	typeString := NewTypeString(s.Name)
 	functions := q.GetFunctions(document.currImportTable().GetFunctionReferenceFQN(ctx.query, typeString))
 	for _, function := range functions {
@@ -91,6 +172,12 @@ func (s *FunctionCall) GetTypes() TypeComposite {
 
 func (s *FunctionCall) ResolveToHasParams(ctx ResolveContext) []HasParams {
 	functions := []HasParams{}
+	if s.Scope != nil {
+		for _, signature := range s.resolveSignatures(ctx) {
+			functions = append(functions, callableHasParams{s.Name, signature})
+		}
+		return functions
+	}
 	typeString := NewTypeString(s.Name)
 	q := ctx.query
 	document := ctx.document
@@ -100,3 +187,34 @@ func (s *FunctionCall) ResolveToHasParams(ctx ResolveContext) []HasParams {
 	}
 	return functions
 }
+
+// resolveSignatures returns the signatures of the called variable, e.g. $fn()
+func (s *FunctionCall) resolveSignatures(ctx ResolveContext) []*CallableSignature {
+	signatures := []*CallableSignature{}
+	for _, typeString := range s.ResolveAndGetScope(ctx).Resolve() {
+		if signature := typeString.GetSignature(); signature != nil {
+			signatures = append(signatures, signature)
+		}
+	}
+	return signatures
+}
+
+// callableHasParams provides the params of a callable signature
+type callableHasParams struct {
+	name      string
+	signature *CallableSignature
+}
+
+var _ HasParams = (*callableHasParams)(nil)
+
+func (c callableHasParams) GetParams() []*Parameter {
+	return c.signature.Params
+}
+
+func (c callableHasParams) GetDescription() string {
+	return ""
+}
+
+func (c callableHasParams) GetNameLabel() string {
+	return c.name
+}
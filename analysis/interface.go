@@ -17,6 +17,8 @@ type Interface struct {
 	deprecatedTag *tag
 	Name          TypeString
 	Extends       []TypeString
+	Mixins        []TypeString
+	isGuarded     bool
 }
 
 var _ HasScope = (*Interface)(nil)
@@ -26,7 +28,8 @@ var _ SymbolReference = (*Interface)(nil)
 
 func newInterface(a analyser, document *Document, node *phrase.Phrase) Symbol {
 	theInterface := &Interface{
-		location: document.GetNodeLocation(node),
+		location:  document.GetNodeLocation(node),
+		isGuarded: isExistenceGuarded(a, document),
 	}
 	document.addClass(theInterface)
 	phpDoc := document.getValidPhpDoc(theInterface.location)
@@ -61,6 +64,7 @@ func (s *Interface) analyseHeader(document *Document, node *phrase.Phrase, phpDo
 				s.refLocation = document.GetNodeLocation(token)
 				if phpDoc != nil {
 					s.description = phpDoc.Description
+					s.Mixins = phpDoc.mixinTypes(document)
 					s.deprecatedTag = phpDoc.deprecated()
 				}
 			}
@@ -85,8 +89,10 @@ func (s *Interface) extends(document *Document, node *phrase.Phrase) {
 			}
 			child = traverser.Advance()
 			for child != nil {
-				if p, ok := child.(*phrase.Phrase); ok && p.Type == phrase.QualifiedName {
-					s.Extends = append(s.Extends, transformQualifiedName(p, document))
+				if p, ok := child.(*phrase.Phrase); ok && (p.Type == phrase.QualifiedName || p.Type == phrase.FullyQualifiedName) {
+					extend := transformQualifiedName(p, document)
+					extend.SetFQN(document.currImportTable().GetClassReferenceFQN(extend))
+					s.Extends = append(s.Extends, extend)
 				}
 				child = traverser.Advance()
 			}
@@ -135,8 +141,13 @@ func (s *Interface) Serialise(e *storage.Encoder) {
 	for _, extend := range s.Extends {
 		extend.Write(e)
 	}
+	e.WriteInt(len(s.Mixins))
+	for _, mixin := range s.Mixins {
+		mixin.Write(e)
+	}
 	e.WriteString(s.description)
 	serialiseDeprecatedTag(e, s.deprecatedTag)
+	e.WriteBool(s.isGuarded)
 }
 
 func ReadInterface(d *storage.Decoder) *Interface {
@@ -148,8 +159,13 @@ func ReadInterface(d *storage.Decoder) *Interface {
 	for i := 0; i < countExtends; i++ {
 		theInterface.Extends = append(theInterface.Extends, ReadTypeString(d))
 	}
+	countMixins := d.ReadInt()
+	for i := 0; i < countMixins; i++ {
+		theInterface.Mixins = append(theInterface.Mixins, ReadTypeString(d))
+	}
 	theInterface.description = d.ReadString()
 	theInterface.deprecatedTag = deserialiseDeprecatedTag(d)
+	theInterface.isGuarded = d.ReadBool()
 	return theInterface
 }
 
@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"context"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+// IsMemberReferenceOf checks whether the member reference at the location,
+// which is indexed by the name of the member only, can be of the class,
+// i.e. its scope is the class, a subclass or a super type of it. The
+// references of which the scope cannot be resolved and the members of
+// traits are assumed to be
+func (q *Query) IsMemberReferenceOf(ctx context.Context, location protocol.Location, fqn string) bool {
+	document := q.store.GetOrCreateDocument(ctx, location.URI)
+	if document == nil {
+		return true
+	}
+	document.Load()
+	resolveCtx := NewResolveContext(q, document)
+	var scopes []string
+	isFound := false
+	TraverseDocument(document, func(s Symbol) {
+		if isFound {
+			return
+		}
+		switch v := s.(type) {
+		case HasTypesHasScope:
+			if v.GetLocation().Range != location.Range {
+				return
+			}
+			isFound = true
+			if scope, ok := v.(interface {
+				ResolveAndGetScope(ResolveContext) TypeComposite
+			}); ok {
+				for _, t := range scope.ResolveAndGetScope(resolveCtx).Resolve() {
+					scopes = append(scopes, t.GetFQN())
+				}
+			}
+		case *Method, *Property, *ClassConst:
+			if s.(SymbolReference).ReferenceLocation().Range != location.Range {
+				return
+			}
+			isFound = true
+			scopes = append(scopes, s.(HasScope).GetScope())
+		}
+	}, nil)
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if len(q.GetTraits(scope)) > 0 || q.IsSubclassOf(scope, fqn) || q.IsSubclassOf(fqn, scope) {
+			return true
+		}
+	}
+	return false
+}
@@ -19,12 +19,14 @@ type Method struct {
 	Name               string
 	Params             []*Parameter
 	returnTypes        TypeComposite
+	throwTypes         TypeComposite
 	description        string
 	Scope              TypeString
 	VisibilityModifier VisibilityModifierValue
 	isStatic           bool
 	ClassModifier      ClassModifierValue
 	deprecatedTag      *tag
+	usesFuncGetArgs    bool
 }
 
 var _ HasScope = (*Method)(nil)
@@ -51,6 +53,8 @@ func newMethodFromPhpDocTag(document *Document, class *Class, methodTag tag, loc
 			Name:        paramTag.Name,
 			Value:       paramTag.Value,
 			Type:        typesFromPhpDoc(document, paramTag.TypeString),
+			hasValue:    paramTag.Value != "",
+			isVariadic:  paramTag.IsVariadic,
 		}
 		method.Params = append(method.Params, param)
 	}
@@ -79,6 +83,9 @@ func (s *Method) analyseMethodNode(a analyser, document *Document, node *phrase.
 				for _, param := range s.Params {
 					lastToken := util.LastToken(p)
 					variableTable.add(a, param.ToVariable(), document.positionAt(lastToken.Offset+lastToken.Length), true)
+					if param.IsReference() {
+						variableTable.setReference(param.Name)
+					}
 				}
 			case phrase.MethodDeclarationBody:
 				scanForChildren(a, document, p)
@@ -88,6 +95,13 @@ func (s *Method) analyseMethodNode(a analyser, document *Document, node *phrase.
 	}
 	document.popVariableTable()
 	document.popBlock()
+	if strings.EqualFold(s.Name, "__construct") {
+		for _, param := range s.Params {
+			if param.IsPromoted() {
+				document.addSymbol(newPromotedProperty(document, param))
+			}
+		}
+	}
 }
 
 func (s *Method) analyseHeader(a analyser, document *Document, methodHeader *phrase.Phrase) {
@@ -103,6 +117,8 @@ func (s *Method) analyseHeader(a analyser, document *Document, methodHeader *phr
 			case phrase.Identifier:
 				s.Name = document.getPhraseText(p)
 				s.refLocation = document.GetNodeLocation(p)
+			case phrase.ReturnType:
+				s.returnTypes = analyseReturnType(document, p)
 			}
 		} else if token, ok := child.(*lexer.Token); ok {
 			switch token.Type {
@@ -145,6 +161,8 @@ func newMethod(a analyser, document *Document, node *phrase.Phrase) Symbol {
 	} else if trait, ok := lastClass.(*Trait); ok {
 		method.Scope = trait.Name
 		method.Scope.SetNamespace(document.currImportTable().GetNamespace())
+	} else if enum, ok := lastClass.(*Enum); ok {
+		method.Scope = enum.Name
 	}
 	return nil
 }
@@ -154,10 +172,11 @@ func (s Method) GetLocation() protocol.Location {
 }
 
 func (s *Method) applyPhpDoc(document *Document, phpDoc phpDocComment) {
-	tags := phpDoc.Returns
-	for _, tag := range tags {
-		s.returnTypes.merge(typesFromPhpDoc(document, tag.TypeString))
+	returnTypes := newTypeComposite()
+	for _, tag := range phpDoc.Returns {
+		returnTypes.merge(typesFromPhpDoc(document, tag.TypeString))
 	}
+	s.returnTypes = refineTypes(s.returnTypes, returnTypes)
 	for index, param := range s.Params {
 		tag := phpDoc.findParamTag(param.Name)
 		if tag != nil {
@@ -165,6 +184,9 @@ func (s *Method) applyPhpDoc(document *Document, phpDoc phpDocComment) {
 			s.Params[index].description = tag.Description
 		}
 	}
+	for _, tag := range phpDoc.Throws {
+		s.throwTypes.merge(typesFromPhpDoc(document, tag.TypeString))
+	}
 	s.description = phpDoc.Description
 	s.deprecatedTag = phpDoc.deprecated()
 }
@@ -181,6 +203,11 @@ func (s Method) GetReturnTypes() TypeComposite {
 	return s.returnTypes
 }
 
+// GetThrowTypes returns the exception types from @throws
+func (s Method) GetThrowTypes() TypeComposite {
+	return s.throwTypes
+}
+
 func (s *Method) GetCollection() string {
 	return methodCollection
 }
@@ -233,6 +260,8 @@ func (s *Method) Serialise(e *storage.Encoder) {
 	e.WriteBool(s.isStatic)
 	e.WriteInt(int(s.ClassModifier))
 	serialiseDeprecatedTag(e, s.deprecatedTag)
+	e.WriteBool(s.usesFuncGetArgs)
+	s.throwTypes.Write(e)
 }
 
 func ReadMethod(d *storage.Decoder) *Method {
@@ -253,6 +282,8 @@ func ReadMethod(d *storage.Decoder) *Method {
 	method.isStatic = d.ReadBool()
 	method.ClassModifier = ClassModifierValue(d.ReadInt())
 	method.deprecatedTag = deserialiseDeprecatedTag(d)
+	method.usesFuncGetArgs = d.ReadBool()
+	method.throwTypes = ReadTypeComposite(d)
 
 	return &method
 }
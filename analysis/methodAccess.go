@@ -54,17 +54,27 @@ func (s *MethodAccess) Resolve(ctx ResolveContext) {
 	}
 	q := ctx.query
 	currentClass := ctx.document.GetClassScopeAtSymbol(s.Scope)
+	notFoundTypes := newTypeComposite()
 	for _, scopeType := range s.ResolveAndGetScope(ctx).Resolve() {
+		found := false
 		for _, class := range q.GetClasses(scopeType.GetFQN()) {
 			for _, m := range q.GetClassMethods(class, s.Name, nil).ReduceAccess(currentClass, s) {
 				s.Type.merge(resolveMemberTypes(m.Method.GetReturnTypes(), s.Scope))
+				found = true
 			}
 		}
 		for _, theInterface := range q.GetInterfaces(scopeType.GetFQN()) {
 			for _, m := range q.GetInterfaceMethods(theInterface, s.Name, nil).ReduceAccess(currentClass, s) {
 				s.Type.merge(resolveMemberTypes(m.Method.GetReturnTypes(), s.Scope))
+				found = true
 			}
 		}
+		if !found {
+			notFoundTypes.add(scopeType)
+		}
+	}
+	for _, m := range q.GetMagicMethods(notFoundTypes, MagicCall) {
+		s.Type.merge(resolveMemberTypes(m.Method.GetReturnTypes(), s.Scope))
 	}
 	s.hasResolved = true
 }
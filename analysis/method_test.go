@@ -151,3 +151,29 @@ class TestClass1 {
 			},
 		}).ToString())
 }
+
+func TestMethodReturnTypesWithPhpDoc(t *testing.T) {
+	document := NewDocument("test1", []byte(`<?php
+class Repository
+{
+    /**
+     * @return Item[]
+     */
+    public function all(): array {}
+
+    /**
+     * @return ArrayObject
+     */
+    public function items(): Countable {}
+}`))
+	document.Load()
+	returnTypes := map[string]string{}
+	tra := newTraverser()
+	tra.traverseDocument(document, func(tra *traverser, s Symbol, _ []Symbol) {
+		if m, ok := s.(*Method); ok {
+			returnTypes[m.Name] = m.GetReturnTypes().ToString()
+		}
+	})
+	assert.Equal(t, "\\Item[]", returnTypes["all"])
+	assert.Equal(t, "\\ArrayObject|\\Countable", returnTypes["items"])
+}
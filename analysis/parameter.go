@@ -1,6 +1,8 @@
 package analysis
 
 import (
+	"strings"
+
 	"github.com/john-nguyen09/go-phpparser/lexer"
 	"github.com/john-nguyen09/go-phpparser/phrase"
 	"github.com/john-nguyen09/phpintel/analysis/storage"
@@ -14,6 +16,13 @@ type Parameter struct {
 	varLocation protocol.Location
 	description string
 	hasValue    bool
+	visibility  VisibilityModifierValue
+	isPromoted  bool
+	isReadonly  bool
+	isVariadic  bool
+	isReference bool
+	// declaredTypes are the types which PHP checks the arguments against
+	declaredTypes TypeComposite
 
 	Name  string        `json:"Name"`
 	Type  TypeComposite `json:"Type"`
@@ -37,6 +46,7 @@ func newParameter(a analyser, document *Document, node *phrase.Phrase) *Paramete
 					param.Type.add(typeString)
 				}
 				document.addSymbol(typeDeclaration)
+				param.declaredTypes = declaredTypes(document, p)
 			case phrase.ConstantAccessExpression:
 				var (
 					constAccess HasTypes
@@ -63,6 +73,25 @@ func newParameter(a analyser, document *Document, node *phrase.Phrase) *Paramete
 			case lexer.VariableName:
 				param.Name = document.getTokenText(token)
 				param.varLocation = document.GetNodeLocation(token)
+			case lexer.Ellipsis:
+				param.isVariadic = true
+			case lexer.Ampersand:
+				param.isReference = true
+			case lexer.Public, lexer.Protected, lexer.Private:
+				param.isPromoted = true
+				param.visibility = visibilityFromToken(token)
+				// readonly without visibility is replaced by public before parsing
+				if token.Type == lexer.Public && !strings.EqualFold(document.getTokenText(token), "public") {
+					param.isReadonly = true
+				}
+			case lexer.Whitespace:
+				text := document.getTokenText(token)
+				if hasEqual {
+					param.Value += text
+				} else if isReadonlyText(text) {
+					param.isPromoted = true
+					param.isReadonly = true
+				}
 			default:
 				if hasEqual {
 					param.hasValue = true
@@ -75,6 +104,16 @@ func newParameter(a analyser, document *Document, node *phrase.Phrase) *Paramete
 	return param
 }
 
+func visibilityFromToken(token *lexer.Token) VisibilityModifierValue {
+	switch token.Type {
+	case lexer.Protected:
+		return Protected
+	case lexer.Private:
+		return Private
+	}
+	return Public
+}
+
 func (s *Parameter) GetDescription() string {
 	return s.description
 }
@@ -84,15 +123,25 @@ func (s *Parameter) GetLocation() protocol.Location {
 }
 
 func (s Parameter) ToVariable() *Variable {
+	types := s.Type
+	if s.isVariadic {
+		// The variadic parameter is the array of the arguments inside the function
+		types = newTypeComposite()
+		types.mergeWithArrayLevel(s.Type, 1)
+		if types.IsEmpty() {
+			types.add(NewTypeString("array"))
+		}
+	}
 	return &Variable{
 		Expression: Expression{
 			Location: s.varLocation,
-			Type:     s.Type,
+			Type:     types,
 			Name:     s.Name,
 			Scope:    nil,
 		},
 		description:        s.description,
 		canReferenceGlobal: false,
+		isParameter:        true,
 	}
 }
 
@@ -100,6 +149,27 @@ func (s Parameter) HasValue() bool {
 	return s.hasValue
 }
 
+// IsVariadic returns whether the parameter takes the rest of the arguments,
+// the stubs also use $_ for the parameters which can be repeated
+func (s Parameter) IsVariadic() bool {
+	return s.isVariadic || s.Name == "$_"
+}
+
+// IsOptional returns whether the argument of the parameter can be omitted
+func (s Parameter) IsOptional() bool {
+	return s.hasValue || s.IsVariadic()
+}
+
+// IsReference returns whether the argument is passed by reference
+func (s Parameter) IsReference() bool {
+	return s.isReference
+}
+
+// IsPromoted returns whether the constructor parameter declares a property
+func (s Parameter) IsPromoted() bool {
+	return s.isPromoted
+}
+
 func (s *Parameter) Write(e *storage.Encoder) {
 	e.WriteLocation(s.location)
 	e.WriteLocation(s.varLocation)
@@ -107,15 +177,21 @@ func (s *Parameter) Write(e *storage.Encoder) {
 	e.WriteString(s.Name)
 	s.Type.Write(e)
 	e.WriteString(s.Value)
+	e.WriteBool(s.isVariadic)
+	e.WriteBool(s.isReference)
+	s.declaredTypes.Write(e)
 }
 
 func ReadParameter(d *storage.Decoder) *Parameter {
 	return &Parameter{
-		location:    d.ReadLocation(),
-		varLocation: d.ReadLocation(),
-		hasValue:    d.ReadBool(),
-		Name:        d.ReadString(),
-		Type:        ReadTypeComposite(d),
-		Value:       d.ReadString(),
+		location:      d.ReadLocation(),
+		varLocation:   d.ReadLocation(),
+		hasValue:      d.ReadBool(),
+		Name:          d.ReadString(),
+		Type:          ReadTypeComposite(d),
+		Value:         d.ReadString(),
+		isVariadic:    d.ReadBool(),
+		isReference:   d.ReadBool(),
+		declaredTypes: ReadTypeComposite(d),
 	}
 }
@@ -16,6 +16,7 @@ type methodTagParam struct {
 	TypeString string
 	Name       string
 	Value      string
+	IsVariadic bool
 }
 
 type tag struct {
@@ -27,10 +28,17 @@ type tag struct {
 	IsStatic    bool
 
 	nameLocation protocol.Location
+	isPrefixed   bool
 }
 
 var /* const */ stripPattern = regexp.MustCompile(`(?m)^\/\*\*[ \t]*|\s*\*\/$|^[ \t]*\*[ \t]*`)
 
+// toolTagPrefixes are the prefixes of static analysers' tags, e.g. @psalm-param,
+// these tags usually have more precise types than the unprefixed ones
+var /* const */ toolTagPrefixes = []string{"@psalm-", "@phpstan-"}
+
+var /* const */ docIdentifierPattern = regexp.MustCompile(`[\\\w$-]+`)
+
 func processTypeNode(document *Document, node *phrase.Phrase) string {
 	text := document.GetNodeText(node)
 	if text == "" {
@@ -69,6 +77,12 @@ func paramOrPropTypeTag(tagName string, document *Document, p *phrase.Phrase) ta
 		if p, ok := child.(*phrase.Phrase); ok {
 			switch p.Type {
 			case phrase.DocumentCommentDescription:
+				if len(ts) > 0 && isCallableTypeText(ts[len(ts)-1]) {
+					signature, rest := readCallableSignature(document, p)
+					ts[len(ts)-1] += signature
+					name, nameLocation, description = readNameAndDescription(document, rest)
+					break
+				}
 				description = readDescriptionNode(document, p)
 			case phrase.TypeDeclaration:
 				t := processTypeNode(document, p)
@@ -102,6 +116,12 @@ func varTag(tagName string, document *Document, node *phrase.Phrase) tag {
 		if p, ok := child.(*phrase.Phrase); ok {
 			switch p.Type {
 			case phrase.DocumentCommentDescription:
+				if len(ts) > 0 && isCallableTypeText(ts[len(ts)-1]) {
+					signature, rest := readCallableSignature(document, p)
+					ts[len(ts)-1] += signature
+					name, _, description = readNameAndDescription(document, rest)
+					break
+				}
 				description = readDescriptionNode(document, p)
 			case phrase.TypeDeclaration:
 				t := processTypeNode(document, p)
@@ -132,6 +152,12 @@ func returnTag(tagName string, document *Document, node *phrase.Phrase) tag {
 		if p, ok := child.(*phrase.Phrase); ok {
 			switch p.Type {
 			case phrase.DocumentCommentDescription:
+				if len(ts) > 0 && isCallableTypeText(ts[len(ts)-1]) {
+					signature, rest := readCallableSignature(document, p)
+					ts[len(ts)-1] += signature
+					description = readNodesDescription(document, rest)
+					break
+				}
 				description = readDescriptionNode(document, p)
 			case phrase.TypeDeclaration:
 				t := processTypeNode(document, p)
@@ -246,6 +272,34 @@ func methodParam(document *Document, node *phrase.Phrase) methodTagParam {
 	}
 }
 
+// mixinTag reads the class name of @mixin, the parser does not have a
+// dedicated phrase for this tag so the class name is the first word
+// of the description
+func mixinTag(tagName string, document *Document, node *phrase.Phrase) tag {
+	typeString := ""
+	description := ""
+	traverser := util.NewTraverser(node)
+	for child := traverser.Advance(); child != nil; child = traverser.Advance() {
+		if p, ok := child.(*phrase.Phrase); ok && p.Type == phrase.DocumentCommentDescription {
+			i := skipDocWhitespaces(p.Children, 0)
+			j := i
+			for ; j < len(p.Children); j++ {
+				t, ok := p.Children[j].(*lexer.Token)
+				if !ok || (t.Type != lexer.Name && t.Type != lexer.Backslash) {
+					break
+				}
+				typeString += document.getTokenText(t)
+			}
+			description = readNodesDescription(document, p.Children[j:])
+		}
+	}
+	return tag{
+		TagName:     tagName,
+		TypeString:  typeString,
+		Description: description,
+	}
+}
+
 func globalTag(tagName string, document *Document, node *phrase.Phrase) tag {
 	ts := []string{}
 	name := ""
@@ -307,6 +361,10 @@ type phpDocComment struct {
 	Vars        []tag
 	Globals     []tag
 	Deprecates  []tag
+	Throws      []tag
+	Mixins      []tag
+	Templates   []tag
+	Extends     []tag
 	location    protocol.Location
 
 	PropertyReads  []tag
@@ -318,11 +376,110 @@ func readDescriptionNode(document *Document, node *phrase.Phrase) string {
 	return strings.TrimSpace(stripPattern.ReplaceAllString(desc, ""))
 }
 
+func readNodesDescription(document *Document, nodes []phrase.AstNode) string {
+	if len(nodes) == 0 {
+		return ""
+	}
+	first, last := util.FirstToken(nodes[0]), util.LastToken(nodes[len(nodes)-1])
+	if first == nil || last == nil {
+		return ""
+	}
+	desc := string(document.GetText()[first.Offset : last.Offset+last.Length])
+	return strings.TrimSpace(stripPattern.ReplaceAllString(desc, ""))
+}
+
+func isCallableTypeText(text string) bool {
+	text = strings.ToLower(strings.TrimLeft(text, "\\"))
+	return text == "callable" || text == "closure"
+}
+
+// readCallableSignature reads the (T): R part of callable or Closure types,
+// the parser does not understand the signature so it ends up being
+// a part of the description. This returns the signature and the
+// rest of the description nodes.
+func readCallableSignature(document *Document, node *phrase.Phrase) (string, []phrase.AstNode) {
+	children := node.Children
+	if len(children) == 0 {
+		return "", children
+	}
+	if t, ok := children[0].(*lexer.Token); !ok || t.Type != lexer.OpenParenthesis {
+		return "", children
+	}
+	depth := 0
+	end := 0
+	i := 0
+	for ; i < len(children); i++ {
+		t, ok := children[i].(*lexer.Token)
+		if !ok {
+			continue
+		}
+		if t.Type == lexer.OpenParenthesis {
+			depth++
+		} else if t.Type == lexer.CloseParenthesis {
+			depth--
+		}
+		if depth == 0 {
+			end = t.Offset + t.Length
+			i++
+			break
+		}
+	}
+	if depth != 0 {
+		return "", children
+	}
+	j := skipDocWhitespaces(children, i)
+	if t, ok := childToken(children, j); ok && document.getTokenText(t) == ":" {
+		j = skipDocWhitespaces(children, j+1)
+		for ; j < len(children); j++ {
+			t, ok := children[j].(*lexer.Token)
+			if !ok {
+				continue
+			}
+			if t.Type == lexer.OpenParenthesis {
+				depth++
+			} else if t.Type == lexer.CloseParenthesis {
+				depth--
+			} else if depth == 0 && (t.Type == lexer.Whitespace || t.Type == lexer.DocumentCommentEndline) {
+				break
+			}
+			end = t.Offset + t.Length
+			i = j + 1
+		}
+	}
+	start := util.FirstToken(node).Offset
+	return string(document.GetText()[start:end]), children[i:]
+}
+
+func readNameAndDescription(document *Document, nodes []phrase.AstNode) (string, protocol.Location, string) {
+	i := skipDocWhitespaces(nodes, 0)
+	if t, ok := childToken(nodes, i); ok && t.Type == lexer.VariableName {
+		return document.getTokenText(t), document.GetNodeLocation(t), readNodesDescription(document, nodes[i+1:])
+	}
+	return "", protocol.Location{}, readNodesDescription(document, nodes)
+}
+
+func skipDocWhitespaces(nodes []phrase.AstNode, i int) int {
+	for ; i < len(nodes); i++ {
+		if t, ok := nodes[i].(*lexer.Token); !ok || t.Type != lexer.Whitespace {
+			break
+		}
+	}
+	return i
+}
+
+func childToken(nodes []phrase.AstNode, i int) (*lexer.Token, bool) {
+	if i >= len(nodes) {
+		return nil, false
+	}
+	t, ok := nodes[i].(*lexer.Token)
+	return t, ok
+}
+
 func getTagName(document *Document, p *phrase.Phrase) string {
 	traverser := util.NewTraverser(p)
 	for child := traverser.Advance(); child != nil; child = traverser.Advance() {
-		if t, ok := child.(*lexer.Token); ok &&
-			t.Type > lexer.DocumentCommentTagNameAnchorStart && t.Type < lexer.DocumentCommentTagNameAnchorEnd {
+		if t, ok := child.(*lexer.Token); ok && (t.Type == lexer.DocumentCommentTagName ||
+			t.Type > lexer.DocumentCommentTagNameAnchorStart && t.Type < lexer.DocumentCommentTagNameAnchorEnd) {
 			return document.getTokenText(t)
 		}
 	}
@@ -331,6 +488,9 @@ func getTagName(document *Document, p *phrase.Phrase) string {
 
 func parseTag(document *Document, p *phrase.Phrase) (tag, error) {
 	tagName := getTagName(document, p)
+	if unprefixed, ok := unprefixTagName(tagName); ok {
+		return parsePrefixedTag(unprefixed, document, p)
+	}
 	switch tagName {
 	case "@param", "@property", "@property-read", "@property-write":
 		paramOrProp := paramOrPropTypeTag(tagName, document, p)
@@ -340,7 +500,7 @@ func parseTag(document *Document, p *phrase.Phrase) (tag, error) {
 		return paramOrProp, nil
 	case "@var":
 		return varTag(tagName, document, p), nil
-	case "@return":
+	case "@return", "@throws":
 		return returnTag(tagName, document, p), nil
 	case "@method":
 		return methodTag(tagName, document, p), nil
@@ -348,10 +508,227 @@ func parseTag(document *Document, p *phrase.Phrase) (tag, error) {
 		return globalTag(tagName, document, p), nil
 	case "@deprecated":
 		return deprecatedTag(tagName, document, p), nil
+	case "@mixin":
+		mixin := mixinTag(tagName, document, p)
+		if mixin.TypeString == "" {
+			return tag{}, fmt.Errorf("@mixin tags with no class name")
+		}
+		return mixin, nil
+	case "@template", "@template-covariant", "@template-contravariant":
+		return templateTag("@template", document, p), nil
+	case "@extends":
+		return extendsTag(tagName, document, p), nil
 	}
 	return tag{}, fmt.Errorf("unexpected tag: %s", tagName)
 }
 
+func unprefixTagName(tagName string) (string, bool) {
+	for _, prefix := range toolTagPrefixes {
+		if strings.HasPrefix(tagName, prefix) {
+			return "@" + tagName[len(prefix):], true
+		}
+	}
+	return tagName, false
+}
+
+// parsePrefixedTag parses the tags with a static analyser prefix, the parser
+// does not recognise these tags so the whole tag is in the description
+func parsePrefixedTag(tagName string, document *Document, p *phrase.Phrase) (tag, error) {
+	var nodes []phrase.AstNode
+	traverser := util.NewTraverser(p)
+	for child := traverser.Advance(); child != nil; child = traverser.Advance() {
+		if p, ok := child.(*phrase.Phrase); ok && p.Type == phrase.DocumentCommentDescription {
+			nodes = p.Children
+		}
+	}
+	var (
+		result tag
+		err    error
+	)
+	switch tagName {
+	case "@param", "@property", "@property-read", "@property-write", "@var":
+		typeString, rest := readDocType(document, nodes)
+		name, nameLocation, description := readNameAndDescription(document, rest)
+		result = tag{
+			TagName:     tagName,
+			Name:        name,
+			Description: description,
+			TypeString:  typeString,
+
+			nameLocation: nameLocation,
+		}
+		if tagName != "@param" && tagName != "@var" && name == "" {
+			err = fmt.Errorf("%s tags with no name", tagName)
+		}
+	case "@return":
+		typeString, rest := readDocType(document, nodes)
+		result = tag{
+			TagName:     tagName,
+			Description: readNodesDescription(document, rest),
+			TypeString:  typeString,
+		}
+	case "@method":
+		result, err = prefixedMethodTag(tagName, document, nodes)
+	case "@template", "@template-covariant", "@template-contravariant":
+		result = templateTag("@template", document, p)
+	case "@extends":
+		result = extendsTag(tagName, document, p)
+	default:
+		return tag{}, fmt.Errorf("unexpected tag: %s", tagName)
+	}
+	result.isPrefixed = true
+	return result, err
+}
+
+// readDocType reads a type which can contain generics, array shapes and
+// callable signatures from the description nodes and returns the rest
+func readDocType(document *Document, nodes []phrase.AstNode) (string, []phrase.AstNode) {
+	var sb strings.Builder
+	depth := 0
+	i := skipDocWhitespaces(nodes, 0)
+	for ; i < len(nodes); i++ {
+		t, ok := nodes[i].(*lexer.Token)
+		if !ok {
+			break
+		}
+		text := document.getTokenText(t)
+		if depth == 0 {
+			if t.Type == lexer.VariableName || t.Type == lexer.DocumentCommentEndline {
+				break
+			}
+			if t.Type == lexer.Whitespace {
+				// The type continues after whitespaces in int | string or callable(): void
+				next, ok := childToken(nodes, skipDocWhitespaces(nodes, i))
+				typeText := strings.TrimSpace(sb.String())
+				if typeText != "" && (strings.HasSuffix(typeText, "|") || strings.HasSuffix(typeText, ":") ||
+					ok && strings.HasPrefix(document.getTokenText(next), "|")) {
+					continue
+				}
+				break
+			}
+		}
+		for _, r := range text {
+			switch r {
+			case '(', '<', '{', '[':
+				depth++
+			case ')', '>', '}', ']':
+				depth--
+			}
+		}
+		sb.WriteString(text)
+	}
+	return sb.String(), nodes[i:]
+}
+
+// prefixedMethodTag reads [static] [ReturnType] name(params) description
+func prefixedMethodTag(tagName string, document *Document, nodes []phrase.AstNode) (tag, error) {
+	result := tag{
+		TagName: tagName,
+	}
+	i := skipDocWhitespaces(nodes, 0)
+	if t, ok := childToken(nodes, i); ok && t.Type == lexer.Static {
+		result.IsStatic = true
+		i = skipDocWhitespaces(nodes, i+1)
+	}
+	typeString, rest := readDocType(document, nodes[i:])
+	j := skipDocWhitespaces(rest, 0)
+	if t, ok := childToken(rest, j); !ok || t.Type != lexer.Name || !isOpenParenthesis(document, rest, j+1) {
+		// There is no return type so the type is actually the name
+		typeString, rest = "", nodes[i:]
+		j = skipDocWhitespaces(rest, 0)
+	}
+	t, ok := childToken(rest, j)
+	if !ok || t.Type != lexer.Name || !isOpenParenthesis(document, rest, j+1) {
+		return tag{}, fmt.Errorf("%s tags with no name", tagName)
+	}
+	result.Name = document.getTokenText(t)
+	result.nameLocation = document.GetNodeLocation(t)
+	if typeString == "" {
+		typeString = "void"
+	}
+	result.TypeString = typeString
+	paramsText, rest := readDocType(document, rest[j+1:])
+	paramsText = strings.TrimSuffix(strings.TrimPrefix(paramsText, "("), ")")
+	for _, paramText := range splitOutsideBrackets(paramsText, ',') {
+		if param, ok := methodParamFromText(paramText); ok {
+			result.Parameters = append(result.Parameters, param)
+		}
+	}
+	result.Description = readNodesDescription(document, rest)
+	return result, nil
+}
+
+func isOpenParenthesis(document *Document, nodes []phrase.AstNode, i int) bool {
+	t, ok := childToken(nodes, i)
+	return ok && strings.HasPrefix(document.getTokenText(t), "(")
+}
+
+// methodParamFromText parses Type $name = value
+func methodParamFromText(text string) (methodTagParam, bool) {
+	param := methodTagParam{}
+	if index := strings.IndexByte(text, '='); index >= 0 {
+		text, param.Value = text[:index], strings.TrimSpace(text[index+1:])
+	}
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return param, false
+	}
+	name := strings.TrimLeft(fields[len(fields)-1], "&.")
+	param.IsVariadic = strings.Contains(fields[len(fields)-1], "...")
+	if !strings.HasPrefix(name, "$") {
+		param.TypeString = strings.Join(fields, " ")
+		return param, true
+	}
+	param.Name = name
+	param.TypeString = strings.Join(fields[:len(fields)-1], " ")
+	return param, true
+}
+
+// templateTag reads T [of|as Bound], the bound is the type string
+func templateTag(tagName string, document *Document, node *phrase.Phrase) tag {
+	result := tag{
+		TagName: tagName,
+	}
+	traverser := util.NewTraverser(node)
+	for child := traverser.Advance(); child != nil; child = traverser.Advance() {
+		p, ok := child.(*phrase.Phrase)
+		if !ok || p.Type != phrase.DocumentCommentDescription {
+			continue
+		}
+		i := skipDocWhitespaces(p.Children, 0)
+		t, ok := childToken(p.Children, i)
+		if !ok || t.Type != lexer.Name {
+			break
+		}
+		result.Name = document.getTokenText(t)
+		result.nameLocation = document.GetNodeLocation(t)
+		rest := p.Children[i+1:]
+		i = skipDocWhitespaces(rest, 0)
+		if t, ok := childToken(rest, i); ok {
+			if keyword := document.getTokenText(t); keyword == "of" || keyword == "as" {
+				result.TypeString, rest = readDocType(document, rest[i+1:])
+			}
+		}
+		result.Description = readNodesDescription(document, rest)
+	}
+	return result
+}
+
+func extendsTag(tagName string, document *Document, node *phrase.Phrase) tag {
+	result := tag{
+		TagName: tagName,
+	}
+	traverser := util.NewTraverser(node)
+	for child := traverser.Advance(); child != nil; child = traverser.Advance() {
+		if p, ok := child.(*phrase.Phrase); ok && p.Type == phrase.DocumentCommentDescription {
+			var rest []phrase.AstNode
+			result.TypeString, rest = readDocType(document, p.Children)
+			result.Description = readNodesDescription(document, rest)
+		}
+	}
+	return result
+}
+
 func (d phpDocComment) findTagsByTagName(tagName string) []tag {
 	tags := []tag{}
 
@@ -381,6 +758,10 @@ func (d phpDocComment) deprecated() *tag {
 }
 
 func (d *phpDocComment) prefillTags() {
+	d.tags = d.withoutOverriddenTags()
+	d.Templates = d.findTagsByTagName("@template")
+	d.normalisePrefixedTags()
+	d.Extends = d.findTagsByTagName("@extends")
 	d.Returns = d.findTagsByTagName("@return")
 	d.Properties = d.findTagsByTagName("@property")
 	d.PropertyReads = d.findTagsByTagName("@property-read")
@@ -389,6 +770,87 @@ func (d *phpDocComment) prefillTags() {
 	d.Vars = d.findTagsByTagName("@var")
 	d.Globals = d.findTagsByTagName("@global")
 	d.Deprecates = d.findTagsByTagName("@deprecated")
+	d.Throws = d.findTagsByTagName("@throws")
+	d.Mixins = d.findTagsByTagName("@mixin")
+}
+
+// withoutOverriddenTags removes the unprefixed tags that have
+// a prefixed counterpart, e.g. @param when there is @psalm-param
+func (d *phpDocComment) withoutOverriddenTags() []tag {
+	overrides := []tag{}
+	for _, t := range d.tags {
+		if t.isPrefixed {
+			overrides = append(overrides, t)
+		}
+	}
+	if len(overrides) == 0 {
+		return d.tags
+	}
+	tags := []tag{}
+	for _, t := range d.tags {
+		if !t.isPrefixed && isOverridden(t, overrides) {
+			continue
+		}
+		tags = append(tags, t)
+	}
+	return tags
+}
+
+func isOverridden(t tag, overrides []tag) bool {
+	for _, override := range overrides {
+		if override.TagName != t.TagName {
+			continue
+		}
+		if override.Name == t.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalisePrefixedTags replaces the template names in the types of the
+// prefixed tags with their bounds and removes their generics because
+// generics are not supported
+func (d *phpDocComment) normalisePrefixedTags() {
+	bounds := map[string]string{}
+	for _, template := range d.Templates {
+		bound := template.TypeString
+		if bound == "" {
+			bound = "mixed"
+		}
+		bounds[template.Name] = bound
+	}
+	normalise := func(text string) string {
+		if len(bounds) > 0 {
+			text = docIdentifierPattern.ReplaceAllStringFunc(text, func(name string) string {
+				if bound, ok := bounds[name]; ok {
+					return bound
+				}
+				return name
+			})
+		}
+		return withoutGenericsInUnion(text)
+	}
+	for i, t := range d.tags {
+		if t.TagName == "@template" || !t.isPrefixed {
+			continue
+		}
+		d.tags[i].TypeString = normalise(t.TypeString)
+		for j, param := range t.Parameters {
+			d.tags[i].Parameters[j].TypeString = normalise(param.TypeString)
+		}
+	}
+}
+
+// mixinTypes returns the class names of the @mixin tags
+func (d *phpDocComment) mixinTypes(document *Document) []TypeString {
+	var results []TypeString
+	for _, mixin := range d.Mixins {
+		typeString := NewTypeString(mixin.TypeString)
+		typeString.SetFQN(document.currImportTable().GetClassReferenceFQN(typeString))
+		results = append(results, typeString)
+	}
+	return results
 }
 
 func (d *phpDocComment) GetLocation() protocol.Location {
@@ -62,3 +62,86 @@ func TestPhpDocCommentSymbols(t *testing.T) {
 	doc.Load()
 	cupaloy.SnapshotT(t, doc.hasTypesSymbols())
 }
+
+func TestPrefixedPhpDocTags(t *testing.T) {
+	phpDocStr := `<?php /**
+	* @template T of \Countable
+	* @template U
+	* @param array $items
+	* @psalm-param list<\DateTime> $items The items
+	* @param int $count
+	* @phpstan-param T $value
+	* @param mixed $other
+	* @return array
+	* @psalm-return array<string, int|null>
+	* @phpstan-var array{id: int, name: string} $shape
+	* @psalm-property-read non-empty-list<Foo> $foos
+	* @phpstan-method static Builder<U> where(string $column, mixed ...$values) Adds a where
+	* @psalm-method create(array<int, string> $attributes = [])
+	* @phpstan-extends Collection<int, T>
+	*/`
+	doc := NewDocument("test", []byte(phpDocStr))
+	phpDoc := newPhpDocFromNode(newAnalyser(), doc, doc.GetRootNode().Children[1].(*phrase.Phrase)).(*phpDocComment)
+
+	items := phpDoc.findParamTag("$items")
+	assert.NotNil(t, items)
+	assert.Equal(t, "\\DateTime[]", items.TypeString)
+	assert.Equal(t, "The items", items.Description)
+	assert.Equal(t, "\\DateTime[]", typesFromPhpDoc(doc, items.TypeString).ToString())
+	assert.Equal(t, "\\Countable", phpDoc.findParamTag("$value").TypeString)
+	assert.Equal(t, "mixed", phpDoc.findParamTag("$other").TypeString)
+	assert.Equal(t, "int", phpDoc.findParamTag("$count").TypeString)
+
+	assert.Equal(t, 1, len(phpDoc.Returns))
+	assert.Equal(t, "array", phpDoc.Returns[0].TypeString)
+	assert.Equal(t, "array", typesFromPhpDoc(doc, phpDoc.Returns[0].TypeString).ToString())
+
+	assert.Equal(t, 1, len(phpDoc.Vars))
+	assert.Equal(t, "$shape", phpDoc.Vars[0].Name)
+	assert.Equal(t, "array", typesFromPhpDoc(doc, phpDoc.Vars[0].TypeString).ToString())
+
+	assert.Equal(t, 1, len(phpDoc.PropertyReads))
+	assert.Equal(t, "$foos", phpDoc.PropertyReads[0].Name)
+	assert.Equal(t, "\\Foo[]", typesFromPhpDoc(doc, phpDoc.PropertyReads[0].TypeString).ToString())
+
+	assert.Equal(t, 2, len(phpDoc.Methods))
+	where := phpDoc.Methods[0]
+	assert.Equal(t, "where", where.Name)
+	assert.True(t, where.IsStatic)
+	assert.Equal(t, "Builder", where.TypeString)
+	assert.Equal(t, "Adds a where", where.Description)
+	assert.Equal(t, []methodTagParam{
+		{TypeString: "string", Name: "$column"},
+		{TypeString: "mixed", Name: "$values", IsVariadic: true},
+	}, where.Parameters)
+	create := phpDoc.Methods[1]
+	assert.Equal(t, "create", create.Name)
+	assert.False(t, create.IsStatic)
+	assert.Equal(t, "void", create.TypeString)
+	assert.Equal(t, []methodTagParam{
+		{TypeString: "string[]", Name: "$attributes", Value: "[]"},
+	}, create.Parameters)
+
+	assert.Equal(t, 2, len(phpDoc.Templates))
+	assert.Equal(t, "T", phpDoc.Templates[0].Name)
+	assert.Equal(t, "\\Countable", phpDoc.Templates[0].TypeString)
+	assert.Equal(t, 1, len(phpDoc.Extends))
+	assert.Equal(t, "Collection", phpDoc.Extends[0].TypeString)
+}
+
+func TestUnprefixedPhpDocTags(t *testing.T) {
+	phpDocStr := `<?php /**
+	* @template T of \Countable
+	* @param T $value
+	* @psalm-param int
+	* @return T
+	*/`
+	doc := NewDocument("test", []byte(phpDocStr))
+	phpDoc := newPhpDocFromNode(newAnalyser(), doc, doc.GetRootNode().Children[1].(*phrase.Phrase)).(*phpDocComment)
+
+	value := phpDoc.findParamTag("$value")
+	assert.NotNil(t, value)
+	assert.Equal(t, "T", value.TypeString)
+	assert.Equal(t, 1, len(phpDoc.Returns))
+	assert.Equal(t, "T", phpDoc.Returns[0].TypeString)
+}
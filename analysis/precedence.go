@@ -0,0 +1,202 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/john-nguyen09/go-phpparser/lexer"
+	"github.com/john-nguyen09/go-phpparser/phrase"
+	"github.com/john-nguyen09/phpintel/stub"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// Precedences of the declarations with the same name, the lower is preferred
+const (
+	openDocumentPrecedence = iota
+	workspacePrecedence
+	vendorPrecedence
+	stubPrecedence
+)
+
+// guardedPrecedence is added to the declarations inside if (!class_exists(...))
+// because they are only declared when there is no other declaration, it is
+// greater than the other precedences so any unguarded declaration is preferred
+const guardedPrecedence = stubPrecedence + 1
+
+// IsVendorURI checks whether the URI belongs to the dependencies installed by Composer
+func IsVendorURI(uri string) bool {
+	return strings.Contains(uri, "/vendor/")
+}
+
+// isVendorURI checks whether the URI belongs to the vendor folder of the
+// workspace, the root is trimmed as the workspace itself can be inside a
+// folder named vendor
+func (s *Store) isVendorURI(uri string) bool {
+	return IsVendorURI(strings.TrimPrefix(uri, s.uri))
+}
+
+// isExistenceGuarded checks whether the declaration is inside an if statement
+// of which condition checks the existence, e.g. !function_exists('foo')
+func isExistenceGuarded(a analyser, document *Document) bool {
+	for _, parent := range a.nodes.Parents() {
+		if parent.Type != phrase.IfStatement {
+			continue
+		}
+		isCondition := false
+		for _, child := range parent.Children {
+			if t, ok := child.(*lexer.Token); ok {
+				if t.Type == lexer.OpenParenthesis {
+					isCondition = true
+				} else if t.Type == lexer.CloseParenthesis {
+					break
+				}
+				continue
+			}
+			if p, ok := child.(*phrase.Phrase); ok && isCondition && hasExistenceCheck(document, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasExistenceCheck(document *Document, condition *phrase.Phrase) bool {
+	found := false
+	util.NewTraverser(condition).Traverse(func(node phrase.AstNode, _ []*phrase.Phrase) util.VisitorContext {
+		p, ok := node.(*phrase.Phrase)
+		if !ok || found {
+			return util.VisitorContext{ShouldAscend: false}
+		}
+		if p.Type == phrase.FunctionCallExpression {
+			if name := firstPhrase(p); name != nil {
+				switch strings.ToLower(strings.TrimPrefix(document.GetNodeText(name), "\\")) {
+				case "class_exists", "interface_exists", "trait_exists", "enum_exists", "function_exists", "defined":
+					found = true
+					return util.VisitorContext{ShouldAscend: false}
+				}
+			}
+		}
+		return util.VisitorContext{ShouldAscend: true}
+	})
+	return found
+}
+
+// isDocumentOpen checks whether the document is open in the editor
+func (s *Store) isDocumentOpen(uri string) bool {
+	if value, ok := s.documents.Get(uri); ok {
+		return value.(*Document).IsOpen()
+	}
+	return false
+}
+
+// declarationPrecedence returns the precedence of the declaration, open
+// documents are preferred to the workspace, vendor and then stubs
+func (q *Query) declarationPrecedence(uri string, isGuarded bool) int {
+	precedence := workspacePrecedence
+	switch {
+	case stub.IsStub(uri):
+		precedence = stubPrecedence
+	case q.store.isDocumentOpen(uri):
+		precedence = openDocumentPrecedence
+	case q.store.isVendorURI(uri):
+		precedence = vendorPrecedence
+	}
+	if isGuarded {
+		precedence += guardedPrecedence
+	}
+	return precedence
+}
+
+// preferred returns the indexes of the declarations which have the highest precedence
+func (q *Query) preferred(count int, declaration func(i int) (string, bool)) []int {
+	indexes := []int{}
+	best := -1
+	for i := 0; i < count; i++ {
+		precedence := q.declarationPrecedence(declaration(i))
+		if best == -1 || precedence < best {
+			best = precedence
+			indexes = indexes[:0]
+		}
+		if precedence == best {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (q *Query) preferredClasses(classes []*Class) []*Class {
+	if len(classes) <= 1 {
+		return classes
+	}
+	results := []*Class{}
+	for _, i := range q.preferred(len(classes), func(i int) (string, bool) {
+		return classes[i].Location.URI, classes[i].isGuarded
+	}) {
+		results = append(results, classes[i])
+	}
+	return results
+}
+
+func (q *Query) preferredInterfaces(interfaces []*Interface) []*Interface {
+	if len(interfaces) <= 1 {
+		return interfaces
+	}
+	results := []*Interface{}
+	for _, i := range q.preferred(len(interfaces), func(i int) (string, bool) {
+		return interfaces[i].location.URI, interfaces[i].isGuarded
+	}) {
+		results = append(results, interfaces[i])
+	}
+	return results
+}
+
+func (q *Query) preferredTraits(traits []*Trait) []*Trait {
+	if len(traits) <= 1 {
+		return traits
+	}
+	results := []*Trait{}
+	for _, i := range q.preferred(len(traits), func(i int) (string, bool) {
+		return traits[i].location.URI, traits[i].isGuarded
+	}) {
+		results = append(results, traits[i])
+	}
+	return results
+}
+
+func (q *Query) preferredFunctions(functions []*Function) []*Function {
+	if len(functions) <= 1 {
+		return functions
+	}
+	results := []*Function{}
+	for _, i := range q.preferred(len(functions), func(i int) (string, bool) {
+		return functions[i].location.URI, functions[i].isGuarded
+	}) {
+		results = append(results, functions[i])
+	}
+	return results
+}
+
+func (q *Query) preferredConsts(consts []*Const) []*Const {
+	if len(consts) <= 1 {
+		return consts
+	}
+	results := []*Const{}
+	for _, i := range q.preferred(len(consts), func(i int) (string, bool) {
+		return consts[i].location.URI, false
+	}) {
+		results = append(results, consts[i])
+	}
+	return results
+}
+
+func (q *Query) preferredDefines(defines []*Define) []*Define {
+	if len(defines) <= 1 {
+		return defines
+	}
+	results := []*Define{}
+	for _, i := range q.preferred(len(defines), func(i int) (string, bool) {
+		return defines[i].location.URI, defines[i].isGuarded
+	}) {
+		results = append(results, defines[i])
+	}
+	return results
+}
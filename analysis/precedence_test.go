@@ -0,0 +1,56 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeclarationPrecedence(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		indexDocument(store, "../cases/duplicate/polyfill.php", "file:///project/src/polyfill.php")
+		indexDocument(store, "../cases/duplicate/vendor.php", "file:///project/vendor/acme/collection/vendor.php")
+
+		q := NewQuery(store)
+		functions := q.GetFunctions("\\helper")
+		assert.Len(t, functions, 1)
+		assert.Equal(t, "file:///project/vendor/acme/collection/vendor.php", functions[0].GetLocation().URI)
+		defines := q.GetDefines("\\APP_DEBUG")
+		assert.Len(t, defines, 1)
+		assert.Equal(t, "file:///project/src/polyfill.php", defines[0].GetLocation().URI)
+
+		indexDocument(store, "../cases/duplicate/copy.php", "file:///project/src/copy.php")
+		store.SaveDocOnStore(openDocument(store, "../cases/duplicate/workspace.php", "file:///project/src/workspace.php"))
+
+		q = NewQuery(store)
+		classes := q.GetClasses("\\Collection")
+		assert.Len(t, classes, 1)
+		assert.Equal(t, "file:///project/src/workspace.php", classes[0].GetLocation().URI)
+		functions = q.GetFunctions("\\helper")
+		assert.Len(t, functions, 1)
+		assert.Equal(t, "file:///project/src/workspace.php", functions[0].GetLocation().URI)
+		defines = q.GetDefines("\\APP_DEBUG")
+		assert.Len(t, defines, 1)
+		assert.Equal(t, "file:///project/src/workspace.php", defines[0].GetLocation().URI)
+	})
+}
+
+func TestInheritedMembersPrecedence(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		indexDocument(store, "../cases/duplicate/contractCopy.php", "file:///project/lib/contractCopy.php")
+		store.SaveDocOnStore(openDocument(store, "../cases/duplicate/contract.php", "file:///project/src/contract.php"))
+
+		q := NewQuery(store)
+		classes := q.GetClasses("\\Report")
+		assert.Len(t, classes, 1)
+		assert.Empty(t, q.GetClassMethods(classes[0], "legacy", nil).Methods)
+		methods := q.GetClassMethods(classes[0], "serialise", nil).Methods
+		assert.NotEmpty(t, methods)
+		for _, method := range methods {
+			assert.Equal(t, "file:///project/src/contract.php", method.Scope.GetLocation().URI)
+		}
+		interfaces := q.GetInterfaces("\\Renderable")
+		assert.Len(t, interfaces, 1)
+		assert.Empty(t, q.GetInterfaceMethods(interfaces[0], "legacy", nil).Methods)
+	})
+}
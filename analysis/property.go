@@ -18,6 +18,7 @@ type Property struct {
 	Scope              TypeString
 	VisibilityModifier VisibilityModifierValue
 	isStatic           bool
+	isReadonly         bool
 	Types              TypeComposite
 }
 
@@ -44,10 +45,34 @@ func newPropertyFromPhpDocTag(document *Document, parent *Class, docTag tag, loc
 	return property
 }
 
+// newPromotedProperty creates the property declared by a promoted constructor parameter,
+// e.g. __construct(private readonly UserRepo $repo)
+func newPromotedProperty(document *Document, param *Parameter) *Property {
+	property := &Property{
+		location:    param.location,
+		refLocation: param.varLocation,
+		description: param.description,
+
+		Name:               param.Name,
+		VisibilityModifier: param.visibility,
+		isReadonly:         param.isReadonly,
+		Types:              newTypeComposite(),
+	}
+	property.Types.merge(param.Type)
+	switch v := document.getLastClass().(type) {
+	case *Class:
+		property.Scope = v.Name
+	case *Trait:
+		property.Scope = v.Name
+	}
+	return property
+}
+
 func newPropertyDeclaration(a analyser, document *Document, node *phrase.Phrase) Symbol {
 	traverser := util.NewTraverser(node)
 	visibility := Public
 	isStatic := false
+	isReadonly := false
 	child := traverser.Advance()
 	for child != nil {
 		if p, ok := child.(*phrase.Phrase); ok {
@@ -55,8 +80,10 @@ func newPropertyDeclaration(a analyser, document *Document, node *phrase.Phrase)
 			case phrase.MemberModifierList:
 				visibility, isStatic, _ = getMemberModifier(p)
 			case phrase.PropertyElementList:
-				newPropertyList(a, document, p, visibility, isStatic)
+				newPropertyList(a, document, p, visibility, isStatic, isReadonly)
 			}
+		} else if t, ok := child.(*lexer.Token); ok && t.Type == lexer.Whitespace {
+			isReadonly = isReadonly || isReadonlyText(document.getTokenText(t))
 		}
 		child = traverser.Advance()
 	}
@@ -64,11 +91,12 @@ func newPropertyDeclaration(a analyser, document *Document, node *phrase.Phrase)
 }
 
 func newPropertyList(a analyser, document *Document, node *phrase.Phrase,
-	visibility VisibilityModifierValue, isStatic bool) {
+	visibility VisibilityModifierValue, isStatic bool, isReadonly bool) {
 	traverser := util.NewTraverser(node)
 	for child := traverser.Advance(); child != nil; child = traverser.Advance() {
 		if p, ok := child.(*phrase.Phrase); ok && p.Type == phrase.PropertyElement {
 			property := newProperty(a, document, p, visibility, isStatic)
+			property.isReadonly = isReadonly
 			document.addSymbol(property)
 		}
 	}
@@ -86,6 +114,8 @@ func newProperty(a analyser, document *Document, node *phrase.Phrase, visibility
 		property.Scope = v.Name
 	case *Trait:
 		property.Scope = v.Name
+	case *Enum:
+		property.Scope = v.Name
 	}
 	traverser := util.NewTraverser(node)
 	child := traverser.Advance()
@@ -163,6 +193,7 @@ func (s *Property) Serialise(e *storage.Encoder) {
 	s.Scope.Write(e)
 	e.WriteInt(int(s.VisibilityModifier))
 	e.WriteBool(s.isStatic)
+	e.WriteBool(s.isReadonly)
 	s.Types.Write(e)
 	e.WriteString(s.description)
 	serialiseDeprecatedTag(e, s.deprecatedTag)
@@ -175,6 +206,7 @@ func ReadProperty(d *storage.Decoder) *Property {
 		Scope:              ReadTypeString(d),
 		VisibilityModifier: VisibilityModifierValue(d.ReadInt()),
 		isStatic:           d.ReadBool(),
+		isReadonly:         d.ReadBool(),
 		Types:              ReadTypeComposite(d),
 		description:        d.ReadString(),
 		deprecatedTag:      deserialiseDeprecatedTag(d),
@@ -196,6 +228,11 @@ func (s *Property) IsStatic() bool {
 	return s.isStatic
 }
 
+// IsReadonly returns whether a property is readonly
+func (s *Property) IsReadonly() bool {
+	return s.isReadonly
+}
+
 // ScopeTypeString returns the class scope of the property
 func (s *Property) ScopeTypeString() TypeString {
 	return s.Scope
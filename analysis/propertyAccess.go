@@ -44,12 +44,21 @@ func (s *PropertyAccess) Resolve(ctx ResolveContext) {
 	}
 	q := ctx.query
 	currentClass := ctx.document.GetClassScopeAtSymbol(s.Scope)
+	notFoundTypes := newTypeComposite()
 	for _, scopeType := range s.ResolveAndGetScope(ctx).Resolve() {
+		found := false
 		for _, class := range q.GetClasses(scopeType.GetFQN()) {
 			for _, p := range q.GetClassProps(class, "$"+s.Name, nil).ReduceAccess(currentClass, s) {
 				s.Type.merge(p.Prop.Types)
+				found = true
 			}
 		}
+		if !found {
+			notFoundTypes.add(scopeType)
+		}
+	}
+	for _, m := range q.GetMagicMethods(notFoundTypes, MagicGet) {
+		s.Type.merge(resolveMemberTypes(m.Method.GetReturnTypes(), s.Scope))
 	}
 	s.hasResolved = true
 }
@@ -130,3 +130,35 @@ class theme_base {
 	assert.IsType(t, &FunctionCall{}, hasTypes)
 	assert.Equal(t, "rawurlencode", hasTypes.(*FunctionCall).Name)
 }
+
+func TestPromotedProperty(t *testing.T) {
+	data, err := ioutil.ReadFile("../cases/promotedProperty.php")
+	assert.NoError(t, err)
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := NewDocument("test1", data)
+		doc.Load()
+		store.SyncDocument(doc)
+		props := []*Property{}
+		TraverseDocument(doc, func(s Symbol) {
+			if prop, ok := s.(*Property); ok {
+				props = append(props, prop)
+			}
+		}, nil)
+		cupaloy.SnapshotT(t, props)
+
+		q := NewQuery(store)
+		classes := q.GetClasses("\\App\\Services\\UserService")
+		assert.Equal(t, 1, len(classes))
+		repo := q.GetClassProps(classes[0], "$repo", nil).Props
+		assert.Equal(t, 1, len(repo))
+		assert.Equal(t, "\\App\\Repositories\\UserRepo", repo[0].Prop.Types.ToString())
+		assert.Equal(t, "The user repository", repo[0].Prop.GetDescription())
+		assert.Equal(t, VisibilityModifierValue(Private), repo[0].Prop.Visibility())
+		assert.True(t, repo[0].Prop.IsReadonly())
+
+		ctx := NewResolveContext(q, doc)
+		access := doc.HasTypesAtPos(protocol.Position{Line: 22, Character: 16})
+		access.Resolve(ctx)
+		assert.Equal(t, "\\App\\Repositories\\UserRepo", access.GetTypes().ToString())
+	})
+}
@@ -7,10 +7,18 @@ import (
 const sep = ":"
 const staticInAccessCost = 16
 
+// Names of the magic methods which handle inaccessible members
+const (
+	MagicGet        = "__get"
+	MagicCall       = "__call"
+	MagicCallStatic = "__callStatic"
+)
+
 // Query is a wrapper around store
 type Query struct {
 	store *Store
 	cache map[string]interface{}
+	trace *Trace
 }
 
 // NewQuery creates a new query, a query should not outlive a request
@@ -26,20 +34,43 @@ func (q Query) Store() *Store {
 	return q.store
 }
 
-// GetClasses is a cached proxy behind store
+// GetClasses is a cached proxy behind store, only the declarations with the
+// highest precedence are returned, enums are also returned as final classes
+// so their members can be looked up
 func (q *Query) GetClasses(name string) []*Class {
 	name = GetClassFQNLowerCase(name)
 	cacheKey := "Classes" + sep + name
 	if data, ok := q.cache[cacheKey]; ok {
 		if classes, ok := data.([]*Class); ok {
+			traceLookup(q.trace, "classes", name, true, classes)
 			return classes
 		}
 	}
-	classes := q.store.GetClasses(name)
+	classes := q.preferredClasses(q.store.GetClasses(name))
+	for _, enum := range q.GetEnums(name) {
+		classes = append(classes, enum.asClass())
+	}
 	q.cache[cacheKey] = classes
+	traceLookup(q.trace, "classes", name, false, classes)
 	return classes
 }
 
+// GetEnums is a cached proxy behind store
+func (q *Query) GetEnums(name string) []*Enum {
+	name = GetClassFQNLowerCase(name)
+	cacheKey := "Enums" + sep + name
+	if data, ok := q.cache[cacheKey]; ok {
+		if enums, ok := data.([]*Enum); ok {
+			traceLookup(q.trace, "enums", name, true, enums)
+			return enums
+		}
+	}
+	enums := q.store.GetEnums(name)
+	q.cache[cacheKey] = enums
+	traceLookup(q.trace, "enums", name, false, enums)
+	return enums
+}
+
 // GetClassConstructor returns the constructor of the given class
 // including the inherited constructor from parent
 func (q *Query) GetClassConstructor(class *Class) MethodWithScope {
@@ -51,11 +82,13 @@ func (q *Query) GetInterfaces(name string) []*Interface {
 	cacheKey := "Interfaces" + sep + name
 	if data, ok := q.cache[cacheKey]; ok {
 		if interfaces, ok := data.([]*Interface); ok {
+			traceLookup(q.trace, "interfaces", name, true, interfaces)
 			return interfaces
 		}
 	}
-	interfaces := q.store.GetInterfaces(name)
+	interfaces := q.preferredInterfaces(q.store.GetInterfaces(name))
 	q.cache[cacheKey] = interfaces
+	traceLookup(q.trace, "interfaces", name, false, interfaces)
 	return interfaces
 }
 
@@ -64,11 +97,13 @@ func (q *Query) GetTraits(name string) []*Trait {
 	cacheKey := "Traits" + sep + name
 	if data, ok := q.cache[cacheKey]; ok {
 		if traits, ok := data.([]*Trait); ok {
+			traceLookup(q.trace, "traits", name, true, traits)
 			return traits
 		}
 	}
-	traits := q.store.GetTraits(name)
+	traits := q.preferredTraits(q.store.GetTraits(name))
 	q.cache[cacheKey] = traits
+	traceLookup(q.trace, "traits", name, false, traits)
 	return traits
 }
 
@@ -77,11 +112,13 @@ func (q *Query) GetFunctions(name string) []*Function {
 	cacheKey := "Functions" + sep + name
 	if data, ok := q.cache[cacheKey]; ok {
 		if functions, ok := data.([]*Function); ok {
+			traceLookup(q.trace, "functions", name, true, functions)
 			return functions
 		}
 	}
-	functions := q.store.GetFunctions(name)
+	functions := q.preferredFunctions(q.store.GetFunctions(name))
 	q.cache[cacheKey] = functions
+	traceLookup(q.trace, "functions", name, false, functions)
 	return functions
 }
 
@@ -90,11 +127,13 @@ func (q *Query) GetConsts(name string) []*Const {
 	cacheKey := "Consts" + sep + name
 	if data, ok := q.cache[cacheKey]; ok {
 		if consts, ok := data.([]*Const); ok {
+			traceLookup(q.trace, "consts", name, true, consts)
 			return consts
 		}
 	}
-	consts := q.store.GetConsts(name)
+	consts := q.preferredConsts(q.store.GetConsts(name))
 	q.cache[cacheKey] = consts
+	traceLookup(q.trace, "consts", name, false, consts)
 	return consts
 }
 
@@ -103,11 +142,13 @@ func (q *Query) GetDefines(name string) []*Define {
 	cacheKey := "Defines" + sep + name
 	if data, ok := q.cache[cacheKey]; ok {
 		if defines, ok := data.([]*Define); ok {
+			traceLookup(q.trace, "defines", name, true, defines)
 			return defines
 		}
 	}
-	defines := q.store.GetDefines(name)
+	defines := q.preferredDefines(q.store.GetDefines(name))
 	q.cache[cacheKey] = defines
+	traceLookup(q.trace, "defines", name, false, defines)
 	return defines
 }
 
@@ -377,6 +418,7 @@ func (q *Query) GetMethods(scope string, name string) []*Method {
 	cacheKey := "Methods" + sep + scope + "::" + name
 	if data, ok := q.cache[cacheKey]; ok {
 		if methods, ok := data.([]*Method); ok {
+			traceLookup(q.trace, "methods", scope+"::"+name, true, methods)
 			return methods
 		}
 	}
@@ -387,6 +429,7 @@ func (q *Query) GetMethods(scope string, name string) []*Method {
 		methods = q.store.GetAllMethods(scope)
 	}
 	q.cache[cacheKey] = methods
+	traceLookup(q.trace, "methods", scope+"::"+name, false, methods)
 	return methods
 }
 
@@ -395,6 +438,9 @@ func (q *Query) GetClassMethods(class *Class, name string, searchedFQNs map[stri
 	classes := []*Class{
 		class,
 	}
+	// the members are not cached if the search is cut short by the FQNs
+	// which have been searched by the caller
+	cacheable := len(searchedFQNs) == 0
 	if searchedFQNs == nil {
 		searchedFQNs = map[string]struct{}{}
 	}
@@ -406,6 +452,7 @@ func (q *Query) GetClassMethods(class *Class, name string, searchedFQNs map[stri
 	}
 	methods := NewInheritedMethods(nil, searchedFQNs)
 	methodsFromInterfaces := NewInheritedMethods(nil, searchedFQNs)
+	var mixins []TypeString
 	for len(classes) > 0 {
 		var class *Class
 		class, classes = classes[0], classes[1:]
@@ -415,11 +462,12 @@ func (q *Query) GetClassMethods(class *Class, name string, searchedFQNs map[stri
 		}
 		searchedFQNs[scope] = struct{}{}
 		methods.Methods = append(methods.Methods, methodWithScopeFromMethods(class, q.GetMethods(scope, name))...)
+		mixins = append(mixins, class.Mixins...)
 		for _, use := range class.Use {
 			if use.IsEmpty() {
 				continue
 			}
-			for _, trait := range q.store.GetTraits(use.GetFQN()) {
+			for _, trait := range q.GetTraits(use.GetFQN()) {
 				methods.Merge(q.GetTraitMethods(trait, name))
 			}
 		}
@@ -432,13 +480,16 @@ func (q *Query) GetClassMethods(class *Class, name string, searchedFQNs map[stri
 			if implement.IsEmpty() {
 				continue
 			}
-			for _, intf := range q.store.GetInterfaces(implement.GetFQN()) {
+			for _, intf := range q.GetInterfaces(implement.GetFQN()) {
 				methodsFromInterfaces.Merge(q.GetInterfaceMethods(intf, name, methodsFromInterfaces.SearchedFQNs))
 			}
 		}
 	}
 	methods.Merge(methodsFromInterfaces)
-	q.cache[cacheKey] = methods
+	methods.Merge(q.getMixinMethods(mixins, name, searchedFQNs))
+	if cacheable {
+		q.cache[cacheKey] = methods
+	}
 	return methods
 }
 
@@ -447,6 +498,7 @@ func (q *Query) GetInterfaceMethods(intf *Interface, name string, searchedFQNs m
 	interfaces := []*Interface{
 		intf,
 	}
+	cacheable := len(searchedFQNs) == 0
 	if searchedFQNs == nil {
 		searchedFQNs = make(map[string]struct{})
 	}
@@ -457,6 +509,7 @@ func (q *Query) GetInterfaceMethods(intf *Interface, name string, searchedFQNs m
 		}
 	}
 	methods := NewInheritedMethods(nil, searchedFQNs)
+	var mixins []TypeString
 	for len(interfaces) > 0 {
 		var intf *Interface
 		intf, interfaces = interfaces[0], interfaces[1:]
@@ -466,18 +519,68 @@ func (q *Query) GetInterfaceMethods(intf *Interface, name string, searchedFQNs m
 		}
 		searchedFQNs[scope] = struct{}{}
 		methods.Methods = append(methods.Methods, methodWithScopeFromMethods(intf, q.GetMethods(scope, name))...)
+		mixins = append(mixins, intf.Mixins...)
 		for _, extend := range intf.Extends {
 			if extend.IsEmpty() {
 				continue
 			}
-			for _, intf := range q.store.GetInterfaces(extend.GetFQN()) {
+			for _, intf := range q.GetInterfaces(extend.GetFQN()) {
 				if _, ok := searchedFQNs[intf.Name.GetFQN()]; !ok {
 					interfaces = append(interfaces, intf)
 				}
 			}
 		}
 	}
-	q.cache[cacheKey] = methods
+	methods.Merge(q.getMixinMethods(mixins, name, searchedFQNs))
+	if cacheable {
+		q.cache[cacheKey] = methods
+	}
+	return methods
+}
+
+// getMixinMethods returns the public methods of the @mixin classes and
+// interfaces, the members are forwarded by magic methods so only the
+// public ones are accessible
+func (q *Query) getMixinMethods(mixins []TypeString, name string, searchedFQNs map[string]struct{}) InheritedMethods {
+	methods := NewInheritedMethods(nil, searchedFQNs)
+	for _, mixin := range mixins {
+		if _, ok := searchedFQNs[mixin.GetFQN()]; ok || mixin.IsEmpty() {
+			continue
+		}
+		for _, class := range q.GetClasses(mixin.GetFQN()) {
+			methods.Merge(q.GetClassMethods(class, name, searchedFQNs))
+		}
+		for _, intf := range q.GetInterfaces(mixin.GetFQN()) {
+			methods.Merge(q.GetInterfaceMethods(intf, name, searchedFQNs))
+		}
+	}
+	publicMethods := methods.Methods[:0]
+	for _, method := range methods.Methods {
+		if method.Method.VisibilityModifier == Public {
+			publicMethods = append(publicMethods, method)
+		}
+	}
+	methods.Methods = publicMethods
+	return methods
+}
+
+// GetMagicMethods returns the given magic method, e.g. __get or __call, of
+// the classes and interfaces of the types, magic methods handle the members
+// which cannot be found
+func (q *Query) GetMagicMethods(types TypeComposite, name string) []MethodWithScope {
+	var methods []MethodWithScope
+	for _, typeString := range types.Resolve() {
+		ms := EmptyInheritedMethods()
+		for _, class := range q.GetClasses(typeString.GetFQN()) {
+			ms.Merge(q.GetClassMethods(class, name, ms.SearchedFQNs))
+		}
+		for _, intf := range q.GetInterfaces(typeString.GetFQN()) {
+			ms.Merge(q.GetInterfaceMethods(intf, name, ms.SearchedFQNs))
+		}
+		if m := ms.ReduceFirst(); m.Method != nil {
+			methods = MergeMethodWithScope(methods, []MethodWithScope{m})
+		}
+	}
 	return methods
 }
 
@@ -622,6 +725,7 @@ func (q *Query) GetClassConsts(scope string, name string) []*ClassConst {
 	cacheKey := "ClassConst" + sep + scope + "::" + name
 	if data, ok := q.cache[cacheKey]; ok {
 		if classConsts, ok := data.([]*ClassConst); ok {
+			traceLookup(q.trace, "classConsts", scope+"::"+name, true, classConsts)
 			return classConsts
 		}
 	}
@@ -632,6 +736,7 @@ func (q *Query) GetClassConsts(scope string, name string) []*ClassConst {
 		classConsts = q.store.GetAllClassConsts(scope)
 	}
 	q.cache[cacheKey] = classConsts
+	traceLookup(q.trace, "classConsts", scope+"::"+name, false, classConsts)
 	return classConsts
 }
 
@@ -646,16 +751,23 @@ func (q *Query) GetClassClassConsts(class *Class, name string, searchedFQNs map[
 	classes := []*Class{
 		class,
 	}
+	cacheable := len(searchedFQNs) == 0
 	if searchedFQNs == nil {
 		searchedFQNs = make(map[string]struct{})
 	}
 	classConsts := NewInheritedClassConst(nil, searchedFQNs)
 	classConstsFromInterfaces := NewInheritedClassConst(nil, searchedFQNs)
+	var mixins []TypeString
 	for len(classes) > 0 {
 		var class *Class
 		class, classes = classes[0], classes[1:]
+		if _, ok := searchedFQNs[class.Name.GetFQN()]; ok {
+			continue
+		}
+		searchedFQNs[class.Name.GetFQN()] = struct{}{}
 		classConsts.Consts = append(classConsts.Consts,
 			classConstWithScopeFromConsts(class, q.GetClassConsts(class.Name.GetFQN(), name))...)
+		mixins = append(mixins, class.Mixins...)
 		if !class.Extends.IsEmpty() {
 			if _, ok := searchedFQNs[class.Extends.GetFQN()]; !ok {
 				classConsts.RelationMap.Relate(class.Name.GetFQN(), class.Extends.GetFQN())
@@ -673,7 +785,10 @@ func (q *Query) GetClassClassConsts(class *Class, name string, searchedFQNs map[
 		}
 	}
 	classConsts.Merge(classConstsFromInterfaces)
-	q.cache[cacheKey] = classConsts
+	classConsts.Merge(q.getMixinClassConsts(mixins, name, searchedFQNs))
+	if cacheable {
+		q.cache[cacheKey] = classConsts
+	}
 	return classConsts
 }
 
@@ -682,6 +797,7 @@ func (q *Query) GetInterfaceClassConsts(intf *Interface, name string, searchedFQ
 	interfaces := []*Interface{
 		intf,
 	}
+	cacheable := len(searchedFQNs) == 0
 	if searchedFQNs == nil {
 		searchedFQNs = make(map[string]struct{})
 	}
@@ -692,6 +808,7 @@ func (q *Query) GetInterfaceClassConsts(intf *Interface, name string, searchedFQ
 		}
 	}
 	classConsts := NewInheritedClassConst(nil, searchedFQNs)
+	var mixins []TypeString
 	for len(interfaces) > 0 {
 		intf, interfaces = interfaces[0], interfaces[1:]
 		scope := intf.Name.GetFQN()
@@ -701,6 +818,7 @@ func (q *Query) GetInterfaceClassConsts(intf *Interface, name string, searchedFQ
 		searchedFQNs[scope] = struct{}{}
 		classConsts.Consts = append(classConsts.Consts,
 			classConstWithScopeFromConsts(intf, q.GetClassConsts(scope, name))...)
+		mixins = append(mixins, intf.Mixins...)
 		for _, extend := range intf.Extends {
 			if extend.IsEmpty() {
 				continue
@@ -713,7 +831,35 @@ func (q *Query) GetInterfaceClassConsts(intf *Interface, name string, searchedFQ
 			}
 		}
 	}
-	q.cache[cacheKey] = classConsts
+	classConsts.Merge(q.getMixinClassConsts(mixins, name, searchedFQNs))
+	if cacheable {
+		q.cache[cacheKey] = classConsts
+	}
+	return classConsts
+}
+
+// getMixinClassConsts returns the public class consts of the @mixin
+// classes and interfaces
+func (q *Query) getMixinClassConsts(mixins []TypeString, name string, searchedFQNs map[string]struct{}) InheritedClassConst {
+	classConsts := NewInheritedClassConst(nil, searchedFQNs)
+	for _, mixin := range mixins {
+		if _, ok := searchedFQNs[mixin.GetFQN()]; ok || mixin.IsEmpty() {
+			continue
+		}
+		for _, class := range q.GetClasses(mixin.GetFQN()) {
+			classConsts.Merge(q.GetClassClassConsts(class, name, searchedFQNs))
+		}
+		for _, intf := range q.GetInterfaces(mixin.GetFQN()) {
+			classConsts.Merge(q.GetInterfaceClassConsts(intf, name, searchedFQNs))
+		}
+	}
+	publicConsts := classConsts.Consts[:0]
+	for _, classConst := range classConsts.Consts {
+		if classConst.Const.VisibilityModifier == Public {
+			publicConsts = append(publicConsts, classConst)
+		}
+	}
+	classConsts.Consts = publicConsts
 	return classConsts
 }
 
@@ -867,6 +1013,7 @@ func (q *Query) GetProps(scope, name string) []*Property {
 	cacheKey := "Props" + sep + scope + "::" + name
 	if data, ok := q.cache[cacheKey]; ok {
 		if p, ok := data.([]*Property); ok {
+			traceLookup(q.trace, "props", scope+"::"+name, true, p)
 			return p
 		}
 	}
@@ -877,6 +1024,7 @@ func (q *Query) GetProps(scope, name string) []*Property {
 		p = q.store.GetAllProperties(scope)
 	}
 	q.cache[cacheKey] = p
+	traceLookup(q.trace, "props", scope+"::"+name, false, p)
 	return p
 }
 
@@ -885,6 +1033,7 @@ func (q *Query) GetClassProps(class *Class, name string, searchedFQNs map[string
 	classes := []*Class{
 		class,
 	}
+	cacheable := len(searchedFQNs) == 0
 	if searchedFQNs == nil {
 		searchedFQNs = map[string]struct{}{}
 	}
@@ -896,9 +1045,15 @@ func (q *Query) GetClassProps(class *Class, name string, searchedFQNs map[string
 	}
 	props := NewInheritedProps(nil, searchedFQNs)
 	propsFromInterfaces := NewInheritedProps(nil, searchedFQNs)
+	var mixins []TypeString
 	for len(classes) > 0 {
 		class, classes = classes[0], classes[1:]
+		if _, ok := searchedFQNs[class.Name.GetFQN()]; ok {
+			continue
+		}
+		searchedFQNs[class.Name.GetFQN()] = struct{}{}
 		props.Props = append(props.Props, propWithScopeFromProps(class, q.GetProps(class.Name.GetFQN(), name))...)
+		mixins = append(mixins, class.Mixins...)
 		if !class.Extends.IsEmpty() {
 			if _, ok := searchedFQNs[class.Extends.GetFQN()]; !ok {
 				classes = append(classes, q.GetClasses(class.Extends.GetFQN())...)
@@ -914,7 +1069,10 @@ func (q *Query) GetClassProps(class *Class, name string, searchedFQNs map[string
 		}
 	}
 	props.Merge(propsFromInterfaces)
-	q.cache[cacheKey] = props
+	props.Merge(q.getMixinProps(mixins, name, searchedFQNs))
+	if cacheable {
+		q.cache[cacheKey] = props
+	}
 	return props
 }
 
@@ -923,6 +1081,7 @@ func (q *Query) GetInterfaceProps(intf *Interface, name string, searchedFQNs map
 	interfaces := []*Interface{
 		intf,
 	}
+	cacheable := len(searchedFQNs) == 0
 	if searchedFQNs == nil {
 		searchedFQNs = make(map[string]struct{})
 	}
@@ -933,6 +1092,7 @@ func (q *Query) GetInterfaceProps(intf *Interface, name string, searchedFQNs map
 		}
 	}
 	props := NewInheritedProps(nil, searchedFQNs)
+	var mixins []TypeString
 	for len(interfaces) > 0 {
 		intf, interfaces = interfaces[0], interfaces[1:]
 		scope := intf.Name.GetFQN()
@@ -941,6 +1101,7 @@ func (q *Query) GetInterfaceProps(intf *Interface, name string, searchedFQNs map
 		}
 		searchedFQNs[scope] = struct{}{}
 		props.Props = append(props.Props, propWithScopeFromProps(intf, q.GetProps(scope, name))...)
+		mixins = append(mixins, intf.Mixins...)
 		for _, extend := range intf.Extends {
 			if extend.IsEmpty() {
 				continue
@@ -952,7 +1113,35 @@ func (q *Query) GetInterfaceProps(intf *Interface, name string, searchedFQNs map
 			}
 		}
 	}
-	q.cache[cacheKey] = props
+	props.Merge(q.getMixinProps(mixins, name, searchedFQNs))
+	if cacheable {
+		q.cache[cacheKey] = props
+	}
+	return props
+}
+
+// getMixinProps returns the public properties of the @mixin classes
+// and interfaces
+func (q *Query) getMixinProps(mixins []TypeString, name string, searchedFQNs map[string]struct{}) InheritedProps {
+	props := NewInheritedProps(nil, searchedFQNs)
+	for _, mixin := range mixins {
+		if _, ok := searchedFQNs[mixin.GetFQN()]; ok || mixin.IsEmpty() {
+			continue
+		}
+		for _, class := range q.GetClasses(mixin.GetFQN()) {
+			props.Merge(q.GetClassProps(class, name, searchedFQNs))
+		}
+		for _, intf := range q.GetInterfaces(mixin.GetFQN()) {
+			props.Merge(q.GetInterfaceProps(intf, name, searchedFQNs))
+		}
+	}
+	publicProps := props.Props[:0]
+	for _, prop := range props.Props {
+		if prop.Prop.VisibilityModifier == Public {
+			publicProps = append(publicProps, prop)
+		}
+	}
+	props.Props = publicProps
 	return props
 }
 
@@ -357,3 +357,124 @@ func TestPropsInheritance(t *testing.T) {
 		}}, true)
 	})
 }
+
+func TestMixins(t *testing.T) {
+	data, err := ioutil.ReadFile("../cases/mixin.php")
+	if err != nil {
+		panic(err)
+	}
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := NewDocument("test1", data)
+		doc.Load()
+		store.SyncDocument(doc)
+		q := NewQuery(store)
+		mockAccess := &mockMemberAccess{
+			scopeTypes: newTypeComposite(),
+			scopeName:  "$user",
+		}
+		mockAccess.scopeTypes.add(NewTypeString("\\App\\User"))
+		classes := q.GetClasses("\\App\\User")
+		assert.Equal(t, 1, len(classes))
+		assert.Equal(t, 1, len(classes[0].Mixins))
+		assert.Equal(t, "\\App\\Model", classes[0].Mixins[0].GetFQN())
+
+		methods := q.GetClassMethods(classes[0], "where", nil).ReduceAccess("", mockAccess)
+		assert.Equal(t, 1, len(methods))
+		assert.Equal(t, "\\Illuminate\\Database\\Eloquent\\Builder", methods[0].Method.ScopeTypeString().GetFQN())
+		assert.Equal(t, 0, len(q.GetClassMethods(classes[0], "addBinding", nil).ReduceAccess("", mockAccess)))
+		assert.Equal(t, 1, len(q.GetClassMethods(classes[0], "save", nil).ReduceAccess("", mockAccess)))
+		props := q.GetClassProps(classes[0], "$wheres", nil).ReduceAccess("", mockAccess)
+		assert.Equal(t, 1, len(props))
+		assert.Equal(t, 0, len(q.GetClassProps(classes[0], "$bindings", nil).ReduceAccess("", mockAccess)))
+		classConsts := q.GetClassClassConsts(classes[0], "ASC", nil)
+		assert.Equal(t, 1, len(classConsts.Consts))
+
+		completions := q.SearchClassMethods(classes[0], "wh", nil).ReduceAccess("", mockAccess)
+		assert.Equal(t, 1, len(completions))
+	})
+}
+
+func TestMixinsAreNotCachedPartially(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := NewDocument("test1", []byte(`<?php
+class Base
+{
+    public function foo() {}
+}
+
+class Inner extends Base {}
+
+/**
+ * @mixin Inner
+ */
+class Outer extends Base {}`))
+		doc.Load()
+		store.SyncDocument(doc)
+		q := NewQuery(store)
+		outer := q.GetClasses("\\Outer")
+		assert.Equal(t, 1, len(outer))
+		assert.Equal(t, 1, len(q.GetClassMethods(outer[0], "foo", nil).Methods))
+		inner := q.GetClasses("\\Inner")
+		assert.Equal(t, 1, len(inner))
+		assert.Equal(t, 1, len(q.GetClassMethods(inner[0], "foo", nil).Methods))
+	})
+}
+
+func TestMagicMembers(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		doc := NewDocument("test1", []byte(`<?php
+class Config
+{
+    public $name;
+
+    /**
+     * @return string
+     */
+    public function __get($key) {}
+
+    public function __call($method, $args): Config {}
+
+    /**
+     * @return DateTime
+     */
+    public static function __callStatic($method, $args) {}
+}
+
+$config = new Config();
+$config->timezone;
+$config->name;
+$config->set('a', 1);
+Config::now();
+
+class AppConfig extends Config
+{
+    public function load()
+    {
+        return parent::fetch();
+    }
+
+    public static function make()
+    {
+        return self::fetch();
+    }
+}`))
+		doc.Load()
+		store.SyncDocument(doc)
+		ctx := NewResolveContext(NewQuery(store), doc)
+		typesAt := func(pos protocol.Position) string {
+			symbol := doc.HasTypesAtPos(pos)
+			symbol.Resolve(ctx)
+			return symbol.GetTypes().ToString()
+		}
+		assert.Equal(t, "string", typesAt(protocol.Position{Line: 19, Character: 12}))
+		assert.Equal(t, "", typesAt(protocol.Position{Line: 20, Character: 12}))
+		assert.Equal(t, "\\Config", typesAt(protocol.Position{Line: 21, Character: 10}))
+		assert.Equal(t, "\\DateTime", typesAt(protocol.Position{Line: 22, Character: 10}))
+		assert.Equal(t, "\\Config", typesAt(protocol.Position{Line: 28, Character: 24}))
+		assert.Equal(t, "\\DateTime", typesAt(protocol.Position{Line: 33, Character: 22}))
+
+		methods := NewQuery(store).GetMagicMethods(doc.HasTypesAtPos(protocol.Position{Line: 21, Character: 10}).(*MethodAccess).Scope.GetTypes(), MagicCall)
+		assert.Equal(t, 1, len(methods))
+		assert.Equal(t, "__call", methods[0].Method.GetName())
+	})
+}
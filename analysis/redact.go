@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/john-nguyen09/go-phpparser/lexer"
+)
+
+// redactedTokens are the tokens which may contain private data, the code
+// itself is kept because it is what reproduces a bug
+var redactedTokens = map[lexer.TokenType]struct{}{
+	lexer.StringLiteral:             {},
+	lexer.EncapsulatedAndWhitespace: {},
+	lexer.Text:                      {},
+	lexer.Comment:                   {},
+	lexer.DocumentCommentText:       {},
+}
+
+func isDocTag(t lexer.TokenType) bool {
+	return t == lexer.DocumentCommentTagName ||
+		(t > lexer.DocumentCommentTagNameAnchorStart && t < lexer.DocumentCommentTagNameAnchorEnd)
+}
+
+// RedactText replaces the letters and digits of the strings, the comments
+// and the inline HTML with x. The lines of the doc comments which have a
+// tag are kept because their types are analysed. Every character is
+// replaced by one x per UTF-16 unit, which the positions of LSP count, and
+// the line breaks are kept, so the positions in a bug report still point to
+// the same code
+func RedactText(text []byte) []byte {
+	redacted := make([]byte, 0, len(text))
+	offset := 0
+	inDocComment, tagLine := false, false
+	l := lexer.NewLexer(text, nil, 0)
+	for t := l.Lex(); t.Type != lexer.EndOfFile; t = l.Lex() {
+		switch {
+		case t.Type == lexer.DocumentCommentStart:
+			inDocComment = true
+		case t.Type == lexer.DocumentCommentEnd:
+			inDocComment = false
+		case t.Type == lexer.DocumentCommentStartline || t.Type == lexer.DocumentCommentEndline:
+			tagLine = false
+		case isDocTag(t.Type):
+			tagLine = true
+		}
+		_, ok := redactedTokens[t.Type]
+		if inDocComment && !tagLine && (t.Type == lexer.Name || t.Type == lexer.Unknown) {
+			ok = true
+		}
+		if !ok || t.Offset < offset {
+			continue
+		}
+		redacted = append(redacted, text[offset:t.Offset]...)
+		redacted = append(redacted, redactBytes(text[t.Offset:t.Offset+t.Length])...)
+		offset = t.Offset + t.Length
+	}
+	return append(redacted, text[offset:]...)
+}
+
+func redactBytes(b []byte) []byte {
+	redacted := make([]byte, 0, len(b))
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			for i := 0; i < utf16.RuneLen(r); i++ {
+				redacted = append(redacted, 'x')
+			}
+		} else {
+			redacted = append(redacted, b[:size]...)
+		}
+		b = b[size:]
+	}
+	return redacted
+}
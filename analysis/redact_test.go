@@ -0,0 +1,37 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactText(t *testing.T) {
+	text := "<h1>Secret Page</h1>\n" +
+		"<?php\n" +
+		"// the password is hunter2\n" +
+		"/**\n" +
+		" * Sends the token\n" +
+		" * @param string $token\n" +
+		" */\n" +
+		"function send(string $token) {\n" +
+		"    $url = 'https://example.com/ĐŁ';\n" +
+		"    $name = '𝒳 is a math letter';\n" +
+		"    echo \"Hello $token, welcome\";\n" +
+		"    return 42;\n" +
+		"}\n"
+	expected := "<xx>xxxxxx xxxx</xx>\n" +
+		"<?php\n" +
+		"// xxx xxxxxxxx xx xxxxxxx\n" +
+		"/**\n" +
+		" * xxxxx xxx xxxxx\n" +
+		" * @param string $token\n" +
+		" */\n" +
+		"function send(string $token) {\n" +
+		"    $url = 'xxxxx://xxxxxxx.xxx/xx';\n" +
+		"    $name = 'xx xx x xxxx xxxxxx';\n" +
+		"    echo \"xxxxx $token, xxxxxxx\";\n" +
+		"    return 42;\n" +
+		"}\n"
+	assert.Equal(t, expected, string(RedactText([]byte(text))))
+}
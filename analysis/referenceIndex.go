@@ -8,6 +8,7 @@ import (
 	"github.com/john-nguyen09/phpintel/analysis/filter"
 	"github.com/john-nguyen09/phpintel/analysis/storage"
 	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/stub"
 	"github.com/john-nguyen09/phpintel/util"
 	cmap "github.com/orcaman/concurrent-map"
 )
@@ -162,5 +163,5 @@ func (i *referenceIndex) search(store *Store, ref string) []protocol.Location {
 }
 
 func haveReferences(uri string) bool {
-	return !strings.HasPrefix(uri, "phpstorm-stubs://")
+	return !stub.IsStub(uri)
 }
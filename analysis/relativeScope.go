@@ -37,6 +37,8 @@ func newRelativeScope(document *Document, location protocol.Location) *RelativeS
 		types.add(v.Name)
 	case *Trait:
 		types.add(v.Name)
+	case *Enum:
+		types.add(v.Name)
 	}
 	return &RelativeScope{
 		location: location,
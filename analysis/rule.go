@@ -0,0 +1,158 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+// Codes of the diagnostics which are not specific to a group of checks
+const (
+	CodeSyntaxError      = "syntax-error"
+	CodeUnusedVariable   = "unused-variable"
+	CodeUnusedImport     = "unused-import"
+	CodeDeprecated       = "deprecated"
+	CodeArgumentType     = "argument-type"
+	CodeReturnType       = "return-type"
+	CodeNamedArgument    = "named-argument"
+	CodeTooManyArguments = "too-many-arguments"
+	CodeMissingArgument  = "missing-argument"
+)
+
+// Rule describes a check which reports diagnostics, the code is stable so
+// it can be used to disable or suppress the diagnostics of the rule
+type Rule struct {
+	Code        string
+	Severity    protocol.DiagnosticSeverity
+	Tags        []protocol.DiagnosticTag
+	Description string
+	// ConfigKey is the key of the rule in the settings of the clients
+	ConfigKey string
+}
+
+var rules = map[string]*Rule{}
+
+func registerRule(code string, severity protocol.DiagnosticSeverity, description string, tags ...protocol.DiagnosticTag) {
+	rules[code] = &Rule{
+		Code:        code,
+		Severity:    severity,
+		Tags:        tags,
+		Description: description,
+		ConfigKey:   configKey(code),
+	}
+}
+
+func init() {
+	registerRule(CodeSyntaxError, protocol.SeverityError, "The code cannot be parsed.")
+	registerRule(CodeUnusedVariable, protocol.SeverityHint, "A variable is assigned but never read.", protocol.Unnecessary)
+	registerRule(CodeUnusedImport, protocol.SeverityHint, "A use declaration is never used.", protocol.Unnecessary)
+	registerRule(CodeDeprecated, protocol.SeverityHint, "A symbol with a @deprecated tag is used.", protocol.Deprecated)
+	registerRule(CodeArgumentType, protocol.SeverityError, "An argument does not match the type of its parameter.")
+	registerRule(CodeReturnType, protocol.SeverityError, "A function does not return as its return type declares.")
+	registerRule(CodeNamedArgument, protocol.SeverityError, "A named argument is unknown, duplicated or misplaced.")
+	registerRule(CodeTooManyArguments, protocol.SeverityWarning, "A call has more arguments than the parameters of the callee.")
+	registerRule(CodeMissingArgument, protocol.SeverityError, "A call does not provide a required parameter.")
+	registerRule(CodeInaccessibleMethod, protocol.SeverityError, "A method is called from where it is not visible.")
+	registerRule(CodeInaccessibleProperty, protocol.SeverityError, "A property is accessed from where it is not visible.")
+	registerRule(CodeNonStaticCall, protocol.SeverityError, "An instance method is called statically.")
+	registerRule(CodeThisInStaticContext, protocol.SeverityError, "$this is used in a static method or closure.")
+	registerRule(CodeAbstractInstantiation, protocol.SeverityError, "An abstract class or an interface is instantiated.")
+	registerRule(CodeUnusedPrivateMember, protocol.SeverityHint, "A private member is never used in its class.", protocol.Unnecessary)
+	registerRule(CodeUnusedParameter, protocol.SeverityHint, "A parameter is never read.", protocol.Unnecessary)
+	registerRule(CodeUndefinedVariable, protocol.SeverityWarning, "A variable is read before it is defined.")
+	registerRule(CodePossiblyUndefinedVariable, protocol.SeverityInformation, "A variable is only defined in some paths before it is read.")
+	registerRule(CodeUnreachableCode, protocol.SeverityHint, "A statement can never be executed.", protocol.Unnecessary)
+	registerRule(CodeConstantCondition, protocol.SeverityWarning, "A condition is always true or always false.")
+	registerRule(CodeNeverThrownException, protocol.SeverityWarning, "A caught exception is never thrown in the try block.")
+	registerRule(CodeDuplicateDeclaration, protocol.SeverityWarning, "A symbol is declared more than once in the workspace.")
+	registerRule(CodeUnknownSuppressionCode, protocol.SeverityWarning, "A suppression comment has a code which is not a rule.")
+	registerRule(CodeStaleBaselineEntry, protocol.SeverityInformation, "A baseline entry no longer matches any diagnostic.")
+}
+
+// configKey converts the code to camel case, e.g. undefined-variable
+// to undefinedVariable
+func configKey(code string) string {
+	parts := strings.Split(code, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// Rules returns the registered rules sorted by their codes
+func Rules() []Rule {
+	results := []Rule{}
+	for _, rule := range rules {
+		results = append(results, *rule)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Code < results[j].Code
+	})
+	return results
+}
+
+// GetRule returns the rule of the code
+func GetRule(code string) (Rule, bool) {
+	if rule, ok := rules[code]; ok {
+		return *rule, true
+	}
+	return Rule{}, false
+}
+
+// GetRuleByConfigKey returns the rule of the key in the settings
+func GetRuleByConfigKey(key string) (Rule, bool) {
+	for _, rule := range rules {
+		if rule.ConfigKey == key {
+			return *rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// newDiagnostic creates a diagnostic with the code, severity and tags of the rule,
+// the diagnostics of which codes are not registered are warnings
+func newDiagnostic(code string, r protocol.Range, message string) protocol.Diagnostic {
+	severity := protocol.SeverityWarning
+	var tags []protocol.DiagnosticTag
+	if rule, ok := rules[code]; ok {
+		severity = rule.Severity
+		tags = rule.Tags
+	}
+	return protocol.Diagnostic{
+		Range:    r,
+		Message:  message,
+		Code:     code,
+		Source:   source,
+		Severity: severity,
+		Tags:     tags,
+	}
+}
+
+var severityNames = map[protocol.DiagnosticSeverity]string{
+	protocol.SeverityError:       "error",
+	protocol.SeverityWarning:     "warning",
+	protocol.SeverityInformation: "information",
+	protocol.SeverityHint:        "hint",
+}
+
+// ParseSeverity returns the severity of the name, i.e. error, warning,
+// information or hint
+func ParseSeverity(name string) (protocol.DiagnosticSeverity, bool) {
+	for severity, n := range severityNames {
+		if n == name {
+			return severity, true
+		}
+	}
+	return 0, false
+}
+
+// SeverityName returns the name of the severity
+func SeverityName(severity protocol.DiagnosticSeverity) string {
+	if name, ok := severityNames[severity]; ok {
+		return name
+	}
+	return "error"
+}
@@ -0,0 +1,55 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules(t *testing.T) {
+	for _, rule := range Rules() {
+		assert.NotEmpty(t, rule.Description, rule.Code)
+		found, ok := GetRuleByConfigKey(rule.ConfigKey)
+		assert.True(t, ok, rule.Code)
+		assert.Equal(t, rule.Code, found.Code)
+	}
+	rule, ok := GetRule(CodePossiblyUndefinedVariable)
+	assert.True(t, ok)
+	assert.Equal(t, "possiblyUndefinedVariable", rule.ConfigKey)
+	assert.Equal(t, protocol.SeverityInformation, rule.Severity)
+	_, ok = GetRule("unknown")
+	assert.False(t, ok)
+
+	severity, ok := ParseSeverity("warning")
+	assert.True(t, ok)
+	assert.Equal(t, protocol.SeverityWarning, severity)
+	assert.Equal(t, "hint", SeverityName(protocol.SeverityHint))
+	_, ok = ParseSeverity("off")
+	assert.False(t, ok)
+
+	diagnostic := newDiagnostic("unknown", protocol.Range{}, "Unknown.")
+	assert.Equal(t, protocol.SeverityWarning, diagnostic.Severity)
+	assert.Empty(t, diagnostic.Tags)
+}
+
+func TestDiagnosticsHaveRules(t *testing.T) {
+	withTestStore("test", t.Name(), func(store *Store) {
+		for _, path := range []string{
+			"../cases/argumentDiagnostic.php",
+			"../cases/deadCode.php",
+			"../cases/typeDiagnostic.php",
+			"../cases/undefinedVariable.php",
+		} {
+			doc := openDocument(store, path, path)
+			diagnostics := DocumentDiagnostics(doc)
+			diagnostics = append(diagnostics, ResolvedDiagnostics(NewResolveContext(NewQuery(store), doc))...)
+			assert.NotEmpty(t, diagnostics, path)
+			for _, diagnostic := range diagnostics {
+				rule, ok := GetRule(DiagnosticCode(diagnostic))
+				assert.True(t, ok, diagnostic.Message)
+				assert.Equal(t, rule.Severity, diagnostic.Severity, diagnostic.Message)
+			}
+		}
+	})
+}
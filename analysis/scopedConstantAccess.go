@@ -9,6 +9,8 @@ import (
 // ScopedConstantAccess represents a reference to constant in class access, e.g. ::CONSTANT
 type ScopedConstantAccess struct {
 	MemberAccessExpression
+
+	hasResolved bool
 }
 
 var _ HasTypesHasScope = (*ScopedConstantAccess)(nil)
@@ -40,6 +42,24 @@ func (s *ScopedConstantAccess) GetLocation() protocol.Location {
 	return s.Location
 }
 
+// Resolve resolves the types of enum cases to the enums
+func (s *ScopedConstantAccess) Resolve(ctx ResolveContext) {
+	if s.hasResolved {
+		return
+	}
+	q := ctx.query
+	s.hasResolved = true
+	for _, scopeType := range s.ResolveAndGetScope(ctx).Resolve() {
+		for _, enum := range q.GetEnums(scopeType.GetFQN()) {
+			for _, c := range q.GetClassConsts(enum.Name.GetFQN(), s.Name) {
+				if c.IsEnumCase() {
+					s.Type.add(enum.Name)
+				}
+			}
+		}
+	}
+}
+
 func (s *ScopedConstantAccess) GetTypes() TypeComposite {
 	// TODO: Look up constant types
 	return s.Type
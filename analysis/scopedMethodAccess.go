@@ -10,7 +10,8 @@ import (
 type ScopedMethodAccess struct {
 	MemberAccessExpression
 
-	hasResolved bool
+	hasResolved      bool
+	inInstanceMethod bool
 }
 
 var _ HasTypesHasScope = (*ScopedMethodAccess)(nil)
@@ -29,6 +30,9 @@ func newScopedMethodAccess(a analyser, document *Document, node *phrase.Phrase)
 		document.addSymbol(classAccess)
 		methodAccess.Scope = classAccess
 	}
+	if method := document.currentMethod(); method != nil {
+		methodAccess.inInstanceMethod = !method.IsStatic()
+	}
 	traverser.Advance()
 	thirdChild := traverser.Advance()
 	methodAccess.Location = document.GetNodeLocation(thirdChild)
@@ -58,18 +62,39 @@ func (s *ScopedMethodAccess) Resolve(ctx ResolveContext) {
 	s.hasResolved = true
 	q := ctx.query
 	currentClass := ctx.document.GetClassScopeAtSymbol(s.Scope)
+	notFoundTypes := newTypeComposite()
 	for _, scopeType := range s.ResolveAndGetScope(ctx).Resolve() {
+		found := false
 		for _, class := range q.GetClasses(scopeType.GetFQN()) {
 			for _, m := range q.GetClassMethods(class, s.Name, nil).ReduceStatic(currentClass, s) {
 				s.Type.merge(resolveMemberTypes(m.Method.GetReturnTypes(), s.Scope))
+				found = true
 			}
 		}
 		for _, intf := range q.GetInterfaces(scopeType.GetFQN()) {
 			for _, m := range q.GetInterfaceMethods(intf, s.Name, nil).ReduceStatic(currentClass, s) {
 				s.Type.merge(resolveMemberTypes(m.Method.GetReturnTypes(), s.Scope))
+				found = true
 			}
 		}
+		if !found {
+			notFoundTypes.add(scopeType)
+		}
+	}
+	for _, m := range q.GetMagicMethods(notFoundTypes, s.MagicMethodName()) {
+		s.Type.merge(resolveMemberTypes(m.Method.GetReturnTypes(), s.Scope))
+	}
+}
+
+// MagicMethodName returns the magic method which handles the call when the
+// method does not exist, the calls through self, static or parent from an
+// instance method are handled by __call instead of __callStatic
+func (s *ScopedMethodAccess) MagicMethodName() string {
+	scopeName := s.ScopeName()
+	if s.inInstanceMethod && (IsNameRelative(scopeName) || IsNameParent(scopeName)) {
+		return MagicCall
 	}
+	return MagicCallStatic
 }
 
 func (s *ScopedMethodAccess) GetTypes() TypeComposite {
@@ -0,0 +1,351 @@
+package analysis
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/john-nguyen09/go-phpparser/lexer"
+	"github.com/john-nguyen09/go-phpparser/parser"
+	"github.com/john-nguyen09/go-phpparser/phrase"
+)
+
+// enumPattern is a quick check whether the source may declare an enum
+var /* const */ enumPattern = regexp.MustCompile(`(?i)\benum\s+[a-z_\x80-\xff]`)
+
+// readonlyPattern is a quick check whether the source may have readonly modifiers
+var /* const */ readonlyPattern = regexp.MustCompile(`(?i)\breadonly\s`)
+
+// namedArgumentSourcePattern is a quick check whether the source may have named arguments
+var /* const */ namedArgumentSourcePattern = regexp.MustCompile(`[(,]\s*[a-zA-Z_\x80-\xff][a-zA-Z0-9_\x80-\xff]*\s*:[^:]`)
+
+var /* const */ attributeGroupStart = []byte("#[")
+
+// attributeGroup is the span of an attribute group, i.e. #[...], in the source
+type attributeGroup struct {
+	start int
+	end   int
+}
+
+// sourceEdit replaces length bytes at offset of the original source with text
+type sourceEdit struct {
+	offset int
+	length int
+	text   string
+}
+
+// parseSource parses the source, the parser does not support enums so they
+// are rewritten to classes before parsing, e.g. enum Status: string { case A = 'a'; }
+// is parsed as class Status extends string { const A = 'a'; }, then the offsets of the
+// tokens are mapped back to the original source. The readonly modifiers and the
+// names of named arguments are not supported either so they are replaced and
+// read from the original text. The attribute groups are blanked out and returned
+// to be parsed separately.
+func parseSource(source []byte) (*phrase.Phrase, []attributeGroup) {
+	var groups []attributeGroup
+	if bytes.Contains(source, attributeGroupStart) {
+		source, groups = blankAttributeGroups(source)
+	}
+	if !enumPattern.Match(source) && !readonlyPattern.Match(source) && !namedArgumentSourcePattern.Match(source) {
+		return parser.Parse(source), groups
+	}
+	edits := sourceEdits(source)
+	if len(edits) == 0 {
+		return parser.Parse(source), groups
+	}
+	rootNode := parser.Parse(applyEdits(source, edits))
+	restoreOffsets(rootNode, edits, map[*lexer.Token]struct{}{})
+	return rootNode, groups
+}
+
+func isSignificantToken(t lexer.Token) bool {
+	if t.Type == lexer.Whitespace || t.Type == lexer.Comment {
+		return false
+	}
+	return t.Type < lexer.DocumentCommentStart || t.Type > lexer.DocumentCommentEnd
+}
+
+func significantTokens(source []byte) []lexer.Token {
+	tokens := []lexer.Token{}
+	l := lexer.NewLexer(source, nil, 0)
+	for t := l.Lex(); t.Type != lexer.EndOfFile; t = l.Lex() {
+		if isSignificantToken(*t) {
+			tokens = append(tokens, *t)
+		}
+	}
+	return tokens
+}
+
+func sourceEdits(source []byte) []sourceEdit {
+	tokens := significantTokens(source)
+	edits := append(enumEdits(source, tokens), readonlyEdits(source, tokens)...)
+	edits = append(edits, namedArgumentEdits(source, tokens)...)
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].offset < edits[j].offset
+	})
+	return edits
+}
+
+func tokenText(source []byte, t lexer.Token) string {
+	return string(source[t.Offset : t.Offset+t.Length])
+}
+
+func enumEdits(source []byte, tokens []lexer.Token) []sourceEdit {
+	text := func(t lexer.Token) string {
+		return tokenText(source, t)
+	}
+	edits := []sourceEdit{}
+	for i := 0; i+2 < len(tokens); i++ {
+		t := tokens[i]
+		if t.Type != lexer.Name || strings.ToLower(text(t)) != "enum" || tokens[i+1].Type != lexer.Name {
+			continue
+		}
+		if i > 0 {
+			switch tokens[i-1].Type {
+			case lexer.Arrow, lexer.ColonColon, lexer.Backslash, lexer.Function, lexer.Const, lexer.New:
+				continue
+			}
+		}
+		switch tokens[i+2].Type {
+		case lexer.Colon, lexer.Implements, lexer.OpenBrace:
+		default:
+			continue
+		}
+		edits = append(edits, sourceEdit{t.Offset, t.Length, "class"})
+		i += 2
+		if tokens[i].Type == lexer.Colon {
+			// The backed type is kept as the base class
+			edits = append(edits, sourceEdit{tokens[i].Offset, tokens[i].Length, " extends"})
+		}
+		for i < len(tokens) && tokens[i].Type != lexer.OpenBrace {
+			i++
+		}
+		depth := 0
+		for ; i < len(tokens); i++ {
+			switch tokens[i].Type {
+			case lexer.OpenBrace, lexer.CurlyOpen, lexer.DollarCurlyOpen:
+				depth++
+			case lexer.CloseBrace:
+				depth--
+			case lexer.Case:
+				if depth == 1 {
+					edits = append(edits, sourceEdit{tokens[i].Offset, tokens[i].Length, "const"})
+					if i+2 < len(tokens) && tokens[i+2].Type == lexer.Semicolon {
+						// Cases of pure enums have no values
+						edits = append(edits, sourceEdit{tokens[i+2].Offset, 0, " = null"})
+					}
+				}
+			}
+			if depth == 0 {
+				break
+			}
+		}
+	}
+	return edits
+}
+
+// readonlyEdits replaces the readonly modifiers with whitespaces or public of the same length
+func readonlyEdits(source []byte, tokens []lexer.Token) []sourceEdit {
+	edits := []sourceEdit{}
+	for i := 1; i+1 < len(tokens); i++ {
+		t := tokens[i]
+		if t.Type != lexer.Name || strings.ToLower(tokenText(source, t)) != "readonly" {
+			continue
+		}
+		if !isReadonlyFollower(tokens[i+1].Type) {
+			continue
+		}
+		if isVisibilityToken(tokens[i-1].Type) || isVisibilityToken(tokens[i+1].Type) {
+			edits = append(edits, sourceEdit{t.Offset, t.Length, strings.Repeat(" ", t.Length)})
+		} else if tokens[i-1].Type == lexer.OpenParenthesis || tokens[i-1].Type == lexer.Comma {
+			// readonly parameters without visibility are promoted as public
+			edits = append(edits, sourceEdit{t.Offset, t.Length, "public" + strings.Repeat(" ", t.Length-len("public"))})
+		}
+	}
+	return edits
+}
+
+// isReadonlyFollower checks whether the token can follow a readonly modifier,
+// so that constants named readonly are left untouched
+func isReadonlyFollower(tokenType lexer.TokenType) bool {
+	switch tokenType {
+	case lexer.Name, lexer.Question, lexer.Backslash, lexer.VariableName, lexer.Array,
+		lexer.Callable, lexer.Ampersand, lexer.Ellipsis, lexer.Static:
+		return true
+	}
+	return isVisibilityToken(tokenType)
+}
+
+// isReadonlyText checks whether the text of a token has a readonly modifier
+// which is replaced before parsing
+func isReadonlyText(text string) bool {
+	return readonlyPattern.MatchString(text + " ")
+}
+
+func isVisibilityToken(tokenType lexer.TokenType) bool {
+	switch tokenType {
+	case lexer.Public, lexer.Protected, lexer.Private:
+		return true
+	}
+	return false
+}
+
+// namedArgumentEdits replaces the names of named arguments with whitespaces, e.g.
+// foo(limit: 10) is parsed as foo(       10)
+func namedArgumentEdits(source []byte, tokens []lexer.Token) []sourceEdit {
+	edits := []sourceEdit{}
+	for i := 1; i+1 < len(tokens); i++ {
+		if !isNamedArgument(source, tokens[i-1], tokens[i], tokens[i+1]) {
+			continue
+		}
+		start := tokens[i].Offset
+		end := tokens[i+1].Offset + tokens[i+1].Length
+		text := append([]byte{}, source[start:end]...)
+		blankRange(text, 0, len(text))
+		edits = append(edits, sourceEdit{start, end - start, string(text)})
+		i++
+	}
+	return edits
+}
+
+// isNamedArgument checks whether the token is the name of a named argument, e.g. name: 'home'
+func isNamedArgument(source []byte, prev lexer.Token, t lexer.Token, next lexer.Token) bool {
+	if next.Type != lexer.Colon || (prev.Type != lexer.OpenParenthesis && prev.Type != lexer.Comma) {
+		return false
+	}
+	return identifierPattern.Match(source[t.Offset : t.Offset+t.Length])
+}
+
+// blankAttributeGroups replaces the attribute groups with whitespaces, the lexer
+// reads #[ as a comment until the end of the line so the end of the group is
+// found by matching the brackets, and the source is lexed again if there is
+// code after the group on the same line
+func blankAttributeGroups(source []byte) ([]byte, []attributeGroup) {
+	blanked := append([]byte{}, source...)
+	groups := []attributeGroup{}
+	for rescan := true; rescan; {
+		rescan = false
+		skipTo := 0
+		l := lexer.NewLexer(blanked, nil, 0)
+		for t := l.Lex(); t.Type != lexer.EndOfFile; t = l.Lex() {
+			if t.Offset < skipTo || t.Type != lexer.Comment || !bytes.HasPrefix(blanked[t.Offset:], attributeGroupStart) {
+				continue
+			}
+			commentEnd := t.Offset + t.Length
+			group := attributeGroup{t.Offset, attributeGroupEnd(blanked, t.Offset)}
+			if group.end < 0 {
+				group.end = commentEnd
+			}
+			blankRange(blanked, group.start, group.end)
+			groups = append(groups, group)
+			skipTo = group.end
+			if group.end < commentEnd {
+				rescan = true
+				break
+			}
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].start < groups[j].start
+	})
+	return blanked, groups
+}
+
+// attributeGroupEnd returns the offset after the closing bracket of
+// the attribute group or -1 if the group is not closed
+func attributeGroupEnd(source []byte, start int) int {
+	depth := 0
+	for i := start + 1; i < len(source); i++ {
+		switch source[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case '\'', '"':
+			i = quotedStringEnd(source, i)
+		}
+	}
+	return -1
+}
+
+func quotedStringEnd(source []byte, start int) int {
+	quote := source[start]
+	for i := start + 1; i < len(source); i++ {
+		switch source[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return len(source)
+}
+
+// blankRange replaces the bytes with spaces but keeps the line breaks
+func blankRange(source []byte, start int, end int) {
+	for i := start; i < end; i++ {
+		if source[i] != '\n' && source[i] != '\r' {
+			source[i] = ' '
+		}
+	}
+}
+
+func applyEdits(source []byte, edits []sourceEdit) []byte {
+	var sb strings.Builder
+	last := 0
+	for _, edit := range edits {
+		sb.Write(source[last:edit.offset])
+		sb.WriteString(edit.text)
+		last = edit.offset + edit.length
+	}
+	sb.Write(source[last:])
+	return []byte(sb.String())
+}
+
+// originalOffset maps the offset of the edited source to the original source,
+// the offsets inside an edit are clamped to the replaced text
+func originalOffset(offset int, edits []sourceEdit) int {
+	delta := 0
+	for _, edit := range edits {
+		start := edit.offset + delta
+		if offset < start {
+			break
+		}
+		if offset < start+len(edit.text) {
+			if offset-start < edit.length {
+				return edit.offset + offset - start
+			}
+			return edit.offset + edit.length
+		}
+		delta += len(edit.text) - edit.length
+	}
+	return offset - delta
+}
+
+func restoreOffsets(node phrase.AstNode, edits []sourceEdit, visited map[*lexer.Token]struct{}) {
+	switch v := node.(type) {
+	case *lexer.Token:
+		if _, ok := visited[v]; ok {
+			return
+		}
+		visited[v] = struct{}{}
+		start := originalOffset(v.Offset, edits)
+		v.Length = originalOffset(v.Offset+v.Length, edits) - start
+		v.Offset = start
+	case *phrase.ParseError:
+		if v.Unexpected != nil {
+			restoreOffsets(v.Unexpected, edits, visited)
+		}
+		for _, child := range v.Children {
+			restoreOffsets(child, edits, visited)
+		}
+	case *phrase.Phrase:
+		for _, child := range v.Children {
+			restoreOffsets(child, edits, visited)
+		}
+	}
+}
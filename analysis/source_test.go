@@ -0,0 +1,106 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/john-nguyen09/go-phpparser/lexer"
+	"github.com/john-nguyen09/go-phpparser/phrase"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+const rewrittenSource = `<?php
+enum Status: string
+{
+    case Active = 'active';
+    case Draft;
+
+    public function label(): string
+    {
+        return ucfirst($this->value);
+    }
+}
+
+class Post
+{
+    public function __construct(
+        public readonly Status $status,
+        readonly int $id,
+    ) {}
+}
+
+$post = new Post(status: Status::Active, id: 1);
+$label = $post->status->label();`
+
+func TestParseSourceRestoresOffsets(t *testing.T) {
+	source := []byte(rewrittenSource)
+	originalTokens := map[int]lexer.Token{}
+	for _, token := range significantTokens(source) {
+		originalTokens[token.Offset] = token
+	}
+	rootNode, _ := parseSource(source)
+	checked := 0
+	var check func(node phrase.AstNode)
+	check = func(node phrase.AstNode) {
+		switch v := node.(type) {
+		case *lexer.Token:
+			switch v.Type {
+			case lexer.Name, lexer.VariableName, lexer.StringLiteral, lexer.IntegerLiteral:
+			default:
+				return
+			}
+			if v.Length == 0 {
+				// Tokens which are added by the rewrite have no text
+				return
+			}
+			original, ok := originalTokens[v.Offset]
+			if assert.True(t, ok, "no token at %d", v.Offset) {
+				assert.Equal(t, original.Length, v.Length, string(source[original.Offset:original.Offset+original.Length]))
+			}
+			checked++
+		case *phrase.Phrase:
+			for _, child := range v.Children {
+				check(child)
+			}
+		}
+	}
+	check(rootNode)
+	assert.Greater(t, checked, 20)
+}
+
+func TestPositionsAfterRewrittenSource(t *testing.T) {
+	document := NewDocument("test1", []byte(rewrittenSource))
+	document.Load()
+	assert.Empty(t, GetParserDiagnostics(document))
+	locations := map[string]protocol.Range{}
+	tra := newTraverser()
+	tra.traverseDocument(document, func(tra *traverser, s Symbol, _ []Symbol) {
+		switch v := s.(type) {
+		case *Method:
+			locations[v.Name] = v.refLocation.Range
+		case *Property:
+			locations[v.Name] = v.GetLocation().Range
+		}
+	})
+	assert.Equal(t, protocol.Range{
+		Start: protocol.Position{Line: 6, Character: 20},
+		End:   protocol.Position{Line: 6, Character: 25},
+	}, locations["label"])
+	assert.Equal(t, protocol.Range{
+		Start: protocol.Position{Line: 15, Character: 8},
+		End:   protocol.Position{Line: 15, Character: 38},
+	}, locations["$status"])
+
+	access, ok := document.HasTypesAtPos(protocol.Position{Line: 21, Character: 27}).(*MethodAccess)
+	if assert.True(t, ok) {
+		assert.Equal(t, "label", access.Name)
+		assert.Equal(t, protocol.Range{
+			Start: protocol.Position{Line: 21, Character: 24},
+			End:   protocol.Position{Line: 21, Character: 29},
+		}, access.Location.Range)
+	}
+	variable, ok := document.HasTypesAtPos(protocol.Position{Line: 20, Character: 2}).(*Variable)
+	if assert.True(t, ok) {
+		assert.Equal(t, "$post", variable.Name)
+	}
+}
@@ -0,0 +1,116 @@
+package analysis
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/john-nguyen09/phpintel/analysis/storage"
+)
+
+var statsCollections = []string{
+	documentCollection,
+	classCollection,
+	interfaceCollection,
+	traitCollection,
+	enumCollection,
+	functionCollection,
+	constCollection,
+	defineCollection,
+	methodCollection,
+	classConstCollection,
+	propertyCollection,
+	globalVariableCollection,
+	referenceIndexCollection,
+	documentReferenceIndex,
+}
+
+// StoreStats are the numbers of the entries of the collections of a store
+// and the sizes of its databases on the disk in bytes
+type StoreStats struct {
+	URI             string           `json:"uri"`
+	StoragePath     string           `json:"storagePath"`
+	OpenDocuments   int              `json:"openDocuments"`
+	Collections     map[string]int   `json:"collections"`
+	CompletionCount int              `json:"completionEntries"`
+	DiskSizes       map[string]int64 `json:"diskSizes"`
+}
+
+// Stats counts the entries of the store, every entry is read so this is
+// for debugging only
+func (s *Store) Stats() StoreStats {
+	stats := StoreStats{
+		URI:           s.uri,
+		StoragePath:   s.storePath,
+		OpenDocuments: s.documents.Count(),
+		Collections:   map[string]int{},
+		DiskSizes:     map[string]int64{},
+	}
+	for _, collection := range statsCollections {
+		count := 0
+		s.db.PrefixStream(newEntry(collection, "").getKeyBytes(), func(it storage.Iterator) {
+			count++
+		})
+		stats.Collections[collection] = count
+	}
+	s.comDB.PrefixStream([]byte{}, func(it storage.Iterator) {
+		stats.CompletionCount++
+	})
+	stats.DiskSizes["symbols"] = dirSize(s.storePath, false)
+	stats.DiskSizes["completion"] = dirSize(path.Join(s.storePath, "completion"), true)
+	stats.DiskSizes["documents"] = dirSize(path.Join(s.storePath, "pogreb"), true)
+	return stats
+}
+
+// dirSize sums the sizes of the files in the directory, the sub-directories
+// are only included if recursive is true
+func dirSize(dir string, recursive bool) int64 {
+	var size int64
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if p != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		size += info.Size()
+		return nil
+	})
+	return size
+}
+
+// StorageStats are the statistics of the databases of a store, unlike
+// StoreStats they are cheap to collect
+type StorageStats struct {
+	URI               string `json:"uri"`
+	OpenDocuments     int    `json:"openDocuments"`
+	SymbolsLevelDB    string `json:"symbolsLevelDB"`
+	CompletionLevelDB string `json:"completionLevelDB"`
+	DocumentCount     uint64 `json:"documentCount"`
+	DocumentFileSize  int64  `json:"documentFileSize"`
+	DocumentPuts      int64  `json:"documentPuts"`
+	DocumentGets      int64  `json:"documentGets"`
+	DocumentDels      int64  `json:"documentDels"`
+	HashCollisions    int64  `json:"hashCollisions"`
+}
+
+// StorageStats reports the statistics of leveldb and pogreb
+func (s *Store) StorageStats() StorageStats {
+	stats := StorageStats{
+		URI:               s.uri,
+		OpenDocuments:     s.documents.Count(),
+		SymbolsLevelDB:    storage.Stats(s.db),
+		CompletionLevelDB: storage.Stats(s.comDB),
+		DocumentCount:     s.greb.Count(),
+	}
+	stats.DocumentFileSize, _ = s.greb.FileSize()
+	metrics := s.greb.Metrics()
+	stats.DocumentPuts = metrics.Puts.Value()
+	stats.DocumentGets = metrics.Gets.Value()
+	stats.DocumentDels = metrics.Dels.Value()
+	stats.HashCollisions = metrics.HashCollisions.Value()
+	return stats
+}
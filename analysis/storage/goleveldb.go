@@ -30,6 +30,16 @@ func (s *goLevelDB) Close() {
 	s.db.Close()
 }
 
+// Stats returns the leveldb.stats property, the levels of the tables and
+// the compactions
+func (s *goLevelDB) Stats() string {
+	stats, err := s.db.GetProperty("leveldb.stats")
+	if err != nil {
+		return ""
+	}
+	return stats
+}
+
 func (s *goLevelDB) Delete(key []byte) error {
 	return s.db.Delete(key, nil)
 }
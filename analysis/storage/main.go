@@ -26,6 +26,21 @@ type DB interface {
 	WriteBatch(func(Batch) error) error
 }
 
+// statser is implemented by the databases which can report their internal
+// statistics
+type statser interface {
+	Stats() string
+}
+
+// Stats returns the internal statistics of the database, it is empty if
+// the database does not report any
+func Stats(db DB) string {
+	if s, ok := db.(statser); ok {
+		return s.Stats()
+	}
+	return ""
+}
+
 // Combined is a combination of storage modes
 type Combined struct {
 	dbs     []DB
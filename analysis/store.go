@@ -26,6 +26,7 @@ const (
 	classCollection              string = "cla"
 	interfaceCollection          string = "int"
 	traitCollection              string = "tra"
+	enumCollection               string = "enu"
 	functionCollection           string = "fun"
 	constCollection              string = "con"
 	defineCollection             string = "def"
@@ -44,6 +45,7 @@ const (
 	classCompletionIndex      string = "claCom"
 	interfaceCompletionIndex  string = "intCom"
 	traitCompletionIndex      string = "traCom"
+	enumCompletionIndex       string = "enuCom"
 	methodCompletionIndex     string = "metCom"
 	propertyCompletionIndex   string = "proCom"
 	classConstCompletionIndex string = "claConCom"
@@ -54,6 +56,12 @@ const (
 )
 
 var /* const */ versionKey = []byte("Version")
+var /* const */ encodingVersionKey = []byte("EncodingVersion")
+
+// encodingVersion is the version of how the symbols are encoded on the disk,
+// it must be raised whenever the encoding changes so that the stores which
+// were written with the previous encoding are cleared
+const encodingVersion = 11
 
 // KeySep is the separator when constructing key
 const KeySep string = "\x00"
@@ -110,6 +118,7 @@ func (s documentNamespace) serialise(e *storage.Encoder) {
 // for querying symbols
 type Store struct {
 	uri       protocol.DocumentURI
+	storePath string
 	FS        protocol.FS
 	db        storage.DB
 	comDB     storage.DB
@@ -227,6 +236,7 @@ func NewStore(fs protocol.FS, uri protocol.DocumentURI, storePath string) (*Stor
 	}
 	store := &Store{
 		uri:       uri,
+		storePath: storePath,
 		FS:        fs,
 		db:        db,
 		comDB:     comDB,
@@ -247,6 +257,7 @@ func (s *Store) Close() {
 		return
 	}
 	s.db.Close()
+	s.comDB.Close()
 	s.greb.Close()
 	s.isClosed = true
 }
@@ -271,22 +282,29 @@ func (s *Store) Clear() {
 	s.db.Clear()
 }
 
-// Migrate checks for defined version if it is less than
-// clears the store
+// Migrate clears the store if it was written by a version older than
+// v0.0.13 or with a different encoding of the symbols
 func (s *Store) Migrate(newVersion string) {
-	storeVersion := s.GetStoreVersion()
-	sv, _ := semver.NewVersion(storeVersion)
-
-	if sv == nil {
+	if !s.isOutdated() {
 		return
 	}
+	log.Println("Clearing database for upgrade.")
+	s.Clear()
+	s.PutVersion(newVersion)
+	s.db.Put(encodingVersionKey, []byte(strconv.Itoa(encodingVersion)))
+}
 
-	targetV, _ := semver.NewVersion("v0.0.13")
-	if sv.LessThan(targetV) {
-		log.Println("Clearing database for upgrade.")
-		s.Clear()
-		s.PutVersion(newVersion)
+func (s *Store) isOutdated() bool {
+	v, err := s.db.Get(encodingVersionKey)
+	if err != nil || string(v) != strconv.Itoa(encodingVersion) {
+		return true
+	}
+	sv, _ := semver.NewVersion(s.GetStoreVersion())
+	if sv == nil {
+		return false
 	}
+	targetV, _ := semver.NewVersion("v0.0.13")
+	return sv.LessThan(targetV)
 }
 
 // LoadStubs loads the defined stubs, compare their hash and index them
@@ -809,6 +827,47 @@ func (s *Store) SearchTraits(keyword string, options SearchOptions) ([]*Trait, S
 	return traits, result
 }
 
+// GetEnums searches for all the enums with the given name from the disk storage
+func (s *Store) GetEnums(name string) []*Enum {
+	entry := newEntry(enumCollection, name+KeySep)
+	enums := []*Enum{}
+	s.db.PrefixStream(entry.getKeyBytes(), func(it storage.Iterator) {
+		d := storage.NewDecoder(it.Value())
+		enums = append(enums, ReadEnum(d))
+	})
+	return enums
+}
+
+// SearchEnums uses completion index to search enums matching the given keyword.
+// `keyword` can contain scope
+func (s *Store) SearchEnums(keyword string, options SearchOptions) ([]*Enum, SearchResult) {
+	scope, keyword := GetScopeAndNameFromString(keyword)
+	enums := []*Enum{}
+	options.predicates = append(options.predicates, namespacePredicate(scope))
+	query := searchQuery{
+		collection: enumCompletionIndex,
+		keyword:    keyword,
+		onData: func(completionValue CompletionValue) onDataResult {
+			entry := newEntry(enumCollection, string(completionValue))
+			value, err := s.db.Get(entry.getKeyBytes())
+			if err != nil {
+				return onDataResult{false}
+			}
+			d := storage.NewDecoder(value)
+			enum := ReadEnum(d)
+			if isSymbolValid(enum, options) {
+				enums = append(enums, enum)
+				if options.IsLimitReached() {
+					return onDataResult{true}
+				}
+			}
+			return onDataResult{false}
+		},
+	}
+	result := searchCompletions(s.comDB, query)
+	return enums, result
+}
+
 // GetFunctions searches all functions with the given name from the disk storage
 func (s *Store) GetFunctions(name string) []*Function {
 	entry := newEntry(functionCollection, name+KeySep)
@@ -1136,7 +1195,7 @@ func SymToRefs(document *Document, sym HasTypes) []string {
 			sb.WriteString("()")
 			refs = append(refs, sb.String())
 		}
-	case *ClassTypeDesignator, *TypeDeclaration, *ClassAccess, *TraitAccess, *InterfaceAccess:
+	case *ClassTypeDesignator, *TypeDeclaration, *ClassAccess, *TraitAccess, *InterfaceAccess, *Attribute:
 		if c, ok := sym.(*ClassAccess); ok && IsNameRelative(c.Name) {
 			break
 		}
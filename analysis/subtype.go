@@ -0,0 +1,141 @@
+package analysis
+
+import (
+	"strings"
+)
+
+// IsSubclassOf checks whether the class, interface or enum is the super type
+// or extends or implements it
+func (q *Query) IsSubclassOf(fqn string, superFQN string) bool {
+	return q.isSubclassOf(fqn, superFQN, map[string]struct{}{})
+}
+
+func (q *Query) isSubclassOf(fqn string, superFQN string, visited map[string]struct{}) bool {
+	if strings.EqualFold(fqn, superFQN) {
+		return true
+	}
+	key := strings.ToLower(fqn)
+	if _, ok := visited[key]; ok {
+		return false
+	}
+	visited[key] = struct{}{}
+	for _, class := range q.GetClasses(fqn) {
+		if !class.Extends.IsEmpty() && q.isSubclassOf(class.Extends.GetFQN(), superFQN, visited) {
+			return true
+		}
+		for _, intf := range class.Interfaces {
+			if q.isSubclassOf(intf.GetFQN(), superFQN, visited) {
+				return true
+			}
+		}
+	}
+	for _, intf := range q.GetInterfaces(fqn) {
+		for _, extend := range intf.Extends {
+			if q.isSubclassOf(extend.GetFQN(), superFQN, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isClassLike checks whether the FQN is a known class, interface or enum
+func (q *Query) isClassLike(fqn string) bool {
+	return len(q.GetClasses(fqn)) > 0 || len(q.GetInterfaces(fqn)) > 0
+}
+
+// nativeTypeName returns the name of the native type or an empty string if the type is a class
+func nativeTypeName(t TypeString) string {
+	if _, ok := t.Dearray(); ok {
+		return "array"
+	}
+	name := strings.ToLower(strings.TrimPrefix(t.GetOriginal(), "\\"))
+	switch name {
+	case "boolean":
+		return "bool"
+	case "integer":
+		return "int"
+	case "double", "real":
+		return "float"
+	case "callback":
+		return "callable"
+	case "mixed", "null", "bool", "true", "false", "int", "float", "string", "array", "object",
+		"callable", "iterable", "void", "never", "static", "self", "parent", "$this", "resource",
+		"scalar", "numeric", "number", "binary", "closure":
+		return name
+	}
+	if strings.Contains(name, "<") || strings.Contains(name, "(") || strings.Contains(name, "{") {
+		// Generic or shaped types are not checked
+		return "mixed"
+	}
+	return ""
+}
+
+// IsAssignable checks whether a value of the type can be used where the declared
+// types are required, the value can be coerced to other scalar types if not strict.
+// The types which are not known are assumed to be assignable.
+func (q *Query) IsAssignable(value TypeString, declared TypeComposite, isStrict bool) bool {
+	if declared.IsEmpty() {
+		return true
+	}
+	for _, declaredType := range declared.Resolve() {
+		if q.acceptsType(declaredType, value, isStrict) {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *Query) acceptsType(declared TypeString, value TypeString, isStrict bool) bool {
+	declaredName := nativeTypeName(declared)
+	valueName := nativeTypeName(value)
+	switch declaredName {
+	case "", "null", "bool", "true", "false", "int", "float", "string", "array", "object", "callable", "iterable":
+	default:
+		return true
+	}
+	switch valueName {
+	case "":
+		if !q.isClassLike(value.GetFQN()) {
+			return true
+		}
+		switch declaredName {
+		case "":
+			return !q.isClassLike(declared.GetFQN()) || q.IsSubclassOf(value.GetFQN(), declared.GetFQN())
+		case "object", "callable":
+			return true
+		case "iterable":
+			return q.IsSubclassOf(value.GetFQN(), "\\Traversable")
+		case "string":
+			// Objects with __toString can be coerced to string
+			return !isStrict
+		}
+		return false
+	case "null":
+		return declaredName == "null"
+	case "array":
+		switch declaredName {
+		case "array", "iterable", "callable":
+			return true
+		case "":
+			return !q.isClassLike(declared.GetFQN())
+		}
+		return false
+	case "bool", "true", "false", "int", "float", "string":
+		switch declaredName {
+		case "":
+			return !q.isClassLike(declared.GetFQN())
+		case "null", "array", "object", "iterable":
+			return false
+		case "callable":
+			return valueName == "string"
+		}
+		if declaredName == valueName || !isStrict ||
+			declaredName == "float" && valueName == "int" ||
+			declaredName == "bool" && (valueName == "true" || valueName == "false") {
+			return true
+		}
+		return (declaredName == "true" || declaredName == "false") && valueName == "bool"
+	}
+	return true
+}
@@ -0,0 +1,254 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/john-nguyen09/go-phpparser/lexer"
+	"github.com/john-nguyen09/go-phpparser/phrase"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// CodeUnknownSuppressionCode is the code of the diagnostic for the words of
+// the suppression comments which look like codes but are not rules
+const CodeUnknownSuppressionCode = "unknown-suppression-code"
+
+// IgnoreNextLineTag suppresses the diagnostics of the next line, it is
+// followed by the codes to suppress or nothing to suppress every diagnostic,
+// e.g. // @phpintel-ignore-next-line undefined-variable, unused-variable.
+// @phpintel-ignore in a doc comment suppresses the diagnostics of the
+// declaration and @phpintel-ignore-file the ones of the whole file.
+const IgnoreNextLineTag = "@phpintel-ignore-next-line"
+
+var ignoreTagPattern = regexp.MustCompile(`@phpintel-ignore(-next-line|-file)?([ \t][^\r\n]*)?(?:\*/|\r?\n|$)`)
+
+// codePattern matches the words which are meant to be codes, e.g. a
+// misspelled code, rather than the reason of the suppression
+var codePattern = regexp.MustCompile(`^[a-z]+(-[a-z]+)+$`)
+
+// suppression hides the diagnostics with the codes between the lines,
+// every code is suppressed if the tag is not followed by anything
+type suppression struct {
+	startLine int
+	endLine   int
+	codes     map[string]struct{}
+	isAll     bool
+	// unknown are the diagnostics of the words which are not the codes of rules
+	unknown []protocol.Diagnostic
+}
+
+func (s suppression) matches(diagnostic protocol.Diagnostic) bool {
+	line := diagnostic.Range.Start.Line
+	if line < s.startLine || line > s.endLine {
+		return false
+	}
+	if s.isAll {
+		return true
+	}
+	_, ok := s.codes[DiagnosticCode(diagnostic)]
+	return ok
+}
+
+// SuppressDiagnostics removes the diagnostics which are suppressed by
+// the comments of the document
+func SuppressDiagnostics(document *Document, diagnostics []protocol.Diagnostic) []protocol.Diagnostic {
+	suppressions := documentSuppressions(document)
+	if len(suppressions) == 0 {
+		return diagnostics
+	}
+	results := []protocol.Diagnostic{}
+	for _, diagnostic := range diagnostics {
+		isSuppressed := false
+		for _, s := range suppressions {
+			if s.matches(diagnostic) {
+				isSuppressed = true
+				break
+			}
+		}
+		if !isSuppressed {
+			results = append(results, diagnostic)
+		}
+	}
+	return results
+}
+
+// SuppressionDiagnostics returns the diagnostics for the unknown codes of
+// the suppression comments, the comments do not suppress anything for them
+func SuppressionDiagnostics(document *Document) []protocol.Diagnostic {
+	diagnostics := []protocol.Diagnostic{}
+	for _, s := range documentSuppressions(document) {
+		diagnostics = append(diagnostics, s.unknown...)
+	}
+	return diagnostics
+}
+
+func documentSuppressions(document *Document) []suppression {
+	suppressions := []suppression{}
+	traverser := util.NewTraverser(document.GetRootNode())
+	traverser.Traverse(func(node phrase.AstNode, spine []*phrase.Phrase) util.VisitorContext {
+		isDocComment := false
+		switch v := node.(type) {
+		case *lexer.Token:
+			if v.Type != lexer.Comment {
+				return util.VisitorContext{ShouldAscend: false}
+			}
+		case *phrase.Phrase:
+			if v.Type != phrase.DocumentComment {
+				return util.VisitorContext{ShouldAscend: true}
+			}
+			isDocComment = true
+		}
+		r := document.NodeRange(node)
+		text := document.GetNodeText(node)
+		for _, match := range ignoreTagPattern.FindAllStringSubmatchIndex(text, -1) {
+			s := suppression{isAll: true}
+			if match[4] >= 0 {
+				codes := parseIgnoredCodes(text[match[4]:match[5]])
+				s.codes, s.isAll = codes.codes, codes.isEmpty
+				offset := document.OffsetAtPosition(r.Start) + match[4]
+				for _, word := range codes.unknown {
+					r := protocol.Range{
+						Start: document.positionAt(offset + word[0]),
+						End:   document.positionAt(offset + word[1]),
+					}
+					s.unknown = append(s.unknown, newDiagnostic(CodeUnknownSuppressionCode, r,
+						"Unknown diagnostic code "+text[match[4]+word[0]:match[4]+word[1]]+"."))
+				}
+			}
+			tag := ""
+			if match[2] >= 0 {
+				tag = text[match[2]:match[3]]
+			}
+			switch tag {
+			case "-next-line":
+				s.startLine, s.endLine = r.End.Line+1, r.End.Line+1
+			case "-file":
+				s.startLine, s.endLine = 0, len(document.lineOffsets)
+			default:
+				if !isDocComment || len(spine) == 0 {
+					continue
+				}
+				documented := nextPhraseSibling(spine[len(spine)-1], node)
+				if documented == nil {
+					continue
+				}
+				s.startLine, s.endLine = r.Start.Line, document.NodeRange(documented).End.Line
+			}
+			suppressions = append(suppressions, s)
+		}
+		return util.VisitorContext{ShouldAscend: false}
+	})
+	return suppressions
+}
+
+// ignoredCodes are the codes after a suppression tag
+type ignoredCodes struct {
+	codes map[string]struct{}
+	// unknown are the offsets of the words which look like codes but
+	// are not the codes of rules, e.g. misspelled codes
+	unknown [][2]int
+	// end is the offset after the last code
+	end int
+	// isEmpty is true if the tag is not followed by anything
+	isEmpty bool
+}
+
+// parseIgnoredCodes returns the codes at the start of the text, the codes end
+// at the first word which does not look like a code, e.g. the reason of the
+// suppression
+func parseIgnoredCodes(text string) ignoredCodes {
+	if end := strings.Index(text, "*/"); end >= 0 {
+		text = text[:end]
+	}
+	isSeparator := func(c byte) bool {
+		return c == ',' || c == ' ' || c == '\t' || c == '\r'
+	}
+	result := ignoredCodes{
+		codes:   map[string]struct{}{},
+		isEmpty: strings.TrimSpace(text) == "",
+	}
+	for start := 0; start < len(text); {
+		if isSeparator(text[start]) {
+			start++
+			continue
+		}
+		next := start
+		for next < len(text) && !isSeparator(text[next]) {
+			next++
+		}
+		word := text[start:next]
+		if _, ok := rules[word]; ok {
+			result.codes[word] = struct{}{}
+		} else if codePattern.MatchString(word) {
+			result.unknown = append(result.unknown, [2]int{start, next})
+		} else {
+			break
+		}
+		result.end = next
+		start = next
+	}
+	return result
+}
+
+// nextPhraseSibling returns the phrase after the node in the parent,
+// this is the declaration of a doc comment
+func nextPhraseSibling(parent *phrase.Phrase, node phrase.AstNode) *phrase.Phrase {
+	isAfter := false
+	for _, child := range parent.Children {
+		if child == node {
+			isAfter = true
+			continue
+		}
+		if !isAfter {
+			continue
+		}
+		if p, ok := child.(*phrase.Phrase); ok {
+			return p
+		}
+		if t, ok := child.(*lexer.Token); ok && t.Type != lexer.Whitespace {
+			return nil
+		}
+	}
+	return nil
+}
+
+// SuppressionEdit returns the edit which suppresses the diagnostic with a
+// comment above its line, the code is added after the codes of the comment
+// if there is one, so the reason after the codes is kept
+func SuppressionEdit(document *Document, diagnostic protocol.Diagnostic) (protocol.TextEdit, bool) {
+	code := DiagnosticCode(diagnostic)
+	if _, ok := rules[code]; !ok {
+		return protocol.TextEdit{}, false
+	}
+	line := diagnostic.Range.Start.Line
+	if line > 0 {
+		previous := strings.TrimRight(document.lineText(line-1), "\r\n")
+		prefix := "// " + IgnoreNextLineTag
+		comment := strings.TrimSpace(previous)
+		if strings.HasPrefix(comment, prefix+" ") {
+			offset := strings.Index(previous, prefix) + len(prefix)
+			codes := parseIgnoredCodes(previous[offset:])
+			if _, ok := codes.codes[code]; ok || codes.isEmpty {
+				return protocol.TextEdit{}, false
+			}
+			end := protocol.Position{Line: line - 1, Character: offset + codes.end}
+			newText := ", " + code
+			if codes.end == 0 {
+				// The comment only has the reason
+				newText = " " + code
+			}
+			return protocol.TextEdit{
+				Range:   protocol.Range{Start: end, End: end},
+				NewText: newText,
+			}, true
+		}
+	}
+	text := document.lineText(line)
+	indentation := text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+	start := protocol.Position{Line: line, Character: 0}
+	return protocol.TextEdit{
+		Range:   protocol.Range{Start: start, End: start},
+		NewText: indentation + "// " + IgnoreNextLineTag + " " + code + document.detectedEOL,
+	}, true
+}
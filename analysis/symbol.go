@@ -142,6 +142,35 @@ func (t *traverser) traverseBlock(s Symbol, spine []Symbol, fn func(*traverser,
 	}
 }
 
+// SymbolFQN returns the FQN of the declaration as it is written in PHP with ()
+// after the functions and methods, e.g. \App\Foo::bar(), or empty if the
+// symbol is not a declaration
+func SymbolFQN(s Symbol) string {
+	switch v := s.(type) {
+	case *Class:
+		return v.Name.GetFQN()
+	case *Interface:
+		return v.Name.GetFQN()
+	case *Trait:
+		return v.Name.GetFQN()
+	case *Enum:
+		return v.Name.GetFQN()
+	case *Function:
+		return v.Name.GetFQN() + "()"
+	case *Const:
+		return v.Name.GetFQN()
+	case *Define:
+		return v.Name.GetFQN()
+	case *Method:
+		return v.Scope.GetFQN() + "::" + v.Name + "()"
+	case *Property:
+		return v.Scope.GetFQN() + "::" + v.Name
+	case *ClassConst:
+		return v.Scope.GetFQN() + "::" + v.Name
+	}
+	return ""
+}
+
 func TraverseDocument(document *Document, preorder func(Symbol), postorder func(Symbol)) {
 	for _, child := range document.Children {
 		TraverseSymbol(child, preorder, postorder)
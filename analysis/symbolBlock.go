@@ -84,24 +84,23 @@ var symbolConstructorMap map[phrase.PhraseType]symbolConstructor
 
 func init() {
 	symbolConstructorMap = map[phrase.PhraseType]symbolConstructor{
-		phrase.InterfaceDeclaration:                newInterface,
-		phrase.ClassDeclaration:                    newClass,
-		phrase.ClassConstDeclaration:               newClassConstDeclaration,
-		phrase.PropertyDeclaration:                 newPropertyDeclaration,
-		phrase.MethodDeclaration:                   newMethod,
-		phrase.TraitUseClause:                      processTraitUseClause,
-		phrase.FunctionDeclaration:                 newFunction,
-		phrase.ConstDeclaration:                    newConstDeclaration,
-		phrase.ConstElement:                        newConst,
-		phrase.ArgumentExpressionList:              newArgumentList,
-		phrase.TraitDeclaration:                    newTrait,
-		phrase.FunctionCallExpression:              tryToNewDefine,
-		phrase.GlobalDeclaration:                   newGlobalDeclaration,
-		phrase.NamespaceUseDeclaration:             processNamespaceUseDeclaration,
-		phrase.AnonymousFunctionCreationExpression: newAnonymousFunction,
-		phrase.AnonymousClassDeclaration:           newAnonymousClass,
-		phrase.DocumentComment:                     newPhpDocFromNode,
-		phrase.CatchNameList:                       processCatchNameList,
+		phrase.InterfaceDeclaration:      newInterface,
+		phrase.ClassDeclaration:          newClassOrEnum,
+		phrase.ClassConstDeclaration:     newClassConstDeclaration,
+		phrase.PropertyDeclaration:       newPropertyDeclaration,
+		phrase.MethodDeclaration:         newMethod,
+		phrase.TraitUseClause:            processTraitUseClause,
+		phrase.FunctionDeclaration:       newFunction,
+		phrase.ConstDeclaration:          newConstDeclaration,
+		phrase.ConstElement:              newConst,
+		phrase.ArgumentExpressionList:    newArgumentList,
+		phrase.TraitDeclaration:          newTrait,
+		phrase.FunctionCallExpression:    tryToNewDefine,
+		phrase.GlobalDeclaration:         newGlobalDeclaration,
+		phrase.NamespaceUseDeclaration:   processNamespaceUseDeclaration,
+		phrase.AnonymousClassDeclaration: newAnonymousClass,
+		phrase.DocumentComment:           newPhpDocFromNode,
+		phrase.CatchNameList:             processCatchNameList,
 	}
 }
 
@@ -42,6 +42,52 @@ type TypeString struct {
 	fqn        string
 	original   string
 	arrayLevel int
+	signature  *CallableSignature
+}
+
+// CallableSignature contains the parameters and return types of
+// callable(T): R or Closure(T): R types
+type CallableSignature struct {
+	Params  []*Parameter
+	Returns TypeComposite
+}
+
+// ToString returns the string representation of the signature
+func (c *CallableSignature) ToString() string {
+	params := []string{}
+	for _, param := range c.Params {
+		label := param.Type.ToString()
+		if param.Name != "" {
+			label += " " + param.Name
+		}
+		if param.hasValue {
+			label += " = " + param.Value
+		}
+		params = append(params, strings.TrimSpace(label))
+	}
+	str := "(" + strings.Join(params, ", ") + ")"
+	if !c.Returns.IsEmpty() {
+		str += ": " + c.Returns.ToString()
+	}
+	return str
+}
+
+func (c *CallableSignature) Write(e *storage.Encoder) {
+	e.WriteInt(len(c.Params))
+	for _, param := range c.Params {
+		param.Write(e)
+	}
+	c.Returns.Write(e)
+}
+
+func ReadCallableSignature(d *storage.Decoder) *CallableSignature {
+	signature := &CallableSignature{}
+	count := d.ReadInt()
+	for i := 0; i < count; i++ {
+		signature.Params = append(signature.Params, ReadParameter(d))
+	}
+	signature.Returns = ReadTypeComposite(d)
+	return signature
 }
 
 func NewTypeString(typeString string) TypeString {
@@ -98,13 +144,29 @@ func (t TypeString) GetFQN() string {
 	return t.fqn
 }
 
+// GetSignature returns the callable signature if the type has one,
+// e.g. Closure(int): string
+func (t TypeString) GetSignature() *CallableSignature {
+	return t.signature
+}
+
+// IsCallable checks whether the type is callable or Closure
+func (t TypeString) IsCallable() bool {
+	fqn := strings.ToLower(t.GetFQN())
+	return fqn == "callable" || fqn == "\\closure" || strings.ToLower(t.original) == "closure"
+}
+
 // ToString returns the string representation of the type
 func (t TypeString) ToString() string {
 	arraySuffices := []string{}
 	for i := 0; i < t.arrayLevel; i++ {
 		arraySuffices = append(arraySuffices, "[]")
 	}
-	return t.GetFQN() + strings.Join(arraySuffices, "")
+	str := t.GetFQN()
+	if t.signature != nil {
+		str += t.signature.ToString()
+	}
+	return str + strings.Join(arraySuffices, "")
 }
 
 func (t TypeString) GetNamespace() string {
@@ -169,14 +231,24 @@ func (t TypeString) Write(e *storage.Encoder) {
 	e.WriteString(t.original)
 	e.WriteString(t.fqn)
 	e.WriteInt(t.arrayLevel)
+	if t.signature == nil {
+		e.WriteBool(false)
+	} else {
+		e.WriteBool(true)
+		t.signature.Write(e)
+	}
 }
 
 func ReadTypeString(d *storage.Decoder) TypeString {
-	return TypeString{
+	typeString := TypeString{
 		original:   d.ReadString(),
 		fqn:        d.ReadString(),
 		arrayLevel: d.ReadInt(),
 	}
+	if d.ReadBool() {
+		typeString.signature = ReadCallableSignature(d)
+	}
+	return typeString
 }
 
 // TypeComposite contains multiple type strings
@@ -191,13 +263,17 @@ func newTypeComposite() TypeComposite {
 }
 
 func typesFromPhpDoc(document *Document, text string) TypeComposite {
-	parts := strings.Split(text, "|")
+	parts := splitOutsideBrackets(text, '|')
 	types := newTypeComposite()
 	for _, part := range parts {
 		part := strings.TrimSpace(part)
 		if part == "" {
 			continue
 		}
+		signature := ""
+		if index := strings.IndexByte(part, '('); index > 0 {
+			part, signature = strings.TrimSpace(part[:index]), part[index:]
+		}
 		if part == "self" {
 			currentClass := document.getLastClass()
 			switch v := currentClass.(type) {
@@ -207,16 +283,140 @@ func typesFromPhpDoc(document *Document, text string) TypeComposite {
 				types.add(v.Name)
 			case *Trait:
 				types.add(v.Name)
+			case *Enum:
+				types.add(v.Name)
 			}
 			continue
 		}
 		typeString := NewTypeString(part)
 		typeString.SetFQN(document.currImportTable().GetClassReferenceFQN(typeString))
+		if signature != "" && typeString.IsCallable() {
+			typeString.signature = callableSignatureFromPhpDoc(document, signature)
+		}
 		types.add(typeString)
 	}
 	return types
 }
 
+// arrayLikeTypes are the types of which generics describe the values
+var /* const */ arrayLikeTypes = map[string]bool{
+	"array":           true,
+	"list":            true,
+	"iterable":        true,
+	"non-empty-array": true,
+	"non-empty-list":  true,
+}
+
+// withoutGenerics converts array<K, V> and list<V> to V[], array{...} shapes
+// to array and Foo<T> to Foo because generics are not supported
+func withoutGenerics(text string) string {
+	index := strings.IndexAny(text, "<{")
+	if index <= 0 {
+		return text
+	}
+	base, suffix := text[:index], ""
+	closeIndex := strings.LastIndexAny(text, ">}")
+	if closeIndex > index {
+		suffix = text[closeIndex+1:]
+	} else {
+		closeIndex = len(text)
+	}
+	if !arrayLikeTypes[strings.ToLower(base)] {
+		return base + suffix
+	}
+	if text[index] == '{' {
+		return "array" + suffix
+	}
+	args := splitOutsideBrackets(text[index+1:closeIndex], ',')
+	value := strings.TrimSpace(args[len(args)-1])
+	if value == "" || strings.ContainsAny(value, "|&") {
+		return "array" + suffix
+	}
+	return withoutGenerics(value) + "[]" + suffix
+}
+
+// withoutGenericsInUnion removes the generics of every type of the union
+func withoutGenericsInUnion(text string) string {
+	parts := splitOutsideBrackets(text, '|')
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		signature := ""
+		if index := strings.IndexByte(part, '('); index > 0 {
+			part, signature = strings.TrimSpace(part[:index]), part[index:]
+		}
+		parts[i] = withoutGenerics(part) + signature
+	}
+	return strings.Join(parts, "|")
+}
+
+// callableSignatureFromPhpDoc parses the (T, U $name): R part of
+// a callable or Closure type
+func callableSignatureFromPhpDoc(document *Document, text string) *CallableSignature {
+	signature := &CallableSignature{}
+	depth := 0
+	closeIndex := -1
+	for i, r := range text {
+		if r == '(' {
+			depth++
+		} else if r == ')' {
+			depth--
+			if depth == 0 {
+				closeIndex = i
+				break
+			}
+		}
+	}
+	if closeIndex == -1 {
+		return signature
+	}
+	for _, paramText := range splitOutsideBrackets(text[1:closeIndex], ',') {
+		paramText = strings.TrimSpace(paramText)
+		if paramText == "" {
+			continue
+		}
+		param := &Parameter{}
+		if index := strings.IndexByte(paramText, '='); index >= 0 {
+			param.hasValue = true
+			param.Value = strings.TrimSpace(paramText[index+1:])
+			paramText = strings.TrimSpace(paramText[:index])
+		}
+		if index := strings.LastIndexByte(paramText, '$'); index >= 0 {
+			param.Name = paramText[index:]
+			paramText = paramText[:index]
+		}
+		paramText = strings.TrimRight(paramText, " \t&.")
+		param.Type = typesFromPhpDoc(document, paramText)
+		signature.Params = append(signature.Params, param)
+	}
+	rest := strings.TrimSpace(text[closeIndex+1:])
+	if strings.HasPrefix(rest, ":") {
+		signature.Returns = typesFromPhpDoc(document, strings.TrimSpace(rest[1:]))
+	}
+	return signature
+}
+
+// splitOutsideBrackets splits the text by the separator but ignores
+// the separators inside (), <> and {}
+func splitOutsideBrackets(text string, sep rune) []string {
+	parts := []string{}
+	depth := 0
+	start := 0
+	for i, r := range text {
+		switch r {
+		case '(', '<', '{':
+			depth++
+		case ')', '>', '}':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, text[start:])
+}
+
 // MarshalJSON marshals TypeComposite to JSON
 func (t TypeComposite) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.typeStrings)
@@ -232,8 +432,14 @@ func (t *TypeComposite) hasFQN(fqn string) bool {
 }
 
 func (t *TypeComposite) add(typeString TypeString) {
-	if t.hasFQN(typeString.GetFQN()) {
-		return
+	for i, current := range t.typeStrings {
+		// T and T[] are different types
+		if current.GetFQN() == typeString.GetFQN() && current.arrayLevel == typeString.arrayLevel {
+			if current.signature == nil && typeString.signature != nil {
+				t.typeStrings[i].signature = typeString.signature
+			}
+			return
+		}
 	}
 	t.typeStrings = append(t.typeStrings, typeString)
 }
@@ -244,6 +450,42 @@ func (t *TypeComposite) merge(types TypeComposite) {
 	}
 }
 
+// refineTypes returns the phpDoc types and the declared types which are not
+// refined by them, e.g. array with @return Foo[] is Foo[]
+func refineTypes(declared TypeComposite, phpDoc TypeComposite) TypeComposite {
+	types := newTypeComposite()
+	types.merge(phpDoc)
+	for _, typeString := range declared.Resolve() {
+		if !isRefinedBy(typeString, phpDoc) {
+			types.add(typeString)
+		}
+	}
+	return types
+}
+
+func isRefinedBy(typeString TypeString, types TypeComposite) bool {
+	name := strings.ToLower(typeString.GetOriginal())
+	for _, other := range types.Resolve() {
+		switch {
+		case name == "mixed":
+			return true
+		case name == "array" || name == "iterable":
+			if other.arrayLevel > 0 {
+				return true
+			}
+		case name == "object":
+			if strings.HasPrefix(other.GetFQN(), "\\") {
+				return true
+			}
+		case name == "callable":
+			if other.IsCallable() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (t *TypeComposite) mergeWithArrayLevel(types TypeComposite, arrayLevel int) {
 	for _, typeString := range types.typeStrings {
 		typeString.arrayLevel += arrayLevel
@@ -0,0 +1,41 @@
+package analysis
+
+import "github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+
+// Trace records the lookups of a query so the resolution of a symbol can
+// be explained, e.g. which FQNs were tried and which members were considered
+type Trace struct {
+	Steps []TraceStep `json:"steps"`
+}
+
+// TraceStep is a lookup of the query, the members are looked up by their
+// scopes so every inherited scope which is searched is a step
+type TraceStep struct {
+	Lookup string        `json:"lookup"`
+	Name   string        `json:"name"`
+	Cached bool          `json:"cached,omitempty"`
+	Found  []TraceSymbol `json:"found"`
+}
+
+// TraceSymbol is a symbol found by a lookup
+type TraceSymbol struct {
+	Name     string            `json:"name"`
+	Location protocol.Location `json:"location"`
+}
+
+// WithTrace makes the query record its lookups into the returned trace
+func (q *Query) WithTrace() *Trace {
+	q.trace = &Trace{Steps: []TraceStep{}}
+	return q.trace
+}
+
+func traceLookup[T Symbol](trace *Trace, lookup string, name string, cached bool, symbols []T) {
+	if trace == nil {
+		return
+	}
+	found := []TraceSymbol{}
+	for _, symbol := range symbols {
+		found = append(found, TraceSymbol{SymbolFQN(symbol), symbol.GetLocation()})
+	}
+	trace.Steps = append(trace.Steps, TraceStep{lookup, name, cached, found})
+}
@@ -10,8 +10,9 @@ import (
 
 // Trait contains information of a trait
 type Trait struct {
-	location protocol.Location
-	children []Symbol
+	location  protocol.Location
+	children  []Symbol
+	isGuarded bool
 
 	Name TypeString
 }
@@ -21,7 +22,8 @@ var _ BlockSymbol = (*Trait)(nil)
 
 func newTrait(a analyser, document *Document, node *phrase.Phrase) Symbol {
 	trait := &Trait{
-		location: document.GetNodeLocation(node),
+		location:  document.GetNodeLocation(node),
+		isGuarded: isExistenceGuarded(a, document),
 	}
 	document.addClass(trait)
 	document.addSymbol(trait)
@@ -89,12 +91,14 @@ func (s *Trait) GetIndexCollection() string {
 func (s *Trait) Serialise(e *storage.Encoder) {
 	e.WriteLocation(s.location)
 	s.Name.Write(e)
+	e.WriteBool(s.isGuarded)
 }
 
 func ReadTrait(d *storage.Decoder) *Trait {
 	return &Trait{
-		location: d.ReadLocation(),
-		Name:     ReadTypeString(d),
+		location:  d.ReadLocation(),
+		Name:      ReadTypeString(d),
+		isGuarded: d.ReadBool(),
 	}
 }
 
@@ -37,7 +37,12 @@ func traitAnalyseQualifiedNameList(document *Document, node *phrase.Phrase) {
 			case phrase.QualifiedName, phrase.FullyQualifiedName:
 				traitAccess := newTraitAccess(document, p)
 				document.addSymbol(traitAccess)
-				if class, ok := currentClass.(*Class); ok {
+				switch class := currentClass.(type) {
+				case *Class:
+					for _, typeString := range traitAccess.Type.Resolve() {
+						class.AddUse(typeString)
+					}
+				case *Enum:
 					for _, typeString := range traitAccess.Type.Resolve() {
 						class.AddUse(typeString)
 					}
@@ -0,0 +1,573 @@
+package analysis
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/john-nguyen09/go-phpparser/lexer"
+	"github.com/john-nguyen09/go-phpparser/phrase"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// TypeDiagnostics returns the diagnostics for the arguments which do not match
+// the types of the parameters, and the functions which do not return as their
+// return types declare
+func TypeDiagnostics(ctx ResolveContext) []protocol.Diagnostic {
+	defer util.TimeTrack(time.Now(), "TypeDiagnostics")
+	doc := ctx.document
+	isStrict := doc.hasStrictTypes()
+	diagnostics := []protocol.Diagnostic{}
+	traverseCalls(doc, func(argumentList *ArgumentList, callee HasParamsResolvable) {
+		diagnostics = append(diagnostics, candidatesDiagnostics(ctx.resolveCallee(callee), func(candidate HasParams) []protocol.Diagnostic {
+			return argumentTypesDiagnostics(ctx, argumentList, candidate, isStrict)
+		})...)
+	})
+	isNeverCall := neverReturningCalls(ctx)
+	traverser := util.NewTraverser(doc.GetRootNode())
+	traverser.Traverse(func(node phrase.AstNode, _ []*phrase.Phrase) util.VisitorContext {
+		if p, ok := node.(*phrase.Phrase); ok {
+			switch p.Type {
+			case phrase.FunctionDeclaration, phrase.MethodDeclaration, phrase.AnonymousFunctionCreationExpression:
+				diagnostics = append(diagnostics, returnDiagnostics(doc, p, isNeverCall)...)
+			case phrase.DocumentComment:
+				return util.VisitorContext{ShouldAscend: false}
+			}
+		}
+		return util.VisitorContext{ShouldAscend: true}
+	})
+	return diagnostics
+}
+
+// hasStrictTypes checks whether the document declares strict_types=1
+func (s *Document) hasStrictTypes() bool {
+	for _, child := range s.GetRootNode().Children {
+		p, ok := child.(*phrase.Phrase)
+		if !ok || p.Type != phrase.DeclareStatement {
+			continue
+		}
+		for _, c := range p.Children {
+			if directive, ok := c.(*phrase.Phrase); ok && directive.Type == phrase.DeclareDirective {
+				text := strings.ReplaceAll(s.GetNodeText(directive), " ", "")
+				if strings.EqualFold(text, "strict_types=1") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func argumentTypesDiagnostics(ctx ResolveContext, argumentList *ArgumentList, hasParams HasParams, isStrict bool) []protocol.Diagnostic {
+	diagnostics := []protocol.Diagnostic{}
+	params := hasParams.GetParams()
+	ranges := argumentList.GetArgumentRanges()
+	positionals := 0
+	for i, argument := range argumentList.GetArguments() {
+		if i >= len(ranges) {
+			break
+		}
+		if p, ok := argument.(*phrase.Phrase); ok && p.Type == phrase.VariadicUnpacking {
+			break
+		}
+		index := positionals
+		if name := argumentList.ArgumentName(i); name != "" {
+			index = paramIndex(params, name)
+		} else {
+			positionals++
+		}
+		if index >= len(params) && len(params) > 0 && params[len(params)-1].IsVariadic() {
+			index = len(params) - 1
+		}
+		if index < 0 || index >= len(params) {
+			continue
+		}
+		param := params[index]
+		if param.declaredTypes.IsEmpty() {
+			// The types from @param are not checked as they are often less
+			// precise than what the function accepts, e.g. the stubs
+			continue
+		}
+		types := argumentTypes(ctx, argumentList, argument)
+		if types.IsEmpty() || isAssignableToParam(ctx.query, types, param, isStrict) {
+			continue
+		}
+		diagnostics = append(diagnostics, newDiagnostic(CodeArgumentType, ranges[i],
+			"Argument "+strconv.Itoa(i+1)+" ("+param.Name+") of "+calleeLabel(hasParams)+
+				" expects "+param.declaredTypes.ToString()+", "+types.ToString()+" given."))
+	}
+	return diagnostics
+}
+
+// declaredTypes returns the types of the type declaration as PHP checks
+// them, e.g. ?Foo is Foo|null
+func declaredTypes(document *Document, node *phrase.Phrase) TypeComposite {
+	types := newTypeComposite()
+	for _, part := range strings.Split(document.GetNodeText(node), "|") {
+		part = strings.TrimSpace(part)
+		isNullable := strings.HasPrefix(part, "?")
+		typeString := NewTypeString(strings.TrimSpace(strings.TrimPrefix(part, "?")))
+		if typeString.IsEmpty() {
+			continue
+		}
+		typeString.SetFQN(document.currImportTable().GetClassReferenceFQN(typeString))
+		types.add(typeString)
+		if isNullable {
+			types.add(NewTypeString("null"))
+		}
+	}
+	return types
+}
+
+func isAssignableToParam(q *Query, types TypeComposite, param *Parameter, isStrict bool) bool {
+	declared := newTypeComposite()
+	declared.merge(param.declaredTypes)
+	if strings.EqualFold(param.Value, "null") {
+		// Parameters with null as the default value are implicitly nullable
+		declared.add(NewTypeString("null"))
+	}
+	for _, typeString := range types.Resolve() {
+		if q.IsAssignable(typeString, declared, isStrict) {
+			return true
+		}
+	}
+	return false
+}
+
+// argumentTypes returns the types of literals or the types of the
+// expression which is analysed as a symbol of the argument list
+func argumentTypes(ctx ResolveContext, argumentList *ArgumentList, argument phrase.AstNode) TypeComposite {
+	types := newTypeComposite()
+	switch v := argument.(type) {
+	case *lexer.Token:
+		switch v.Type {
+		case lexer.StringLiteral:
+			types.add(NewTypeString("string"))
+		case lexer.IntegerLiteral:
+			types.add(NewTypeString("int"))
+		case lexer.FloatingLiteral:
+			types.add(NewTypeString("float"))
+		}
+	case *phrase.Phrase:
+		switch v.Type {
+		case phrase.DoubleQuotedStringLiteral, phrase.HeredocStringLiteral:
+			types.add(NewTypeString("string"))
+		case phrase.ArrayCreationExpression:
+			types.add(NewTypeString("array"))
+		case phrase.ConstantAccessExpression:
+			switch strings.ToLower(strings.TrimPrefix(ctx.document.GetNodeText(v), "\\")) {
+			case "true", "false":
+				types.add(NewTypeString("bool"))
+			case "null":
+				types.add(NewTypeString("null"))
+			}
+		default:
+			if expression := argumentExpression(ctx.document, argumentList, v); expression != nil {
+				expression.Resolve(ctx)
+				types.merge(expression.GetTypes())
+			}
+		}
+	}
+	return types
+}
+
+// argumentExpression returns the symbol of the argument if the whole argument is the expression
+func argumentExpression(document *Document, argumentList *ArgumentList, node *phrase.Phrase) HasTypes {
+	var nameNode *phrase.Phrase
+	var isExpected func(Symbol) bool
+	switch node.Type {
+	case phrase.SimpleVariable:
+		nameNode = node
+		isExpected = func(s Symbol) bool { _, ok := s.(*Variable); return ok }
+	case phrase.FunctionCallExpression:
+		nameNode = firstPhrase(node)
+		isExpected = func(s Symbol) bool { _, ok := s.(*FunctionCall); return ok }
+	case phrase.MethodCallExpression:
+		nameNode = childPhrase(node, phrase.MemberName)
+		isExpected = func(s Symbol) bool { _, ok := s.(*MethodAccess); return ok }
+	case phrase.PropertyAccessExpression:
+		nameNode = childPhrase(node, phrase.MemberName)
+		isExpected = func(s Symbol) bool { _, ok := s.(*PropertyAccess); return ok }
+	case phrase.ScopedCallExpression:
+		nameNode = childPhrase(node, phrase.ScopedMemberName)
+		isExpected = func(s Symbol) bool { _, ok := s.(*ScopedMethodAccess); return ok }
+	case phrase.ScopedPropertyAccessExpression:
+		nameNode = childPhrase(node, phrase.ScopedMemberName)
+		isExpected = func(s Symbol) bool { _, ok := s.(*ScopedPropertyAccess); return ok }
+	case phrase.ObjectCreationExpression:
+		nameNode = childPhrase(node, phrase.ClassTypeDesignator)
+		isExpected = func(s Symbol) bool { _, ok := s.(*ClassTypeDesignator); return ok }
+	}
+	if nameNode == nil {
+		return nil
+	}
+	start := document.NodeRange(nameNode).Start
+	for _, child := range argumentList.GetChildren() {
+		if hasTypes, ok := child.(HasTypes); ok && isExpected(child) &&
+			protocol.ComparePos(child.GetLocation().Range.Start, start) == 0 {
+			return hasTypes
+		}
+	}
+	return nil
+}
+
+func firstPhrase(node *phrase.Phrase) *phrase.Phrase {
+	for _, child := range node.Children {
+		if p, ok := child.(*phrase.Phrase); ok {
+			return p
+		}
+	}
+	return nil
+}
+
+func childPhrase(node *phrase.Phrase, phraseType phrase.PhraseType) *phrase.Phrase {
+	for _, child := range node.Children {
+		if p, ok := child.(*phrase.Phrase); ok && p.Type == phraseType {
+			return p
+		}
+	}
+	return nil
+}
+
+// returnDiagnostics returns the diagnostics for the return statements which
+// do not match the declared return type of the function
+func returnDiagnostics(doc *Document, node *phrase.Phrase, isNeverCall neverCallChecker) []protocol.Diagnostic {
+	var (
+		nameRange  protocol.Range
+		returnType string
+		body       *phrase.Phrase
+	)
+	for _, child := range node.Children {
+		p, ok := child.(*phrase.Phrase)
+		if !ok {
+			continue
+		}
+		switch p.Type {
+		case phrase.FunctionDeclarationHeader, phrase.MethodDeclarationHeader, phrase.AnonymousFunctionHeader:
+			for _, c := range p.Children {
+				switch v := c.(type) {
+				case *lexer.Token:
+					if v.Type == lexer.Name || v.Type == lexer.Function && nameRange == (protocol.Range{}) {
+						nameRange = doc.NodeRange(v)
+					}
+				case *phrase.Phrase:
+					switch v.Type {
+					case phrase.Identifier:
+						nameRange = doc.NodeRange(v)
+					case phrase.ReturnType:
+						if typeDeclaration := childPhrase(v, phrase.TypeDeclaration); typeDeclaration != nil {
+							returnType = doc.GetNodeText(typeDeclaration)
+						}
+					}
+				}
+			}
+		case phrase.FunctionDeclarationBody:
+			body = p
+		case phrase.MethodDeclarationBody:
+			body = childPhrase(p, phrase.CompoundStatement)
+		}
+	}
+	if returnType == "" || body == nil {
+		return nil
+	}
+	create := func(r protocol.Range, message string) protocol.Diagnostic {
+		return newDiagnostic(CodeReturnType, r, message)
+	}
+	returns, isGenerator := returnStatements(body)
+	if isGenerator {
+		return nil
+	}
+	diagnostics := []protocol.Diagnostic{}
+	switch strings.ToLower(returnType) {
+	case "void":
+		for _, statement := range returns {
+			if hasReturnValue(statement) {
+				diagnostics = append(diagnostics, create(doc.NodeRange(statement), "A void function must not return a value."))
+			}
+		}
+	case "never":
+		for _, statement := range returns {
+			diagnostics = append(diagnostics, create(doc.NodeRange(statement), "A never-returning function must not return."))
+		}
+	default:
+		for _, statement := range returns {
+			if !hasReturnValue(statement) {
+				diagnostics = append(diagnostics, create(doc.NodeRange(statement),
+					"A function with return type "+returnType+" must return a value."))
+			}
+		}
+		if isNullableReturnType(returnType) || alwaysTerminates(doc, body, isNeverCall) {
+			break
+		}
+		diagnostics = append(diagnostics, create(nameRange,
+			"Missing return statement in a function with return type "+returnType+"."))
+	}
+	return diagnostics
+}
+
+func isNullableReturnType(returnType string) bool {
+	if strings.HasPrefix(returnType, "?") {
+		return true
+	}
+	for _, part := range strings.Split(returnType, "|") {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "mixed", "null":
+			return true
+		}
+	}
+	return false
+}
+
+func isFunctionLike(p *phrase.Phrase) bool {
+	switch p.Type {
+	case phrase.FunctionDeclaration, phrase.MethodDeclaration, phrase.AnonymousFunctionCreationExpression,
+		phrase.ArrowFunctionCreationExpression, phrase.ClassDeclaration, phrase.AnonymousClassDeclaration,
+		phrase.InterfaceDeclaration, phrase.TraitDeclaration:
+		return true
+	}
+	return false
+}
+
+// returnStatements returns the return statements of the function body and
+// whether the function is a generator
+func returnStatements(body *phrase.Phrase) ([]*phrase.Phrase, bool) {
+	returns := []*phrase.Phrase{}
+	isGenerator := false
+	traverser := util.NewTraverser(body)
+	traverser.Traverse(func(node phrase.AstNode, _ []*phrase.Phrase) util.VisitorContext {
+		p, ok := node.(*phrase.Phrase)
+		if !ok {
+			return util.VisitorContext{ShouldAscend: true}
+		}
+		if p != body && isFunctionLike(p) {
+			return util.VisitorContext{ShouldAscend: false}
+		}
+		switch p.Type {
+		case phrase.ReturnStatement:
+			returns = append(returns, p)
+		case phrase.YieldExpression, phrase.YieldFromExpression:
+			isGenerator = true
+		}
+		return util.VisitorContext{ShouldAscend: true}
+	})
+	return returns, isGenerator
+}
+
+func hasReturnValue(statement *phrase.Phrase) bool {
+	for _, child := range statement.Children {
+		if t, ok := child.(*lexer.Token); ok {
+			switch t.Type {
+			case lexer.Return, lexer.Semicolon, lexer.Whitespace, lexer.Comment:
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// alwaysTerminates checks whether the statement always returns, throws or exits
+func alwaysTerminates(doc *Document, node phrase.AstNode, isNeverCall neverCallChecker) bool {
+	p, ok := node.(*phrase.Phrase)
+	if !ok || p == nil {
+		return false
+	}
+	switch p.Type {
+	case phrase.ReturnStatement, phrase.ThrowStatement:
+		return true
+	case phrase.ExpressionStatement:
+		first := firstPhrase(p)
+		return first != nil && (first.Type == phrase.ExitIntrinsic || isNeverCall != nil && isNeverCall(first))
+	case phrase.CompoundStatement, phrase.StatementList, phrase.FunctionDeclarationBody:
+		for _, child := range p.Children {
+			if alwaysTerminates(doc, child, isNeverCall) {
+				return true
+			}
+		}
+	case phrase.IfStatement:
+		hasElse := false
+		for _, child := range p.Children {
+			clause, ok := child.(*phrase.Phrase)
+			if !ok {
+				continue
+			}
+			switch clause.Type {
+			case phrase.ElseIfClauseList:
+				for _, elseIf := range clause.Children {
+					if elseIfClause, ok := elseIf.(*phrase.Phrase); ok && !anyTerminates(doc, statementsAfterCondition(elseIfClause), isNeverCall) {
+						return false
+					}
+				}
+			case phrase.ElseClause:
+				hasElse = true
+				if !anyTerminates(doc, clause.Children, isNeverCall) {
+					return false
+				}
+			}
+		}
+		return hasElse && anyTerminates(doc, statementsAfterCondition(p), isNeverCall)
+	case phrase.SwitchStatement:
+		return switchAlwaysTerminates(doc, p, isNeverCall)
+	case phrase.TryStatement:
+		tryTerminates := true
+		for _, child := range p.Children {
+			clause, ok := child.(*phrase.Phrase)
+			if !ok {
+				continue
+			}
+			switch clause.Type {
+			case phrase.CompoundStatement:
+				tryTerminates = tryTerminates && alwaysTerminates(doc, clause, isNeverCall)
+			case phrase.CatchClauseList:
+				for _, catch := range clause.Children {
+					if catchClause, ok := catch.(*phrase.Phrase); ok {
+						tryTerminates = tryTerminates && alwaysTerminates(doc, childPhrase(catchClause, phrase.CompoundStatement), isNeverCall)
+					}
+				}
+			case phrase.FinallyClause:
+				if alwaysTerminates(doc, childPhrase(clause, phrase.CompoundStatement), isNeverCall) {
+					return true
+				}
+			}
+		}
+		return tryTerminates
+	case phrase.WhileStatement, phrase.DoStatement, phrase.ForStatement:
+		// Infinite loops only end with break
+		return isInfiniteLoop(doc, p) && !hasBreak(p)
+	}
+	return false
+}
+
+func anyTerminates(doc *Document, nodes []phrase.AstNode, isNeverCall neverCallChecker) bool {
+	for _, node := range nodes {
+		if alwaysTerminates(doc, node, isNeverCall) {
+			return true
+		}
+	}
+	return false
+}
+
+// statementsAfterCondition returns the statements of if or elseif after the condition
+func statementsAfterCondition(node *phrase.Phrase) []phrase.AstNode {
+	for i, child := range node.Children {
+		if t, ok := child.(*lexer.Token); ok && t.Type == lexer.CloseParenthesis {
+			statements := []phrase.AstNode{}
+			for _, c := range node.Children[i+1:] {
+				if p, ok := c.(*phrase.Phrase); ok && p.Type != phrase.ElseIfClauseList && p.Type != phrase.ElseClause {
+					statements = append(statements, p)
+				}
+			}
+			return statements
+		}
+	}
+	return nil
+}
+
+// switchAlwaysTerminates checks whether the switch has a default case and
+// every case either falls through to the next case or terminates before it
+// breaks out of the switch, the last case must terminate
+func switchAlwaysTerminates(doc *Document, node *phrase.Phrase, isNeverCall neverCallChecker) bool {
+	caseList := childPhrase(node, phrase.CaseStatementList)
+	if caseList == nil {
+		return false
+	}
+	hasDefault := false
+	terminates := false
+	for _, child := range caseList.Children {
+		clause, ok := child.(*phrase.Phrase)
+		if !ok {
+			continue
+		}
+		if clause.Type == phrase.DefaultStatement {
+			hasDefault = true
+		}
+		terminates = false
+		statements := childPhrase(clause, phrase.StatementList)
+		if statements == nil {
+			continue
+		}
+		for _, statement := range statements.Children {
+			if alwaysTerminates(doc, statement, isNeverCall) {
+				// The statements after it, e.g. break, are unreachable
+				terminates = true
+				break
+			}
+			if p, ok := statement.(*phrase.Phrase); ok && breaksOut(p) {
+				return false
+			}
+		}
+	}
+	return hasDefault && terminates
+}
+
+func isInfiniteLoop(doc *Document, node *phrase.Phrase) bool {
+	if node.Type == phrase.ForStatement {
+		semicolons := 0
+		for _, child := range node.Children {
+			if t, ok := child.(*lexer.Token); ok && t.Type == lexer.Semicolon {
+				semicolons++
+				continue
+			}
+			if p, ok := child.(*phrase.Phrase); ok && semicolons == 1 && p.Type == phrase.ForControl {
+				return false
+			}
+		}
+		return true
+	}
+	hasOpenParenthesis := false
+	for _, child := range node.Children {
+		switch v := child.(type) {
+		case *lexer.Token:
+			if v.Type == lexer.OpenParenthesis {
+				hasOpenParenthesis = true
+			} else if hasOpenParenthesis && v.Type == lexer.IntegerLiteral {
+				return doc.getTokenText(v) == "1"
+			}
+		case *phrase.Phrase:
+			if hasOpenParenthesis {
+				return strings.EqualFold(strings.TrimPrefix(doc.GetNodeText(v), "\\"), "true")
+			}
+		}
+	}
+	return false
+}
+
+// breaksOut checks whether the statement can break out of the enclosing loop or switch
+func breaksOut(statement *phrase.Phrase) bool {
+	switch statement.Type {
+	case phrase.BreakStatement, phrase.ContinueStatement:
+		return true
+	case phrase.WhileStatement, phrase.DoStatement, phrase.ForStatement, phrase.ForeachStatement,
+		phrase.SwitchStatement:
+		return false
+	}
+	return hasBreak(statement)
+}
+
+// hasBreak checks whether there is a break or continue of the loop or switch
+func hasBreak(node *phrase.Phrase) bool {
+	found := false
+	traverser := util.NewTraverser(node)
+	traverser.Traverse(func(child phrase.AstNode, _ []*phrase.Phrase) util.VisitorContext {
+		p, ok := child.(*phrase.Phrase)
+		if !ok || found {
+			return util.VisitorContext{ShouldAscend: false}
+		}
+		if p != node {
+			switch p.Type {
+			case phrase.BreakStatement, phrase.ContinueStatement:
+				found = true
+				return util.VisitorContext{ShouldAscend: false}
+			case phrase.WhileStatement, phrase.DoStatement, phrase.ForStatement, phrase.ForeachStatement,
+				phrase.SwitchStatement:
+				return util.VisitorContext{ShouldAscend: false}
+			}
+			if isFunctionLike(p) {
+				return util.VisitorContext{ShouldAscend: false}
+			}
+		}
+		return util.VisitorContext{ShouldAscend: true}
+	})
+	return found
+}
@@ -0,0 +1,861 @@
+package analysis
+
+import (
+	"time"
+
+	"github.com/john-nguyen09/go-phpparser/lexer"
+	"github.com/john-nguyen09/go-phpparser/phrase"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// Codes of the undefined variable diagnostics, each of them can be disabled by the clients
+const (
+	CodeUndefinedVariable         = "undefined-variable"
+	CodePossiblyUndefinedVariable = "possibly-undefined-variable"
+)
+
+var /* const */ superGlobals = map[string]struct{}{
+	"$GLOBALS":  {},
+	"$_SERVER":  {},
+	"$_GET":     {},
+	"$_POST":    {},
+	"$_FILES":   {},
+	"$_COOKIE":  {},
+	"$_SESSION": {},
+	"$_REQUEST": {},
+	"$_ENV":     {},
+	"$this":     {},
+
+	"$http_response_header": {},
+}
+
+// variableFlow is the state of the variables at a point of a function scope
+type variableFlow struct {
+	defined    map[string]struct{}
+	possible   map[string]struct{}
+	suppressed bool
+	// reported is shared by the states of the same scope so that
+	// a variable is only reported once
+	reported map[string]struct{}
+	isScope  bool
+}
+
+func newVariableFlow(isScope bool) *variableFlow {
+	return &variableFlow{
+		defined:  map[string]struct{}{},
+		possible: map[string]struct{}{},
+		reported: map[string]struct{}{},
+		isScope:  isScope,
+	}
+}
+
+func (f *variableFlow) copy() *variableFlow {
+	result := &variableFlow{
+		defined:    make(map[string]struct{}, len(f.defined)),
+		possible:   make(map[string]struct{}, len(f.possible)),
+		suppressed: f.suppressed,
+		reported:   f.reported,
+		isScope:    f.isScope,
+	}
+	for name := range f.defined {
+		result.defined[name] = struct{}{}
+	}
+	for name := range f.possible {
+		result.possible[name] = struct{}{}
+	}
+	return result
+}
+
+func (f *variableFlow) define(name string) {
+	f.defined[name] = struct{}{}
+	delete(f.possible, name)
+}
+
+// mergeFlows returns the state after the branches, the variables are defined
+// if they are defined in all the branches and possibly defined otherwise
+func mergeFlows(base *variableFlow, branches []*variableFlow) *variableFlow {
+	if len(branches) == 0 {
+		return base
+	}
+	result := branches[0].copy()
+	for _, branch := range branches[1:] {
+		for name := range result.defined {
+			if _, ok := branch.defined[name]; !ok {
+				delete(result.defined, name)
+				result.possible[name] = struct{}{}
+			}
+		}
+		for name := range branch.defined {
+			if _, ok := result.defined[name]; !ok {
+				result.possible[name] = struct{}{}
+			}
+		}
+		for name := range branch.possible {
+			result.possible[name] = struct{}{}
+		}
+		result.suppressed = result.suppressed || branch.suppressed
+	}
+	return result
+}
+
+type undefinedVariables struct {
+	ctx         ResolveContext
+	doc         *Document
+	isNeverCall neverCallChecker
+	diagnostics []protocol.Diagnostic
+}
+
+// UndefinedVariableDiagnostics returns the diagnostics for the variables which
+// are read in a function scope without being assigned before, the variables
+// which are only assigned in some branches are possibly undefined
+func UndefinedVariableDiagnostics(ctx ResolveContext) []protocol.Diagnostic {
+	defer util.TimeTrack(time.Now(), "UndefinedVariableDiagnostics")
+	u := &undefinedVariables{
+		ctx:         ctx,
+		doc:         ctx.document,
+		isNeverCall: neverReturningCalls(ctx),
+		diagnostics: []protocol.Diagnostic{},
+	}
+	// The global scope is not checked because the variables
+	// can be defined by the including files
+	u.statements(u.doc.GetRootNode().Children, newVariableFlow(false))
+	return u.diagnostics
+}
+
+func (u *undefinedVariables) read(name string, node phrase.AstNode, flow *variableFlow) {
+	if !flow.isScope || flow.suppressed {
+		return
+	}
+	if _, ok := superGlobals[name]; ok {
+		return
+	}
+	if _, ok := flow.defined[name]; ok {
+		return
+	}
+	if _, ok := flow.reported[name]; ok {
+		return
+	}
+	flow.reported[name] = struct{}{}
+	if _, ok := flow.possible[name]; ok {
+		u.diagnostics = append(u.diagnostics, newDiagnostic(CodePossiblyUndefinedVariable,
+			u.doc.NodeRange(node), "Variable "+name+" might not be defined."))
+		return
+	}
+	u.diagnostics = append(u.diagnostics, newDiagnostic(CodeUndefinedVariable,
+		u.doc.NodeRange(node), "Undefined variable "+name+"."))
+}
+
+// functionScope checks the function, method or closure with the
+// parameters and the given variables defined
+func (u *undefinedVariables) functionScope(node *phrase.Phrase, flow *variableFlow) {
+	for _, child := range node.Children {
+		p, ok := child.(*phrase.Phrase)
+		if !ok {
+			continue
+		}
+		switch p.Type {
+		case phrase.FunctionDeclarationHeader, phrase.MethodDeclarationHeader,
+			phrase.AnonymousFunctionHeader, phrase.ArrowFunctionHeader:
+			if params := childPhrase(p, phrase.ParameterDeclarationList); params != nil {
+				for _, param := range params.Children {
+					if paramNode, ok := param.(*phrase.Phrase); ok && paramNode.Type == phrase.ParameterDeclaration {
+						u.defineTokens(paramNode, flow)
+					}
+				}
+			}
+		case phrase.FunctionDeclarationBody, phrase.MethodDeclarationBody, phrase.CompoundStatement, phrase.StatementList:
+			u.statements(p.Children, flow)
+		default:
+			// The body of arrow functions is an expression
+			u.expression(p, flow)
+		}
+	}
+}
+
+// defineTokens defines the variable name tokens which are the direct children of the node
+func (u *undefinedVariables) defineTokens(node *phrase.Phrase, flow *variableFlow) {
+	for _, child := range node.Children {
+		switch v := child.(type) {
+		case *lexer.Token:
+			if v.Type == lexer.VariableName {
+				flow.define(u.doc.getTokenText(v))
+			}
+		case *phrase.Phrase:
+			u.expression(v, flow)
+		}
+	}
+}
+
+// statements checks the statements and returns the state after them
+func (u *undefinedVariables) statements(nodes []phrase.AstNode, flow *variableFlow) *variableFlow {
+	for _, node := range nodes {
+		flow = u.statement(node, flow)
+	}
+	return flow
+}
+
+// exits checks whether the statements leave the branch
+func (u *undefinedVariables) exits(nodes []phrase.AstNode) bool {
+	for _, node := range nodes {
+		if p, ok := node.(*phrase.Phrase); ok {
+			switch p.Type {
+			case phrase.BreakStatement, phrase.ContinueStatement:
+				return true
+			case phrase.CompoundStatement, phrase.StatementList:
+				if u.exits(p.Children) {
+					return true
+				}
+			}
+		}
+		if alwaysTerminates(u.doc, node, u.isNeverCall) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *undefinedVariables) statement(node phrase.AstNode, flow *variableFlow) *variableFlow {
+	p, ok := node.(*phrase.Phrase)
+	if !ok {
+		return flow
+	}
+	switch p.Type {
+	case phrase.FunctionDeclaration, phrase.MethodDeclaration:
+		u.functionScope(p, newVariableFlow(true))
+		return flow
+	case phrase.IfStatement:
+		return u.ifStatement(p, flow)
+	case phrase.SwitchStatement:
+		return u.switchStatement(p, flow)
+	case phrase.WhileStatement, phrase.ForStatement, phrase.ForeachStatement, phrase.DoStatement:
+		return u.loop(p, flow)
+	case phrase.TryStatement:
+		return u.tryStatement(p, flow)
+	case phrase.GlobalDeclaration:
+		if list := childPhrase(p, phrase.VariableNameList); list != nil {
+			for _, child := range list.Children {
+				if variable, ok := child.(*phrase.Phrase); ok && variable.Type == phrase.SimpleVariable {
+					flow.define(u.doc.GetNodeText(variable))
+				}
+			}
+		}
+		return flow
+	case phrase.FunctionStaticDeclaration:
+		if list := childPhrase(p, phrase.StaticVariableDeclarationList); list != nil {
+			for _, child := range list.Children {
+				if declaration, ok := child.(*phrase.Phrase); ok && declaration.Type == phrase.StaticVariableDeclaration {
+					u.defineTokens(declaration, flow)
+				}
+			}
+		}
+		return flow
+	case phrase.UnsetIntrinsic:
+		if list := childPhrase(p, phrase.VariableList); list != nil {
+			for _, child := range list.Children {
+				if variable, ok := child.(*phrase.Phrase); ok && variable.Type == phrase.SimpleVariable {
+					name := u.doc.GetNodeText(variable)
+					delete(flow.defined, name)
+					delete(flow.possible, name)
+					delete(flow.reported, name)
+				}
+			}
+		}
+		return flow
+	case phrase.CompoundStatement, phrase.StatementList:
+		return u.statements(p.Children, flow)
+	}
+	u.expression(p, flow)
+	return flow
+}
+
+// conditionAndStatements checks the condition of if, elseif or while and
+// returns the statements after the condition
+func (u *undefinedVariables) conditionAndStatements(node *phrase.Phrase, flow *variableFlow) []phrase.AstNode {
+	isCondition := false
+	for i, child := range node.Children {
+		if t, ok := child.(*lexer.Token); ok {
+			switch t.Type {
+			case lexer.OpenParenthesis:
+				isCondition = true
+			case lexer.CloseParenthesis:
+				statements := []phrase.AstNode{}
+				for _, c := range node.Children[i+1:] {
+					if p, ok := c.(*phrase.Phrase); ok && p.Type != phrase.ElseIfClauseList && p.Type != phrase.ElseClause {
+						statements = append(statements, p)
+					}
+				}
+				return statements
+			}
+			continue
+		}
+		if isCondition {
+			u.expression(child, flow)
+		}
+	}
+	return nil
+}
+
+// guard defines the variables which are checked by isset() or !empty()
+// in the condition of if, elseif or while, the statements after the
+// condition only run when the variables are set
+func (u *undefinedVariables) guard(node *phrase.Phrase, flow *variableFlow) {
+	isCondition := false
+	for _, child := range node.Children {
+		if t, ok := child.(*lexer.Token); ok {
+			switch t.Type {
+			case lexer.OpenParenthesis:
+				isCondition = true
+			case lexer.CloseParenthesis:
+				return
+			}
+			continue
+		}
+		if isCondition {
+			for _, name := range guardedVariables(u.doc, child) {
+				flow.define(name)
+			}
+		}
+	}
+}
+
+// guardedVariables returns the names of the variables which are set when
+// the condition is true
+func guardedVariables(doc *Document, node phrase.AstNode) []string {
+	p, ok := node.(*phrase.Phrase)
+	if !ok {
+		return nil
+	}
+	names := []string{}
+	switch p.Type {
+	case phrase.IssetIntrinsic, phrase.VariableList:
+		for _, child := range p.Children {
+			if c, ok := child.(*phrase.Phrase); ok && c.Type == phrase.VariableList {
+				names = append(names, guardedVariables(doc, c)...)
+				continue
+			}
+			if name := baseVariableName(doc, child); name != "" {
+				names = append(names, name)
+			}
+		}
+	case phrase.UnaryOpExpression:
+		// !empty($x)
+		if t, ok := p.Children[0].(*lexer.Token); ok && t.Type == lexer.Exclamation {
+			if operand := childPhrase(p, phrase.EmptyIntrinsic); operand != nil {
+				for _, child := range operand.Children {
+					if name := baseVariableName(doc, child); name != "" {
+						names = append(names, name)
+					}
+				}
+			}
+		}
+	case phrase.EncapsulatedExpression:
+		for _, child := range p.Children {
+			names = append(names, guardedVariables(doc, child)...)
+		}
+	case phrase.LogicalExpression:
+		// Both operands of && are true when the condition is true
+		for _, child := range p.Children {
+			if t, ok := child.(*lexer.Token); ok && t.Type != lexer.Whitespace &&
+				t.Type != lexer.AmpersandAmpersand && t.Type != lexer.And {
+				return nil
+			}
+		}
+		for _, child := range p.Children {
+			names = append(names, guardedVariables(doc, child)...)
+		}
+	}
+	return names
+}
+
+// baseVariableName returns the name of the variable which is accessed by
+// the node, e.g. $x of $x['key'] or $x->prop
+func baseVariableName(doc *Document, node phrase.AstNode) string {
+	p, ok := node.(*phrase.Phrase)
+	if !ok || p == nil {
+		return ""
+	}
+	switch p.Type {
+	case phrase.SimpleVariable:
+		if t, ok := p.Children[0].(*lexer.Token); ok && t.Type == lexer.VariableName {
+			return doc.getTokenText(t)
+		}
+	case phrase.SubscriptExpression, phrase.PropertyAccessExpression:
+		return baseVariableName(doc, firstPhrase(p))
+	}
+	return ""
+}
+
+func (u *undefinedVariables) ifStatement(node *phrase.Phrase, flow *variableFlow) *variableFlow {
+	branches := []*variableFlow{}
+	addBranch := func(statements []phrase.AstNode, branch *variableFlow) {
+		branch = u.statements(statements, branch)
+		if !u.exits(statements) {
+			branches = append(branches, branch)
+		}
+	}
+	statements := u.conditionAndStatements(node, flow)
+	branch := flow.copy()
+	u.guard(node, branch)
+	addBranch(statements, branch)
+	hasElse := false
+	for _, child := range node.Children {
+		p, ok := child.(*phrase.Phrase)
+		if !ok {
+			continue
+		}
+		switch p.Type {
+		case phrase.ElseIfClauseList:
+			for _, c := range p.Children {
+				if elseIf, ok := c.(*phrase.Phrase); ok && elseIf.Type == phrase.ElseIfClause {
+					branch := flow.copy()
+					statements := u.conditionAndStatements(elseIf, branch)
+					u.guard(elseIf, branch)
+					addBranch(statements, branch)
+				}
+			}
+		case phrase.ElseClause:
+			hasElse = true
+			addBranch(p.Children, flow.copy())
+		}
+	}
+	if !hasElse {
+		branches = append(branches, flow)
+	}
+	return mergeFlows(flow, branches)
+}
+
+func (u *undefinedVariables) switchStatement(node *phrase.Phrase, flow *variableFlow) *variableFlow {
+	u.conditionAndStatements(node, flow)
+	branches := []*variableFlow{}
+	hasDefault := false
+	var fallthroughFlow *variableFlow
+	if caseList := childPhrase(node, phrase.CaseStatementList); caseList != nil {
+		for _, child := range caseList.Children {
+			clause, ok := child.(*phrase.Phrase)
+			if !ok {
+				continue
+			}
+			if clause.Type == phrase.DefaultStatement {
+				hasDefault = true
+			}
+			branch := flow.copy()
+			if fallthroughFlow != nil {
+				branch = mergeFlows(flow, []*variableFlow{branch, fallthroughFlow})
+			}
+			var statements []phrase.AstNode
+			for _, c := range clause.Children {
+				if p, ok := c.(*phrase.Phrase); ok {
+					if p.Type == phrase.StatementList {
+						statements = p.Children
+						continue
+					}
+					u.expression(p, branch)
+				}
+			}
+			branch = u.statements(statements, branch)
+			fallthroughFlow = nil
+			if len(statements) == 0 || !u.exits(statements) {
+				fallthroughFlow = branch
+				continue
+			}
+			if !anyTerminates(u.doc, statements, u.isNeverCall) {
+				// The case ends with break
+				branches = append(branches, branch)
+			}
+		}
+	}
+	if fallthroughFlow != nil {
+		branches = append(branches, fallthroughFlow)
+	}
+	if !hasDefault {
+		branches = append(branches, flow)
+	}
+	return mergeFlows(flow, branches)
+}
+
+func (u *undefinedVariables) loop(node *phrase.Phrase, flow *variableFlow) *variableFlow {
+	// The variables assigned in the loop are possibly defined
+	// in the next iterations
+	body := flow.copy()
+	for name := range assignedVariables(u.doc, node) {
+		if _, ok := body.defined[name]; !ok {
+			body.possible[name] = struct{}{}
+		}
+	}
+	var statements []phrase.AstNode
+	switch node.Type {
+	case phrase.ForeachStatement:
+		for _, child := range node.Children {
+			p, ok := child.(*phrase.Phrase)
+			if !ok {
+				continue
+			}
+			switch p.Type {
+			case phrase.ForeachCollection:
+				u.expression(p, flow)
+			case phrase.ForeachKey, phrase.ForeachValue:
+				for _, c := range p.Children {
+					u.assign(c, body)
+				}
+			default:
+				statements = append(statements, p)
+			}
+		}
+	case phrase.ForStatement:
+		var endOfLoop []phrase.AstNode
+		for _, child := range node.Children {
+			p, ok := child.(*phrase.Phrase)
+			if !ok {
+				continue
+			}
+			switch p.Type {
+			case phrase.ForInitialiser:
+				u.expression(p, flow)
+				u.expression(p, body)
+			case phrase.ForControl:
+				u.expression(p, flow)
+				u.expression(p, body)
+			case phrase.ForEndOfLoop:
+				endOfLoop = append(endOfLoop, p)
+			default:
+				statements = append(statements, p)
+			}
+		}
+		body = u.statements(statements, body)
+		for _, p := range endOfLoop {
+			u.expression(p, body)
+		}
+		return mergeFlows(flow, []*variableFlow{flow, body})
+	case phrase.DoStatement:
+		for _, child := range node.Children {
+			if p, ok := child.(*phrase.Phrase); ok {
+				statements = append(statements, p)
+			}
+		}
+		// The statement always runs once
+		return u.statements(statements, flow)
+	default:
+		statements = u.conditionAndStatements(node, flow)
+		// The variables assigned in the condition are defined in the body
+		u.conditionAndStatements(node, body)
+		u.guard(node, body)
+	}
+	body = u.statements(statements, body)
+	return mergeFlows(flow, []*variableFlow{flow, body})
+}
+
+func (u *undefinedVariables) tryStatement(node *phrase.Phrase, flow *variableFlow) *variableFlow {
+	branches := []*variableFlow{}
+	var tryFlow *variableFlow
+	for _, child := range node.Children {
+		p, ok := child.(*phrase.Phrase)
+		if !ok {
+			continue
+		}
+		switch p.Type {
+		case phrase.CompoundStatement:
+			tryFlow = u.statement(p, flow.copy())
+			if !u.exits(p.Children) {
+				branches = append(branches, tryFlow)
+			}
+		case phrase.CatchClauseList:
+			for _, c := range p.Children {
+				catch, ok := c.(*phrase.Phrase)
+				if !ok || catch.Type != phrase.CatchClause {
+					continue
+				}
+				// Any statement of try can throw
+				catchFlow := mergeFlows(flow, []*variableFlow{flow, tryFlow})
+				var statements []phrase.AstNode
+				for _, cc := range catch.Children {
+					switch v := cc.(type) {
+					case *lexer.Token:
+						if v.Type == lexer.VariableName {
+							catchFlow.define(u.doc.getTokenText(v))
+						}
+					case *phrase.Phrase:
+						if v.Type == phrase.CompoundStatement {
+							statements = v.Children
+						}
+					}
+				}
+				catchFlow = u.statements(statements, catchFlow)
+				if !u.exits(statements) {
+					branches = append(branches, catchFlow)
+				}
+			}
+		case phrase.FinallyClause:
+			finallyFlow := mergeFlows(flow, append([]*variableFlow{flow}, branches...))
+			u.statement(childPhrase(p, phrase.CompoundStatement), finallyFlow)
+		}
+	}
+	return mergeFlows(flow, branches)
+}
+
+// assign defines the variables of the assignment target
+func (u *undefinedVariables) assign(node phrase.AstNode, flow *variableFlow) {
+	p, ok := node.(*phrase.Phrase)
+	if !ok {
+		return
+	}
+	switch p.Type {
+	case phrase.SimpleVariable:
+		if t, ok := p.Children[0].(*lexer.Token); ok && t.Type == lexer.VariableName && len(p.Children) == 1 {
+			flow.define(u.doc.getTokenText(t))
+			return
+		}
+		u.expression(p, flow)
+	case phrase.ListIntrinsic, phrase.ArrayCreationExpression:
+		list := childPhrase(p, phrase.ArrayInitialiserList)
+		if list == nil {
+			return
+		}
+		for _, child := range list.Children {
+			element, ok := child.(*phrase.Phrase)
+			if !ok || element.Type != phrase.ArrayElement {
+				continue
+			}
+			for _, c := range element.Children {
+				if part, ok := c.(*phrase.Phrase); ok {
+					if part.Type == phrase.ArrayValue {
+						for _, value := range part.Children {
+							u.assign(value, flow)
+						}
+						continue
+					}
+					u.expression(part, flow)
+				}
+			}
+		}
+	case phrase.SubscriptExpression:
+		// Arrays are created by assigning their elements
+		for i, child := range p.Children {
+			if i == 0 {
+				u.assign(child, flow)
+				continue
+			}
+			u.expression(child, flow)
+		}
+	default:
+		u.expression(p, flow)
+	}
+}
+
+func (u *undefinedVariables) expression(node phrase.AstNode, flow *variableFlow) {
+	p, ok := node.(*phrase.Phrase)
+	if !ok {
+		return
+	}
+	switch p.Type {
+	case phrase.SimpleVariable:
+		for _, child := range p.Children {
+			switch v := child.(type) {
+			case *lexer.Token:
+				if v.Type == lexer.VariableName {
+					u.read(u.doc.getTokenText(v), v, flow)
+				}
+			case *phrase.Phrase:
+				// Variable variables can be any variable
+				flow.suppressed = true
+				u.expression(v, flow)
+			}
+		}
+		return
+	case phrase.SimpleAssignmentExpression, phrase.ByRefAssignmentExpression, phrase.CompoundAssignmentExpression:
+		u.assignment(p, flow)
+		return
+	case phrase.IssetIntrinsic, phrase.EmptyIntrinsic:
+		return
+	case phrase.CoalesceExpression:
+		for i, child := range p.Children {
+			if i > 0 {
+				u.expression(child, flow)
+			}
+		}
+		return
+	case phrase.FunctionDeclaration, phrase.MethodDeclaration:
+		u.functionScope(p, newVariableFlow(true))
+		return
+	case phrase.AnonymousFunctionCreationExpression:
+		u.closure(p, flow)
+		return
+	case phrase.ArrowFunctionCreationExpression:
+		// Arrow functions capture the variables of the parent scope by value
+		arrowFlow := flow.copy()
+		arrowFlow.reported = map[string]struct{}{}
+		u.functionScope(p, arrowFlow)
+		return
+	case phrase.IncludeExpression, phrase.IncludeOnceExpression, phrase.RequireExpression, phrase.RequireOnceExpression:
+		u.children(p, flow)
+		flow.suppressed = true
+		return
+	case phrase.FunctionCallExpression, phrase.MethodCallExpression, phrase.ScopedCallExpression, phrase.ObjectCreationExpression:
+		u.call(p, flow)
+		return
+	}
+	u.children(p, flow)
+}
+
+func (u *undefinedVariables) children(node *phrase.Phrase, flow *variableFlow) {
+	for _, child := range node.Children {
+		if p, ok := child.(*phrase.Phrase); ok {
+			if isStatementPhrase(p) {
+				u.statement(p, flow)
+				continue
+			}
+			u.expression(p, flow)
+		}
+	}
+}
+
+func isStatementPhrase(p *phrase.Phrase) bool {
+	switch p.Type {
+	case phrase.FunctionDeclaration, phrase.MethodDeclaration, phrase.CompoundStatement, phrase.StatementList,
+		phrase.IfStatement, phrase.SwitchStatement, phrase.WhileStatement, phrase.ForStatement,
+		phrase.ForeachStatement, phrase.DoStatement, phrase.TryStatement, phrase.GlobalDeclaration,
+		phrase.FunctionStaticDeclaration, phrase.UnsetIntrinsic:
+		return true
+	}
+	return false
+}
+
+func (u *undefinedVariables) assignment(node *phrase.Phrase, flow *variableFlow) {
+	var target phrase.AstNode
+	isByRef := node.Type == phrase.ByRefAssignmentExpression
+	isRight := false
+	for _, child := range node.Children {
+		if t, ok := child.(*lexer.Token); ok {
+			if t.Type != lexer.Whitespace && t.Type != lexer.Ampersand {
+				isRight = true
+			}
+			continue
+		}
+		if !isRight {
+			target = child
+			continue
+		}
+		if isByRef {
+			// Referencing a variable creates it
+			u.assign(child, flow)
+			continue
+		}
+		u.expression(child, flow)
+	}
+	if node.Type == phrase.CompoundAssignmentExpression {
+		u.expression(target, flow)
+	}
+	u.assign(target, flow)
+}
+
+func (u *undefinedVariables) closure(node *phrase.Phrase, flow *variableFlow) {
+	closureFlow := newVariableFlow(true)
+	if header := childPhrase(node, phrase.AnonymousFunctionHeader); header != nil {
+		if useClause := childPhrase(header, phrase.AnonymousFunctionUseClause); useClause != nil {
+			if list := childPhrase(useClause, phrase.ClosureUseList); list != nil {
+				for _, child := range list.Children {
+					useVariable, ok := child.(*phrase.Phrase)
+					if !ok || useVariable.Type != phrase.AnonymousFunctionUseVariable {
+						continue
+					}
+					isByRef := false
+					for _, c := range useVariable.Children {
+						if t, ok := c.(*lexer.Token); ok {
+							switch t.Type {
+							case lexer.Ampersand:
+								isByRef = true
+							case lexer.VariableName:
+								name := u.doc.getTokenText(t)
+								if isByRef {
+									flow.define(name)
+								} else {
+									u.read(name, t, flow)
+								}
+								closureFlow.define(name)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	u.functionScope(node, closureFlow)
+}
+
+// call checks the arguments of the call, the variables passed to the
+// parameters by reference are assigned, the variables passed to
+// unknown callees are assumed to be assigned
+func (u *undefinedVariables) call(node *phrase.Phrase, flow *variableFlow) {
+	nameNode := calleeNameNode(node)
+	var params []*Parameter
+	isKnown := false
+	if nameNode != nil {
+		if callee, ok := u.ctx.calleeAt(u.doc.NodeRange(nameNode).Start); ok {
+			for _, hasParams := range u.ctx.resolveCallee(callee) {
+				params = hasParams.GetParams()
+				isKnown = true
+				break
+			}
+		}
+		if node.Type == phrase.FunctionCallExpression && readsAllVariables(u.doc.GetNodeText(nameNode)) {
+			defer func() { flow.suppressed = true }()
+		}
+	}
+	for _, child := range node.Children {
+		p, ok := child.(*phrase.Phrase)
+		if !ok {
+			continue
+		}
+		if p.Type != phrase.ArgumentExpressionList {
+			u.expression(p, flow)
+			continue
+		}
+		index := 0
+		for _, argument := range p.Children {
+			a, ok := argument.(*phrase.Phrase)
+			if !ok {
+				// literal arguments still take the position of a parameter
+				if t, ok := argument.(*lexer.Token); ok && isArgumentToken(t) {
+					index++
+				}
+				continue
+			}
+			isVariable := a.Type == phrase.SimpleVariable || a.Type == phrase.SubscriptExpression
+			isByRef := !isKnown
+			if index < len(params) {
+				isByRef = params[index].IsReference()
+			} else if len(params) > 0 && params[len(params)-1].IsVariadic() {
+				isByRef = params[len(params)-1].IsReference()
+			}
+			if isVariable && isByRef {
+				u.assign(a, flow)
+			} else {
+				u.expression(a, flow)
+			}
+			index++
+		}
+	}
+}
+
+func isArgumentToken(t *lexer.Token) bool {
+	switch t.Type {
+	case lexer.OpenParenthesis, lexer.CloseParenthesis, lexer.Comma, lexer.Whitespace, lexer.Comment:
+		return false
+	}
+	return true
+}
+
+// assignedVariables returns the names of the variables assigned in the node
+func assignedVariables(doc *Document, node *phrase.Phrase) map[string]struct{} {
+	names := map[string]struct{}{}
+	flow := newVariableFlow(false)
+	u := &undefinedVariables{doc: doc}
+	u.children(node, flow)
+	for name := range flow.defined {
+		names[name] = struct{}{}
+	}
+	for name := range flow.possible {
+		names[name] = struct{}{}
+	}
+	return names
+}
@@ -0,0 +1,200 @@
+package analysis
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// Codes of the unused member diagnostics, each of them can be disabled by the clients
+const (
+	CodeUnusedPrivateMember = "unused-private-member"
+	CodeUnusedParameter     = "unused-parameter"
+)
+
+// UnusedMemberDiagnostics returns the diagnostics for the private methods, properties
+// and class constants of classes which are never referenced inside the classes or
+// their traits, and the parameters which are never read. The private members of
+// traits and enums are not checked, the classes using the traits can reference them
+func UnusedMemberDiagnostics(ctx ResolveContext) []protocol.Diagnostic {
+	defer util.TimeTrack(time.Now(), "UnusedMemberDiagnostics")
+	doc := ctx.document
+	q := ctx.query
+	create := func(r protocol.Range, code string, message string) protocol.Diagnostic {
+		return newDiagnostic(code, r, message)
+	}
+	diagnostics := []protocol.Diagnostic{}
+	unusedParams := doc.unusedParameters()
+	paramsDiagnostics := func(params []*Parameter) {
+		for _, param := range params {
+			if _, ok := unusedParams[param.varLocation.Range]; !ok ||
+				strings.HasPrefix(param.Name, "$_") || param.IsPromoted() {
+				continue
+			}
+			diagnostics = append(diagnostics, create(param.varLocation.Range, CodeUnusedParameter,
+				param.Name+" is declared but its value is never read."))
+		}
+	}
+	tr := newTraverser()
+	tr.traverseDocument(doc, func(_ *traverser, s Symbol, spine []Symbol) {
+		switch v := s.(type) {
+		case *Class:
+			for _, member := range q.unusedPrivateMembers(ctx, v) {
+				diagnostics = append(diagnostics, create(member.ReferenceLocation().Range, CodeUnusedPrivateMember,
+					privateMemberLabel(member)+" is declared but never used."))
+			}
+		case *Function:
+			if !v.usesFuncGetArgs {
+				paramsDiagnostics(v.Params)
+			}
+		case *Method:
+			if len(spine) > 1 {
+				if _, ok := spine[len(spine)-2].(*Interface); ok {
+					break
+				}
+			}
+			if v.ClassModifier == Abstract || v.usesFuncGetArgs || q.isOverride(v) {
+				break
+			}
+			paramsDiagnostics(v.Params)
+		case *AnonymousFunction:
+			paramsDiagnostics(v.Params)
+		}
+	})
+	return diagnostics
+}
+
+func privateMemberLabel(member SymbolReference) string {
+	switch v := member.(type) {
+	case *Method:
+		return "Private method " + v.Scope.GetOriginal() + "::" + v.Name + "()"
+	case *Property:
+		return "Private property " + v.Scope.GetOriginal() + "::" + v.Name
+	case *ClassConst:
+		return "Private constant " + v.Scope.GetOriginal() + "::" + v.Name
+	}
+	return member.ReferenceFQN()
+}
+
+// isOverride checks whether the method is also declared by the parent classes
+// or the interfaces, so that its parameters are required by their signatures
+func (q *Query) isOverride(method *Method) bool {
+	scope := method.Scope.GetFQN()
+	for _, class := range q.GetClasses(scope) {
+		for _, m := range q.GetClassMethods(class, method.Name, nil).Methods {
+			if m.Method != nil && !strings.EqualFold(m.Method.Scope.GetFQN(), scope) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unusedPrivateMembers returns the private members of the class which are not
+// referenced in the class, its traits or mentioned by name in a string, e.g.
+// callables like [$this, 'method']
+func (q *Query) unusedPrivateMembers(ctx ResolveContext, class *Class) []SymbolReference {
+	doc := ctx.document
+	refs := map[string]struct{}{}
+	TraverseSymbol(class, func(s Symbol) {
+		if h, ok := s.(HasTypesHasScope); ok && isAccessOfClass(ctx, h, class) {
+			refs[strings.ToLower(h.MemberName())] = struct{}{}
+		}
+	}, nil)
+	var traits []*Trait
+	for _, use := range class.Use {
+		if !use.IsEmpty() {
+			traits = append(traits, q.GetTraits(use.GetFQN())...)
+		}
+	}
+	start := doc.OffsetAtPosition(class.Location.Range.Start)
+	end := doc.OffsetAtPosition(class.Location.Range.End)
+	text := doc.text[start:end]
+	isReferenced := func(ref string, name string) bool {
+		if _, ok := refs[strings.ToLower(ref)]; ok {
+			return true
+		}
+		if bytes.Contains(text, []byte("'"+name+"'")) || bytes.Contains(text, []byte("\""+name+"\"")) {
+			return true
+		}
+		for _, trait := range traits {
+			for _, r := range newReferenceEntry().search(q.store, trait.location.URI, "."+ref) {
+				if protocol.IsInRange(r.Start, trait.location.Range) == 0 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	members := []SymbolReference{}
+	for _, child := range class.GetChildren() {
+		switch v := child.(type) {
+		case *Method:
+			if v.VisibilityModifier == Private && !strings.HasPrefix(v.Name, "__") && !isReferenced(v.Name+"()", v.Name) {
+				members = append(members, v)
+			}
+		case *Property:
+			// The property accesses in some expressions are not analysed,
+			// e.g. $this->r ** 2, so any ->name is assumed to be one
+			name := strings.TrimPrefix(v.Name, "$")
+			if v.VisibilityModifier == Private && !isReferenced(v.Name, name) && !mentionsProperty(text, name) {
+				members = append(members, v)
+			}
+		case *ClassConst:
+			if v.VisibilityModifier == Private && !isReferenced(v.Name, v.Name) {
+				members = append(members, v)
+			}
+		}
+	}
+	return members
+}
+
+// mentionsProperty checks whether ->name is in the text
+func mentionsProperty(text []byte, name string) bool {
+	arrow := []byte("->" + name)
+	for i := bytes.Index(text, arrow); i >= 0; {
+		end := i + len(arrow)
+		if end >= len(text) || !isIdentifierByte(text[end]) {
+			return true
+		}
+		next := bytes.Index(text[end:], arrow)
+		if next < 0 {
+			break
+		}
+		i = end + next
+	}
+	return false
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// isAccessOfClass checks whether the access can be of the members of the class,
+// accesses of which the scope cannot be resolved are assumed to be
+func isAccessOfClass(ctx ResolveContext, access HasTypesHasScope, class *Class) bool {
+	a, ok := access.(MemberAccess)
+	if !ok || IsNameRelative(a.ScopeName()) {
+		return true
+	}
+	scope, ok := access.(interface {
+		ResolveAndGetScope(ResolveContext) TypeComposite
+	})
+	if !ok {
+		return true
+	}
+	types := scope.ResolveAndGetScope(ctx).Resolve()
+	if len(types) == 0 {
+		return true
+	}
+	fqn := class.Name.GetFQN()
+	for _, t := range types {
+		if strings.EqualFold(t.GetFQN(), fqn) {
+			return true
+		}
+	}
+	return false
+}
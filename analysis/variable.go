@@ -3,8 +3,10 @@ package analysis
 import (
 	"sort"
 
+	"github.com/john-nguyen09/go-phpparser/lexer"
 	"github.com/john-nguyen09/go-phpparser/phrase"
 	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
 )
 
 // Variable represents a reference to the variable
@@ -13,6 +15,7 @@ type Variable struct {
 	description        string
 	canReferenceGlobal bool
 	hasResolved        bool
+	isParameter        bool
 }
 
 func newVariableExpression(a analyser, document *Document, node *phrase.Phrase) (HasTypes, bool) {
@@ -20,6 +23,9 @@ func newVariableExpression(a analyser, document *Document, node *phrase.Phrase)
 }
 
 func newVariable(a analyser, document *Document, node *phrase.Phrase, isDeclaration bool) (*Variable, bool) {
+	if isVariableVariable(node) {
+		document.getCurrentVariableTable().setDynamic()
+	}
 	variable := newVariableWithoutPushing(document, node)
 	document.pushVariable(a, variable, variable.GetLocation().Range.End, isDeclaration)
 	return variable, true
@@ -32,6 +38,10 @@ func newVariableWithoutPushing(document *Document, node *phrase.Phrase) *Variabl
 			Location: document.GetNodeLocation(node),
 		},
 	}
+	if node.Type == phrase.AnonymousFunctionUseVariable {
+		// The name of use (&$x) excludes the ampersand
+		variable.Name = document.getTokenText(util.LastToken(node))
+	}
 	phpDoc := document.getValidPhpDoc(variable.Location)
 	if phpDoc != nil {
 		variable.applyPhpDoc(document, *phpDoc)
@@ -106,6 +116,15 @@ func (s *Variable) GetName() string {
 	return s.Name
 }
 
+// isVariableVariable checks whether the variable is named by another expression, e.g. $$name
+func isVariableVariable(node *phrase.Phrase) bool {
+	if node.Type != phrase.SimpleVariable || len(node.Children) == 0 {
+		return false
+	}
+	token, ok := node.Children[0].(*lexer.Token)
+	return ok && token.Type == lexer.Dollar
+}
+
 type contextualVariable struct {
 	v             *Variable
 	start         protocol.Position
@@ -134,6 +153,15 @@ type VariableTable struct {
 	globalDeclares map[string]bool
 	level          int
 	children       []*VariableTable
+	// references are the variables bound by reference, e.g. by-ref parameters
+	// and use (&$x), their values are read outside of the scope
+	references map[string]struct{}
+	// isDynamic is true if the variables of the scope can be read by name,
+	// e.g. extract(), get_defined_vars() or $$name
+	isDynamic bool
+	// arguments are the positions of the variables passed as arguments,
+	// the callees can take them by reference
+	arguments map[string][]protocol.Position
 }
 
 func newVariableTable(locationRange protocol.Range, level int) *VariableTable {
@@ -142,6 +170,8 @@ func newVariableTable(locationRange protocol.Range, level int) *VariableTable {
 		variables:      map[string][]contextualVariable{},
 		globalDeclares: map[string]bool{},
 		level:          level,
+		references:     map[string]struct{}{},
+		arguments:      map[string][]protocol.Position{},
 	}
 }
 
@@ -188,6 +218,22 @@ func (vt *VariableTable) get(name string, pos protocol.Position) *Variable {
 	return nil
 }
 
+// markUsed marks the declarations of the variable before the position as used
+func (vt *VariableTable) markUsed(name string, pos protocol.Position) {
+	ctxVars, ok := vt.variables[name]
+	if !ok {
+		return
+	}
+	for i, ctxVar := range ctxVars {
+		if protocol.ComparePos(pos, ctxVar.v.GetLocation().Range.Start) <= 0 {
+			break
+		}
+		if ctxVar.isDeclaration {
+			ctxVars[i].isUsed = true
+		}
+	}
+}
+
 func (vt *VariableTable) canReferenceGlobal(name string) bool {
 	if _, ok := vt.globalDeclares[name]; ok {
 		return true
@@ -217,13 +263,33 @@ func (vt *VariableTable) GetVariables(pos protocol.Position) []*Variable {
 	return results
 }
 
+func (vt *VariableTable) setReference(name string) {
+	vt.references[name] = struct{}{}
+}
+
+func (vt *VariableTable) addArgument(name string, pos protocol.Position) {
+	vt.arguments[name] = append(vt.arguments[name], pos)
+}
+
+func (vt *VariableTable) setDynamic() {
+	vt.isDynamic = true
+}
+
 func (vt *VariableTable) addChild(child *VariableTable) {
 	vt.children = append(vt.children, child)
 }
 
-func (vt *VariableTable) unusedVariables() []*Variable {
+// unusedVariables returns the declarations which are never read of the variables
+// for which include returns true
+func (vt *VariableTable) unusedVariables(include func(*VariableTable, string) bool) []*Variable {
 	results := []*Variable{}
-	for _, ctxVars := range vt.variables {
+	if vt.isDynamic {
+		return results
+	}
+	for name, ctxVars := range vt.variables {
+		if _, ok := vt.references[name]; ok || !include(vt, name) {
+			continue
+		}
 		for _, ctxVar := range ctxVars {
 			if ctxVar.isDeclaration && !ctxVar.isUsed {
 				results = append(results, ctxVar.v)
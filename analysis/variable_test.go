@@ -173,6 +173,49 @@ function thisIsAFunction()
 			}},
 		}, results)
 	})
+
+	t.Run("TestDynamicVariableReads", func(t *testing.T) {
+		doc4 := NewDocument("test4", []byte(`<?php
+function render()
+{
+	$title = 'Home';
+	$items = [];
+	$unused = 1;
+	return view('home', compact('title', ['items']));
+}
+
+function extracted(array $data)
+{
+	$before = 1;
+	extract($data);
+}
+
+function variableVariable($name)
+{
+	$value = 1;
+	return $$name;
+}
+
+function fill(array &$output)
+{
+	$output = [1];
+}
+
+function collect()
+{
+	$results = [];
+	$fn = function ($x) use (&$results) {
+		$results = $x;
+	};
+	return $fn;
+}`))
+		doc4.Load()
+		results := []string{}
+		for _, unusedVar := range doc4.UnusedVariables() {
+			results = append(results, unusedVar.Name)
+		}
+		assert.Equal(t, []string{"$unused"}, results)
+	})
 }
 
 func TestInterpolatedVariables(t *testing.T) {
@@ -0,0 +1,61 @@
+<?php
+
+namespace App;
+
+interface Shape {}
+
+abstract class Model
+{
+    private $secret;
+    protected $table;
+    public $name;
+
+    private function hidden() {}
+    protected function guarded() {}
+    public function visible() {}
+    public static function create() {}
+
+    public function copy(Model $other)
+    {
+        $other->hidden();
+        $other->secret;
+        return new static();
+    }
+}
+
+class User extends Model
+{
+    public function save()
+    {
+        $this->guarded();
+        $this->hidden();
+        parent::visible();
+        Model::visible();
+        $this->table;
+    }
+
+    public static function find()
+    {
+        $this->name;
+        self::visible();
+        static::create();
+    }
+}
+
+class Report
+{
+    public function render(User $user)
+    {
+        $user->guarded();
+        $user->table;
+        $user->visible();
+    }
+}
+
+$user = new User();
+$user->hidden();
+$user->secret;
+$user->name;
+User::visible();
+new Model();
+new Shape();
@@ -0,0 +1,37 @@
+<?php
+
+namespace App;
+
+function greet(string $name, string $greeting = 'Hello') {}
+function sum(int ...$numbers) {}
+function legacy() { return func_get_args(); }
+
+class Mailer
+{
+    public function __construct(string $host, int $port = 25) {}
+    public function send(string $to, string $subject, string $body = '') {}
+    public static function create(array $options) {}
+}
+
+class SmtpMailer
+{
+    public function send(string $to) {}
+}
+
+greet();
+greet('Ann', 'Hi', 'extra');
+greet(name: 'Ann', greeting: 'Hi');
+greet(greeting: 'Hi');
+greet(name: 'Ann', title: 'Dr');
+greet(name: 'Ann', name: 'Bob');
+greet('Ann', name: 'Bob');
+greet(name: 'Ann', 'Hi');
+sum(1, 2, 3, 4);
+legacy(1, 2, 3);
+greet(...$args);
+$mailer = new Mailer();
+$mailer->send('a@b.c');
+Mailer::create([], 1);
+/** @var Mailer|SmtpMailer $anyMailer */
+$anyMailer->send('a@b.c');
+new Mailer('localhost', port: 587);
@@ -0,0 +1,31 @@
+<?php
+
+namespace App\Controller;
+
+use App\Attributes\Route;
+use Attribute;
+
+#[Attribute(Attribute::TARGET_METHOD | Attribute::IS_REPEATABLE)]
+class Cache
+{
+    public function __construct(public int $maxAge = 0) {}
+}
+
+#[Route('/users')]
+final class UserController
+{
+    /**
+     * Lists the users
+     */
+    #[Route('/', name: 'user_index', methods: ['GET']), Cache(maxAge: 60)]
+    public function index(#[\SensitiveParameter] string $password, int $page = 1): array
+    {
+        return [];
+    }
+
+    #[Route(
+        '/{id}',
+        name: 'user_show'
+    )]
+    public function show(int $id) {}
+}
@@ -0,0 +1,15 @@
+<?php
+
+function bind(&$value) {}
+function show($value) {}
+
+function prepare()
+{
+    $id = 1;
+    bind($id);
+    $id = 2;
+
+    $name = 'a';
+    show($name);
+    $name = 'b';
+}
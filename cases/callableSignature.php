@@ -0,0 +1,36 @@
+<?php
+
+class User
+{
+    public $name;
+}
+
+class Collection
+{
+    /**
+     * @param Closure(User, int): void $callback Called for each user
+     * @return static
+     */
+    public function each($callback)
+    {
+    }
+
+    /**
+     * @return callable(int|string $key): User|null
+     */
+    public function finder()
+    {
+    }
+}
+
+/** @var User[] $users */
+$users = [];
+$names = array_map(fn($u) => $u->name, $users);
+$collection = new Collection();
+$collection->each(function ($item, $index) {
+    $item->name;
+});
+$fn = function (User $user, int $times = 1): string {
+};
+$fn($users[0]);
+$found = $collection->finder();
@@ -0,0 +1,94 @@
+<?php
+
+class ValidationException extends Exception {}
+class StorageException extends Exception {}
+
+/**
+ * @return never
+ */
+function abort(string $message)
+{
+    throw new RuntimeException($message);
+}
+
+/**
+ * @throws ValidationException
+ */
+function validate(array $data)
+{
+}
+
+function process(array $data)
+{
+    if (empty($data)) {
+        return false;
+        echo 'never';
+    }
+    foreach ($data as $item) {
+        if ($item === null) {
+            continue;
+            $item = 0;
+        }
+    }
+    abort('done');
+    echo 'after abort';
+
+    function hoisted() {}
+}
+
+function branches($value)
+{
+    if (false) {
+        echo 'disabled';
+    }
+    if (true) {
+        return 1;
+    } elseif ($value) {
+        return 2;
+    } else {
+        return 3;
+    }
+}
+
+function exceptions(array $data)
+{
+    try {
+        validate($data);
+    } catch (ValidationException $e) {
+        return false;
+    } catch (StorageException $e) {
+        return false;
+    } catch (InvalidArgumentException $e) {
+        return false;
+    }
+    try {
+        validate($data);
+        throw new StorageException();
+    } catch (StorageException | ValidationException $e) {
+        exit(1);
+    }
+}
+
+function persist(array $data)
+{
+}
+
+function undocumented(array $data)
+{
+    try {
+        validate($data);
+        persist($data);
+    } catch (StorageException $e) {
+        return false;
+    }
+}
+
+function anonymousClasses()
+{
+    try {
+        $logger = new class {};
+        throw new class extends \Exception {};
+    } catch (StorageException $e) {
+        return false;
+    }
+}
@@ -0,0 +1,22 @@
+<?php
+
+interface Jsonable
+{
+    public function toJson();
+}
+
+interface Renderable extends Jsonable
+{
+}
+
+trait Serialises
+{
+    public function serialise()
+    {
+    }
+}
+
+class Report implements Jsonable
+{
+    use Serialises;
+}
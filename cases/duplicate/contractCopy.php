@@ -0,0 +1,18 @@
+<?php
+
+interface Legacy
+{
+    public function legacy();
+}
+
+interface Jsonable extends \Legacy
+{
+    public function toJson($options);
+}
+
+trait Serialises
+{
+    public function serialise($format)
+    {
+    }
+}
@@ -0,0 +1,15 @@
+<?php
+
+class Collection
+{
+}
+
+interface Arrayable
+{
+}
+
+function helper()
+{
+}
+
+const VERSION = '2.0';
@@ -0,0 +1,17 @@
+<?php
+
+if (!class_exists('Collection')) {
+    class Collection
+    {
+    }
+}
+
+if (!function_exists('helper')) {
+    function helper()
+    {
+    }
+}
+
+if (!defined('APP_DEBUG')) {
+    define('APP_DEBUG', false);
+}
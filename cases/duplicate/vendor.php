@@ -0,0 +1,9 @@
+<?php
+
+class Collection
+{
+}
+
+function helper()
+{
+}
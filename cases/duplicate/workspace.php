@@ -0,0 +1,20 @@
+<?php
+
+class Collection
+{
+    public function all()
+    {
+    }
+}
+
+interface Arrayable
+{
+}
+
+function helper()
+{
+}
+
+const VERSION = '1.0';
+
+define('APP_DEBUG', true);
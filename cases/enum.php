@@ -0,0 +1,44 @@
+<?php
+namespace App\Enums;
+
+interface HasLabel
+{
+    public function label(): string;
+}
+
+/**
+ * The status of an order
+ */
+enum Status: string implements HasLabel
+{
+    case Pending = 'pending';
+    case Shipped = 'shipped';
+
+    const DEFAULT = self::Pending;
+
+    public function label(): string
+    {
+        return ucfirst($this->value);
+    }
+}
+
+enum Suit
+{
+    case Hearts;
+    case Spades;
+
+    public function color(): string
+    {
+        switch ($this) {
+            case self::Hearts:
+                return 'Red';
+        }
+        return 'Black';
+    }
+}
+
+$status = Status::Shipped;
+$status->value;
+$status->label();
+Status::from('pending');
+Suit::cases();
@@ -0,0 +1,46 @@
+<?php
+
+namespace Illuminate\Database\Eloquent;
+
+class Builder
+{
+    const ASC = 'asc';
+    public $wheres = [];
+    private $bindings = [];
+
+    /**
+     * @return static
+     */
+    public function where($column, $value)
+    {
+    }
+
+    private function addBinding($value)
+    {
+    }
+}
+
+namespace App;
+
+use Illuminate\Database\Eloquent\Builder;
+
+/**
+ * @mixin Builder
+ * @mixin User
+ */
+class Model
+{
+    public function save()
+    {
+    }
+}
+
+/**
+ * @mixin Model
+ */
+class User extends Model
+{
+}
+
+$user = new User();
+$user->where('name', 'foo');
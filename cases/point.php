@@ -0,0 +1,23 @@
+<?php
+namespace App;
+
+class Point
+{
+    public $x = 0;
+}
+
+class Point3D extends Point
+{
+}
+
+class Size
+{
+    public $x = 0;
+}
+
+function move(Point $point, Size $size, Point3D $point3D, $unknown)
+{
+    $point->x = $size->x;
+    $point3D->x = 1;
+    return $unknown->x;
+}
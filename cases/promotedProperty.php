@@ -0,0 +1,26 @@
+<?php
+
+namespace App\Services;
+
+use App\Repositories\UserRepo;
+
+class UserService
+{
+    /**
+     * @param UserRepo $repo The user repository
+     * @param int $limit
+     */
+    public function __construct(
+        private readonly UserRepo $repo,
+        protected int $limit = 10,
+        readonly string $name = 'users',
+        $notPromoted = null
+    ) {
+    }
+
+    public function find()
+    {
+        $this->repo->find();
+        return $this->limit;
+    }
+}
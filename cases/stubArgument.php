@@ -0,0 +1,3 @@
+<?php
+
+function pad($input, $pad_length, $pad_string = " ", $pad_type = STR_PAD_RIGHT) {}
@@ -0,0 +1,52 @@
+<?php
+// @phpintel-ignore-file unreachable-code
+
+function nextLine()
+{
+    // @phpintel-ignore-next-line undefined-variable
+    echo $first;
+    echo $second;
+    /* @phpintel-ignore-next-line unused-variable because it is kept for debugging */
+    $unused = $third;
+    # @phpintel-ignore-next-line
+    $all = $fourth;
+    return;
+    echo 'unreachable';
+}
+
+class Report
+{
+    /**
+     * @phpintel-ignore undefined-variable, unused-parameter
+     */
+    public function render($format)
+    {
+        return $template;
+    }
+
+    /** @phpintel-ignore unused-parameter */
+    public function export($format)
+    {
+        return $output;
+    }
+}
+
+/** @phpintel-ignore */
+function ignored($value)
+{
+    echo $missing;
+}
+
+function withReason()
+{
+    // @phpintel-ignore-next-line undefined-variable because it is set by the template
+    echo $title;
+}
+
+function misspelled()
+{
+    // @phpintel-ignore-next-line undefined-varible
+    echo $typo;
+    // @phpintel-ignore-next-line set by the template
+    echo $reasonOnly;
+}
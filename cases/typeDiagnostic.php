@@ -0,0 +1,116 @@
+<?php
+declare(strict_types=1);
+
+namespace App;
+
+interface Shape {}
+class Circle implements Shape {}
+class Square extends Circle {}
+class Report {}
+
+function scale(int $factor, ?Shape $shape = null) {}
+function draw(Shape $shape, string $label = '') {}
+function note(string ...$messages) {}
+
+scale('2');
+scale(2, new Square());
+draw(new Report());
+draw(new Circle(), 1.5);
+draw(shape: new Report());
+note('a', 'b', 3);
+scale(2, null);
+$report = new Report();
+draw($report);
+scale($unknown);
+
+function nothing(): void
+{
+    return 1;
+}
+
+function total(): int
+{
+    if (rand()) {
+        return 1;
+    }
+}
+
+function maybe(): ?int
+{
+}
+
+function choose(int $a): string
+{
+    if ($a > 0) {
+        return 'positive';
+    } elseif ($a < 0) {
+        return 'negative';
+    } else {
+        throw new \Exception();
+    }
+}
+
+function pick(int $a): string
+{
+    switch ($a) {
+        case 1:
+            return 'one';
+        default:
+            return 'other';
+    }
+}
+
+function loop(): int
+{
+    while (true) {
+        if (rand()) {
+            return 1;
+        }
+    }
+}
+
+function generate(): iterable
+{
+    yield 1;
+}
+
+function blank(): string
+{
+    return;
+}
+
+abstract class Base
+{
+    abstract public function name(): string;
+
+    public function describe(): string
+    {
+        $fn = function (): int {
+            return 1;
+        };
+    }
+}
+
+function frame(?Shape $shape) {}
+
+frame(null);
+frame(new Report());
+
+interface Drawable extends Shape {}
+class Sketch implements Drawable {}
+
+draw(new Sketch());
+
+function values(array $values) {}
+
+function sum(int ...$numbers): int
+{
+    values($numbers);
+    switch (count($numbers)) {
+        case 0:
+            return 0;
+            break;
+        default:
+            return array_sum($numbers);
+    }
+}
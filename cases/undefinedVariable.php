@@ -0,0 +1,121 @@
+<?php
+
+$globalValue = 1;
+echo $notChecked;
+
+function total(array $items, $discount)
+{
+    $total = 0;
+    foreach ($items as $key => [$price, $quantity]) {
+        $total += $price * $quantity;
+    }
+    return $totla - $discount;
+}
+
+function branches($flag)
+{
+    if ($flag) {
+        $message = 'yes';
+    } else {
+        $message = 'no';
+    }
+    if ($flag > 1) {
+        $partial = 1;
+    }
+    echo $message, $partial;
+    switch ($flag) {
+        case 1:
+            $label = 'one';
+            break;
+        default:
+            return;
+    }
+    echo $label;
+}
+
+function outParams($subject)
+{
+    global $config;
+    static $count = 0;
+    if (preg_match('/a/', $subject, $matches)) {
+        echo $matches[0];
+    }
+    unknownFunction($unknownOut);
+    list($first, $second) = explode(',', $subject);
+    try {
+        $result = $first . $second . $config . $count . $unknownOut;
+    } catch (Exception $e) {
+        echo $e->getMessage(), $result;
+    }
+    echo isset($missing) ? 1 : 2;
+    echo $maybe ?? 'default';
+    echo $_GET['id'], $GLOBALS['globalValue'];
+}
+
+function closures($value)
+{
+    $fn = function ($x) use ($value, &$collected) {
+        $collected[] = $x + $value + $outer;
+    };
+    $arrow = fn($y) => $y + $value + $notCaptured;
+    echo $collected;
+    return [$fn, $arrow];
+}
+
+function suppressed(array $data)
+{
+    echo $before;
+    extract($data);
+    echo $after;
+}
+
+class Counter
+{
+    private $count = 0;
+
+    public function increment($by)
+    {
+        $this->count += $by + $step;
+        for ($i = 0; $i < 10; $i++) {
+            $last = $i;
+        }
+        echo $last;
+    }
+}
+
+function parse($pattern, $subject, &$parts)
+{
+    return true;
+}
+
+function literalArguments($subject)
+{
+    if (parse('/a/', $subject, $parts)) {
+        echo $parts[0];
+    }
+}
+
+function anonymousClass()
+{
+    $logger = new class {};
+    $logger = new class($level) {};
+    echo $message;
+}
+
+function guarded(array $rows)
+{
+    if (isset($cached['rows']) && !empty($limit)) {
+        return $cached['rows'][$limit];
+    } elseif (isset($fallback)) {
+        return $fallback;
+    }
+    while ($row = next($rows)) {
+        echo $row;
+    }
+    if ($first = reset($rows)) {
+        echo $first;
+    }
+    if (!isset($absent)) {
+        echo $absent;
+    }
+}
@@ -0,0 +1,111 @@
+<?php
+
+namespace App;
+
+interface Handler
+{
+    public function handle($request, $next);
+}
+
+abstract class Base
+{
+    abstract protected function boot($app);
+
+    public function render($view, $data)
+    {
+        return $view;
+    }
+}
+
+trait Logs
+{
+    public function log()
+    {
+        return $this->channel;
+    }
+}
+
+class Service extends Base implements Handler
+{
+    use Logs;
+
+    private const USED = 1;
+    private const UNUSED = 2;
+    private $channel;
+    private $cache;
+    private static $instances;
+    private $count;
+
+    public function __construct(private $config, $options)
+    {
+        static::$instances = self::USED;
+        $this->count++;
+    }
+
+    public function handle($request, $next)
+    {
+        return $this->helper() + $this->Format();
+    }
+
+    protected function boot($app) {}
+
+    public function render($view, $data)
+    {
+        return array_map([$this, 'callback'], []);
+    }
+
+    public function compute($a, $_b, $c)
+    {
+        return $c;
+    }
+
+    private function helper() {}
+    private function format() {}
+    private function callback() {}
+    private function forgotten() {}
+}
+
+function legacy($first)
+{
+    return func_get_args();
+}
+
+function unused($first, $second)
+{
+    return $second;
+}
+
+array_map(function ($item, $key) {
+    return $item;
+}, []);
+
+class Exporter
+{
+    public function export() {}
+}
+
+class Report
+{
+    private function export() {}
+    private function total() {}
+
+    public function run(Exporter $exporter, Report $other)
+    {
+        return $exporter->export() + $other->total();
+    }
+}
+
+class Circle
+{
+    public function __construct(private float $r, private float $scale) {}
+
+    public function area()
+    {
+        return M_PI * $this->r ** 2;
+    }
+}
+
+trait Greets
+{
+    private function greeting() {}
+}
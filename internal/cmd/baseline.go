@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+// runBaseline generates or updates the baseline with the current diagnostics,
+// the entries which are no longer reported are dropped
+func runBaseline(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	var options checkOptions
+	flags := flag.NewFlagSet("baseline", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	output := flags.String("output", "", "write the baseline to `file`, defaults to "+analysis.BaselineFileName+" in the folder")
+	options.register(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: phpintel baseline [flags] [folder]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	path := *output
+	if path == "" {
+		path = filepath.Join(root, analysis.BaselineFileName)
+	}
+
+	baseline := analysis.NewBaseline()
+	files, err := checkFolder(ctx, root, options, func(file phpFile, document *analysis.Document, diagnostics []protocol.Diagnostic) []protocol.Diagnostic {
+		baseline.Add(file.path, document, diagnostics)
+		return diagnostics
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel baseline: %v\n", err)
+		return exitFailure
+	}
+	if err := baseline.Write(path); err != nil {
+		fmt.Fprintf(stderr, "phpintel baseline: %v\n", err)
+		return exitFailure
+	}
+	count := 0
+	for _, file := range files {
+		count += len(file.diagnostics)
+	}
+	fmt.Fprintf(stdout, "Recorded %d diagnostics of %d files in %s\n", count, len(files), path)
+	return exitOK
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineCommand(t *testing.T) {
+	dir := newCheckFolder(t)
+	ctx := context.Background()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, Run(ctx, []string{"baseline", "-no-cache", dir}, &stdout, &stderr), stderr.String())
+	assert.FileExists(t, filepath.Join(dir, analysis.BaselineFileName))
+
+	check := func() []jsonDiagnostic {
+		stdout.Reset()
+		code := Run(ctx, []string{"check", "-no-cache", "-format", "json", "-severity", "hint", "-fail-on", "warning", dir}, &stdout, &stderr)
+		var results []jsonDiagnostic
+		assert.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+		if len(results) == 0 {
+			assert.Equal(t, exitOK, code)
+		}
+		return results
+	}
+	assert.Empty(t, check())
+
+	path := filepath.Join(dir, "src/undefinedVariable.php")
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(path, bytes.Replace(data, []byte("$totla"), []byte("$total"), 1), os.ModePerm))
+	assert.NoError(t, os.Remove(filepath.Join(dir, "src/copy.php")))
+	results := check()
+	files := map[string][]string{}
+	for _, result := range results {
+		assert.Equal(t, analysis.CodeStaleBaselineEntry, result.Code)
+		files[result.File] = append(files[result.File], result.Message)
+	}
+	assert.Equal(t, []string{
+		"Baseline entry is no longer reported and can be removed: $total is declared but its value is never read. [unused-variable]",
+		"Baseline entry is no longer reported and can be removed: $total is declared but its value is never read. [unused-variable]",
+		"Baseline entry is no longer reported and can be removed: Undefined variable $totla. [undefined-variable]",
+	}, files["src/undefinedVariable.php"])
+	assert.Len(t, files["src/copy.php"], 4)
+	assert.Len(t, files["src/workspace.php"], 4)
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+// fileDiagnostics are the diagnostics of a file in the check report
+type fileDiagnostics struct {
+	phpFile
+	diagnostics []protocol.Diagnostic
+}
+
+// checkReport is the result of phpintel check
+type checkReport struct {
+	version string
+	files   []fileDiagnostics
+}
+
+// checkOptions are the flags shared by the commands which run the diagnostics
+type checkOptions struct {
+	disable       string
+	noCache       bool
+	includeVendor bool
+	verbose       bool
+}
+
+func (o *checkOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.disable, "disable", "", "comma separated diagnostic `codes` to disable")
+	flags.BoolVar(&o.noCache, "no-cache", false, "index into a temporary storage instead of the language server's cache")
+	flags.BoolVar(&o.includeVendor, "include-vendor", false, "also check the files in vendor")
+	flags.BoolVar(&o.verbose, "verbose", false, "print the logs of indexing to stderr")
+}
+
+func (o *checkOptions) disabledCodes() map[string]struct{} {
+	disabled := map[string]struct{}{}
+	for _, code := range strings.Split(o.disable, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			disabled[code] = struct{}{}
+		}
+	}
+	return disabled
+}
+
+// fileVisitor receives the diagnostics of every checked file and returns
+// the diagnostics which are kept in the results
+type fileVisitor func(file phpFile, document *analysis.Document, diagnostics []protocol.Diagnostic) []protocol.Diagnostic
+
+// checkFolder indexes the folder and runs the same diagnostics as the
+// language server on its files, the results are sorted by the files
+func checkFolder(ctx context.Context, root string, o checkOptions, visit fileVisitor) ([]fileDiagnostics, error) {
+	if !o.verbose {
+		log.SetOutput(ioutil.Discard)
+	}
+	w, err := openWorkspace(ctx, root, o.noCache)
+	if err != nil {
+		return nil, err
+	}
+	defer w.close()
+	if _, err := w.index(ctx); err != nil {
+		return nil, err
+	}
+	files, err := w.phpFiles(ctx, o.includeVendor)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].path < files[j].path
+	})
+	disabled := o.disabledCodes()
+	return checkFiles(ctx, w.store, files, func(file phpFile, document *analysis.Document, diagnostics []protocol.Diagnostic) []protocol.Diagnostic {
+		enabled := []protocol.Diagnostic{}
+		for _, diagnostic := range diagnostics {
+			if _, ok := disabled[analysis.DiagnosticCode(diagnostic)]; !ok {
+				enabled = append(enabled, diagnostic)
+			}
+		}
+		return visit(file, document, enabled)
+	}), nil
+}
+
+func runCheck(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	var options checkOptions
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output `format`: text, json, checkstyle or sarif")
+	output := flags.String("output", "", "write the report to `file` instead of stdout")
+	minSeverity := flags.String("severity", "information", "report diagnostics of this `severity` or more severe: error, warning, information or hint")
+	failOn := flags.String("fail-on", "error", "exit with 1 if there is a diagnostic of this `severity` or more severe, never to always exit with 0")
+	baselinePath := flags.String("baseline", "", "hide the diagnostics recorded in the baseline `file`, defaults to "+analysis.BaselineFileName+" in the folder if it exists")
+	options.register(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: phpintel check [flags] [folder]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	writeReport, ok := reportWriters[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "phpintel check: unknown format %q\n", *format)
+		return exitUsage
+	}
+	threshold, ok := analysis.ParseSeverity(*minSeverity)
+	if !ok {
+		fmt.Fprintf(stderr, "phpintel check: unknown severity %q\n", *minSeverity)
+		return exitUsage
+	}
+	failThreshold, ok := analysis.ParseSeverity(*failOn)
+	if !ok && *failOn != "never" {
+		fmt.Fprintf(stderr, "phpintel check: unknown severity %q\n", *failOn)
+		return exitUsage
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	baseline, err := readBaseline(root, *baselinePath)
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel check: %v\n", err)
+		return exitFailure
+	}
+
+	checkedFiles := map[string]struct{}{}
+	var mu sync.Mutex
+	files, err := checkFolder(ctx, root, options, func(file phpFile, document *analysis.Document, diagnostics []protocol.Diagnostic) []protocol.Diagnostic {
+		if baseline == nil {
+			return diagnostics
+		}
+		mu.Lock()
+		checkedFiles[file.path] = struct{}{}
+		mu.Unlock()
+		diagnostics, stale := baseline.Filter(file.path, document, diagnostics)
+		return append(diagnostics, analysis.StaleDiagnostics(stale)...)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel check: %v\n", err)
+		return exitFailure
+	}
+	if baseline != nil {
+		// the entries of the deleted or excluded files are stale as well
+		for _, path := range baseline.Files() {
+			if _, ok := checkedFiles[path]; !ok {
+				files = append(files, fileDiagnostics{
+					phpFile:     phpFile{path: path},
+					diagnostics: analysis.StaleDiagnostics(baseline.Entries(path)),
+				})
+			}
+		}
+	}
+	report := checkReport{
+		version: protocol.GetVersion(ctx),
+		files:   files,
+	}
+	failed := false
+	for i := range report.files {
+		diagnostics := []protocol.Diagnostic{}
+		for _, diagnostic := range report.files[i].diagnostics {
+			if *failOn != "never" && diagnostic.Severity <= failThreshold {
+				failed = true
+			}
+			if diagnostic.Severity > threshold {
+				continue
+			}
+			diagnostics = append(diagnostics, diagnostic)
+		}
+		report.files[i].diagnostics = diagnostics
+	}
+
+	out := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(stderr, "phpintel check: %v\n", err)
+			return exitFailure
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := writeReport(out, report); err != nil {
+		fmt.Fprintf(stderr, "phpintel check: %v\n", err)
+		return exitFailure
+	}
+	if failed {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// readBaseline reads the given baseline or the default one of the folder,
+// nil is returned if there is no baseline
+func readBaseline(root string, path string) (*analysis.Baseline, error) {
+	if path == "" {
+		path = filepath.Join(root, analysis.BaselineFileName)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil, nil
+		}
+	}
+	return analysis.ReadBaseline(path)
+}
+
+// checkFiles runs the diagnostics on the files, the results are in the
+// same order as the files
+func checkFiles(ctx context.Context, store *analysis.Store, files []phpFile, visit fileVisitor) []fileDiagnostics {
+	results := make([]fileDiagnostics, len(files))
+	jobs := make(chan int)
+	var waitGroup sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for i := range jobs {
+				results[i] = fileDiagnostics{files[i], checkFile(ctx, store, files[i], visit)}
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	waitGroup.Wait()
+	return results
+}
+
+func checkFile(ctx context.Context, store *analysis.Store, file phpFile, visit fileVisitor) []protocol.Diagnostic {
+	data, err := store.FS.ReadFile(ctx, file.uri)
+	if err != nil {
+		return []protocol.Diagnostic{{
+			Severity: protocol.SeverityError,
+			Message:  err.Error(),
+		}}
+	}
+	document := analysis.NewDocument(file.uri, data)
+	document.Load()
+	diagnostics := analysis.DocumentDiagnostics(document)
+	resolveCtx := analysis.NewResolveContext(analysis.NewQuery(store), document)
+	diagnostics = append(diagnostics, analysis.ResolvedDiagnostics(resolveCtx)...)
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i].Range.Start, diagnostics[j].Range.Start
+		return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+	})
+	return visit(file, document, diagnostics)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func copyCase(t *testing.T, dir string, src string, dst string) {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst = filepath.Join(dir, dst)
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(dst, data, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newCheckFolder(t *testing.T) string {
+	dir := t.TempDir()
+	copyCase(t, dir, "../../cases/duplicate/workspace.php", "src/workspace.php")
+	copyCase(t, dir, "../../cases/duplicate/copy.php", "src/copy.php")
+	copyCase(t, dir, "../../cases/duplicate/vendor.php", "vendor/acme/collection/vendor.php")
+	copyCase(t, dir, "../../cases/undefinedVariable.php", "src/undefinedVariable.php")
+	return dir
+}
+
+func TestCheck(t *testing.T) {
+	dir := newCheckFolder(t)
+	ctx := context.Background()
+
+	var stdout, stderr bytes.Buffer
+	code := Run(ctx, []string{"check", "-no-cache", "-format", "json", "-severity", "warning", dir}, &stdout, &stderr)
+	assert.Equal(t, exitOK, code, stderr.String())
+	var results []jsonDiagnostic
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+	files := map[string]int{}
+	for _, result := range results {
+		assert.Contains(t, []string{"error", "warning"}, result.Severity)
+		files[result.File]++
+	}
+	assert.Equal(t, map[string]int{
+		"src/copy.php":              4,
+		"src/undefinedVariable.php": 8,
+		"src/workspace.php":         4,
+	}, files)
+	assert.Contains(t, results, jsonDiagnostic{
+		File:      "src/undefinedVariable.php",
+		Line:      12,
+		Column:    12,
+		EndLine:   12,
+		EndColumn: 18,
+		Severity:  "warning",
+		Code:      "undefined-variable",
+		Message:   "Undefined variable $totla.",
+	})
+
+	stdout.Reset()
+	code = Run(ctx, []string{"check", "-no-cache", "-format", "checkstyle", "-fail-on", "warning", "-disable", "duplicate-declaration", dir}, &stdout, &stderr)
+	assert.Equal(t, exitFailure, code)
+	var checkstyle checkstyleReport
+	assert.NoError(t, xml.Unmarshal(stdout.Bytes(), &checkstyle))
+	assert.Len(t, checkstyle.Files, 1)
+	assert.Equal(t, "src/undefinedVariable.php", checkstyle.Files[0].Name)
+
+	stdout.Reset()
+	code = Run(ctx, []string{"check", "-no-cache", "-format", "sarif", "-severity", "error", dir}, &stdout, &stderr)
+	assert.Equal(t, exitOK, code)
+	var sarif sarifLog
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &sarif))
+	assert.Equal(t, "2.1.0", sarif.Version)
+	assert.Len(t, sarif.Runs, 1)
+	assert.Empty(t, sarif.Runs[0].Results)
+
+	assert.Equal(t, exitUsage, Run(ctx, []string{"check", "-format", "yaml", dir}, &stdout, &stderr))
+}
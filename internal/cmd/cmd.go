@@ -0,0 +1,138 @@
+// Package cmd implements the subcommands of phpintel which work on the same
+// index as the language server but without an editor, e.g. phpintel check
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/lsp"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// Exit codes of the subcommands
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// Run runs the subcommand named by the first argument and returns the exit code
+func Run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: phpintel <command> [arguments]")
+		return exitUsage
+	}
+	switch args[0] {
+	case "check":
+		return runCheck(ctx, args[1:], stdout, stderr)
+	case "baseline":
+		return runBaseline(ctx, args[1:], stdout, stderr)
+	case "index":
+		return runIndex(ctx, args[1:], stdout, stderr)
+	case "query":
+		return runQuery(ctx, args[1:], stdout, stderr)
+	case "lsif":
+		return runLSIF(ctx, args[1:], stdout, stderr)
+	case "tags":
+		return runTags(ctx, args[1:], stdout, stderr)
+	case "replay":
+		return runReplay(ctx, args[1:], stdout, stderr)
+	}
+	fmt.Fprintf(stderr, "phpintel: unknown command %q\n", args[0])
+	return exitUsage
+}
+
+// workspace is the store of a folder which is opened from the command line
+type workspace struct {
+	store    *analysis.Store
+	rootPath string
+	rootURI  string
+	cleanup  func()
+}
+
+// openWorkspace opens the store of the folder, the store is shared with the
+// language server unless noCache is given, then a temporary one is used
+func openWorkspace(ctx context.Context, root string, noCache bool) (*workspace, error) {
+	rootPath, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(rootPath); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+	rootURI := util.PathToURI(rootPath)
+	storagePath := lsp.StoragePath(rootURI)
+	cleanup := func() {}
+	if noCache {
+		storagePath, err = ioutil.TempDir("", "phpintel")
+		if err != nil {
+			return nil, err
+		}
+		cleanup = func() {
+			os.RemoveAll(storagePath)
+		}
+	}
+	store, err := analysis.NewStore(protocol.NewFileFS(), rootURI, storagePath)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	store.Migrate(protocol.GetVersion(ctx))
+	store.LoadStubs()
+	return &workspace{
+		store:    store,
+		rootPath: rootPath,
+		rootURI:  rootURI,
+		cleanup:  cleanup,
+	}, nil
+}
+
+func (w *workspace) close() {
+	w.store.Close()
+	w.cleanup()
+}
+
+// index brings the store up to date with the files of the folder
+func (w *workspace) index(ctx context.Context) (int, error) {
+	return lsp.IndexFolder(ctx, w.store, w.rootPath)
+}
+
+// phpFile is a PHP file of the workspace
+type phpFile struct {
+	uri  string
+	path string
+}
+
+// phpFiles lists the PHP files of the workspace with their paths relative to the root
+func (w *workspace) phpFiles(ctx context.Context, includeVendor bool) ([]phpFile, error) {
+	docs, err := w.store.FS.ListFiles(ctx, w.rootPath)
+	if err != nil {
+		return nil, err
+	}
+	files := []phpFile{}
+	for _, doc := range docs {
+		if !strings.HasSuffix(doc.URI, ".php") {
+			continue
+		}
+		uri := w.store.FS.ConvertToURI(doc.URI)
+		if !includeVendor && analysis.IsVendorURI(strings.TrimPrefix(uri, w.rootURI)) {
+			continue
+		}
+		path, err := filepath.Rel(w.rootPath, doc.URI)
+		if err != nil {
+			path = doc.URI
+		}
+		files = append(files, phpFile{uri, filepath.ToSlash(path)})
+	}
+	return files, nil
+}
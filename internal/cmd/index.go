@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp"
+)
+
+// runIndex indexes the folder into the storage of the language server so
+// it does not have to index the folder when it is opened, e.g. when
+// building a development container
+func runIndex(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("index", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	verbose := flags.Bool("verbose", false, "print the logs of indexing to stderr")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: phpintel index [flags] [folder]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if !*verbose {
+		log.SetOutput(ioutil.Discard)
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	start := time.Now()
+	w, err := openWorkspace(ctx, root, false)
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel index: %v\n", err)
+		return exitFailure
+	}
+	defer w.close()
+	count, err := w.index(ctx)
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel index: %v\n", err)
+		return exitFailure
+	}
+	fmt.Fprintf(stdout, "Indexed %d files of %s into %s in %s\n", count, w.rootPath,
+		lsp.StoragePath(w.rootURI), time.Since(start).Round(time.Millisecond))
+	return exitOK
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/john-nguyen09/phpintel/internal/lsp"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+// LSIFFileName is the default name of the dump of phpintel lsif
+const LSIFFileName = "dump.lsif"
+
+// runLSIF exports the index of the folder as an LSIF dump for code
+// navigation outside of an editor, e.g. on a code host
+func runLSIF(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lsif", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	output := flags.String("output", "", "write the dump to `file`, defaults to "+LSIFFileName+" in the folder")
+	noCache := flags.Bool("no-cache", false, "index into a temporary storage instead of the language server's cache")
+	includeVendor := flags.Bool("include-vendor", false, "also export the files in vendor")
+	verbose := flags.Bool("verbose", false, "print the logs of indexing to stderr")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: phpintel lsif [flags] [folder]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if !*verbose {
+		log.SetOutput(ioutil.Discard)
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	path := *output
+	if path == "" {
+		path = filepath.Join(root, LSIFFileName)
+	}
+	w, err := openWorkspace(ctx, root, *noCache)
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel lsif: %v\n", err)
+		return exitFailure
+	}
+	defer w.close()
+	if _, err := w.index(ctx); err != nil {
+		fmt.Fprintf(stderr, "phpintel lsif: %v\n", err)
+		return exitFailure
+	}
+	files, err := w.phpFiles(ctx, *includeVendor)
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel lsif: %v\n", err)
+		return exitFailure
+	}
+	uris := make([]string, 0, len(files))
+	for _, file := range files {
+		uris = append(uris, file.uri)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel lsif: %v\n", err)
+		return exitFailure
+	}
+	err = lsp.ExportLSIF(ctx, w.store, w.rootPath, uris, protocol.GetVersion(ctx), f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel lsif: %v\n", err)
+		return exitFailure
+	}
+	fmt.Fprintf(stdout, "Exported %d files to %s\n", len(uris), path)
+	return exitOK
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/stretchr/testify/assert"
+)
+
+type lsifLine struct {
+	ID         int    `json:"id"`
+	Label      string `json:"label"`
+	URI        string `json:"uri"`
+	Identifier string `json:"identifier"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	OutV       int    `json:"outV"`
+	InV        int    `json:"inV"`
+	InVs       []int  `json:"inVs"`
+	Property   string `json:"property"`
+	Start      struct {
+		Line int `json:"line"`
+	} `json:"start"`
+	Result struct {
+		Contents struct {
+			Value string `json:"value"`
+		} `json:"contents"`
+	} `json:"result"`
+}
+
+// lsifDump indexes the vertices by their IDs and the edges by their labels
+// and out vertices
+type lsifDump struct {
+	vertices map[int]lsifLine
+	edges    map[lsifEdgeKey][]lsifLine
+}
+
+type lsifEdgeKey struct {
+	label string
+	outV  int
+}
+
+func (d lsifDump) out(label string, outV int) []lsifLine {
+	return d.edges[lsifEdgeKey{label, outV}]
+}
+
+func (d lsifDump) resultSet(identifier string) int {
+	for _, vertex := range d.vertices {
+		if vertex.Label == "moniker" && vertex.Identifier == identifier {
+			for _, edges := range d.edges {
+				for _, edge := range edges {
+					if edge.Label == "moniker" && edge.InV == vertex.ID {
+						return edge.OutV
+					}
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func readLSIF(t *testing.T, path string) lsifDump {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dump := lsifDump{map[int]lsifLine{}, map[lsifEdgeKey][]lsifLine{}}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var line lsifLine
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		if line.OutV == 0 {
+			dump.vertices[line.ID] = line
+			continue
+		}
+		key := lsifEdgeKey{line.Label, line.OutV}
+		dump.edges[key] = append(dump.edges[key], line)
+	}
+	assert.NoError(t, scanner.Err())
+	return dump
+}
+
+func TestLSIF(t *testing.T) {
+	homedir.DisableCache = true
+	defer func() {
+		homedir.DisableCache = false
+	}()
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	copyCase(t, dir, "../../cases/argumentDiagnostic.php", "src/mailer.php")
+	copyCase(t, dir, "../../cases/duplicate/vendor.php", "vendor/acme/collection/vendor.php")
+	files := map[string]string{
+		"src/collection.php":             "<?php\n\n$collection = new \\Collection();\nhelper();\n",
+		"composer.json":                  `{"name": "app/mailer"}`,
+		"vendor/composer/installed.json": `{"packages": [{"name": "acme/collection", "version": "1.2.0", "install-path": "../acme/collection"}]}`,
+	}
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, Run(context.Background(), []string{"lsif", "-no-cache", dir}, &stdout, &stderr), stderr.String())
+	dump := readLSIF(t, filepath.Join(dir, "dump.lsif"))
+
+	documents := map[string]int{}
+	for _, vertex := range dump.vertices {
+		if vertex.Label == "document" {
+			documents[filepath.Base(vertex.URI)] = vertex.ID
+		}
+	}
+	assert.Len(t, documents, 2)
+
+	send := dump.resultSet("\\App\\Mailer::send()")
+	assert.NotZero(t, send)
+	hovers := dump.out("textDocument/hover", send)
+	assert.Len(t, hovers, 1)
+	assert.Contains(t, dump.vertices[hovers[0].InV].Result.Contents.Value, "function send(")
+	monikers := dump.out("moniker", send)
+	assert.Equal(t, "export", dump.vertices[monikers[0].InV].Kind)
+	packages := dump.out("packageInformation", monikers[0].InV)
+	assert.Equal(t, "app/mailer", dump.vertices[packages[0].InV].Name)
+
+	definitions := dump.out("item", dump.out("textDocument/definition", send)[0].InV)
+	assert.Len(t, definitions, 1)
+	assert.Equal(t, 11, dump.vertices[definitions[0].InVs[0]].Start.Line)
+	lines := map[string][]int{}
+	for _, item := range dump.out("item", dump.out("textDocument/references", send)[0].InV) {
+		for _, rangeID := range item.InVs {
+			lines[item.Property] = append(lines[item.Property], dump.vertices[rangeID].Start.Line)
+		}
+	}
+	assert.Equal(t, []int{11}, lines["definitions"])
+	assert.Equal(t, []int{32, 35}, lines["references"])
+
+	collection := dump.resultSet("\\Collection")
+	assert.NotZero(t, collection)
+	monikers = dump.out("moniker", collection)
+	assert.Equal(t, "import", dump.vertices[monikers[0].InV].Kind)
+	packages = dump.out("packageInformation", monikers[0].InV)
+	assert.Equal(t, "acme/collection", dump.vertices[packages[0].InV].Name)
+	assert.Equal(t, "1.2.0", dump.vertices[packages[0].InV].Version)
+	assert.Empty(t, dump.out("textDocument/definition", collection))
+	assert.NotZero(t, dump.resultSet("\\helper()"))
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+// queryResult is a symbol or a reference found by phpintel query, the
+// positions are 1-based
+type queryResult struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+type querier func(ctx context.Context, w *workspace, q *analysis.Query, name string, limit int) []queryResult
+
+var queriers = map[string]querier{
+	"classes":    queryClasses,
+	"functions":  queryFunctions,
+	"methods":    queryMethods,
+	"definition": queryDefinition,
+	"references": queryReferences,
+}
+
+func runQuery(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	root := flags.String("root", ".", "the `folder` of the workspace")
+	format := flags.String("format", "text", "output `format`: text or json")
+	limit := flags.Int("limit", 100, "the maximum number of symbols of a search")
+	noIndex := flags.Bool("no-index", false, "query the index as it is instead of bringing it up to date first")
+	noCache := flags.Bool("no-cache", false, "index into a temporary storage instead of the language server's cache")
+	verbose := flags.Bool("verbose", false, "print the logs of indexing to stderr")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: phpintel query [flags] <query> <name>")
+		fmt.Fprintln(stderr, "queries:")
+		fmt.Fprintln(stderr, "  classes <keyword>            search the classes")
+		fmt.Fprintln(stderr, "  functions <keyword>          search the functions")
+		fmt.Fprintln(stderr, "  methods [\\Class::]<keyword>  search the methods, optionally of a class")
+		fmt.Fprintln(stderr, "  definition <fqn>             find the declarations, e.g. \\App\\Foo, \\App\\foo(), \\App\\Foo::bar(), \\App\\Foo::$bar or \\App\\Foo::BAR")
+		fmt.Fprintln(stderr, "  references <fqn>             find the references")
+		fmt.Fprintln(stderr, "flags:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitUsage
+	}
+	query, ok := queriers[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "phpintel query: unknown query %q\n", flags.Arg(0))
+		return exitUsage
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "phpintel query: unknown format %q\n", *format)
+		return exitUsage
+	}
+	if !*verbose {
+		log.SetOutput(ioutil.Discard)
+	}
+	w, err := openWorkspace(ctx, *root, *noCache)
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel query: %v\n", err)
+		return exitFailure
+	}
+	defer w.close()
+	if !*noIndex || *noCache {
+		if _, err := w.index(ctx); err != nil {
+			fmt.Fprintf(stderr, "phpintel query: %v\n", err)
+			return exitFailure
+		}
+	}
+	results := query(ctx, w, analysis.NewQuery(w.store), flags.Arg(1), *limit)
+	if *format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			fmt.Fprintf(stderr, "phpintel query: %v\n", err)
+			return exitFailure
+		}
+	} else {
+		for _, result := range results {
+			fmt.Fprintf(stdout, "%s:%d:%d: %s %s\n", result.File, result.Line, result.Column, result.Kind, result.Name)
+		}
+	}
+	if len(results) == 0 {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// newQueryResult creates the result of the location, the file is relative
+// to the root of the workspace or the URI if it is outside, e.g. the stubs
+func (w *workspace) newQueryResult(kind string, name string, location protocol.Location) queryResult {
+	file := location.URI
+	if strings.HasPrefix(file, w.rootURI+"/") {
+		file = strings.TrimPrefix(file, w.rootURI+"/")
+	}
+	return queryResult{
+		Kind:   kind,
+		Name:   name,
+		File:   file,
+		Line:   location.Range.Start.Line + 1,
+		Column: location.Range.Start.Character + 1,
+	}
+}
+
+func (w *workspace) symbolResult(kind string, symbol analysis.Symbol) queryResult {
+	return w.newQueryResult(kind, analysis.SymbolFQN(symbol), symbol.GetLocation())
+}
+
+func searchOptions(limit int) analysis.SearchOptions {
+	options := analysis.NewSearchOptions()
+	if limit > 0 {
+		options = options.WithLimit(limit)
+	}
+	return options
+}
+
+func queryClasses(ctx context.Context, w *workspace, q *analysis.Query, name string, limit int) []queryResult {
+	results := []queryResult{}
+	classes, _ := w.store.SearchClasses(name, searchOptions(limit))
+	for _, class := range classes {
+		results = append(results, w.symbolResult("class", class))
+	}
+	enums, _ := w.store.SearchEnums(name, searchOptions(limit))
+	for _, enum := range enums {
+		results = append(results, w.symbolResult("enum", enum))
+	}
+	return results
+}
+
+func queryFunctions(ctx context.Context, w *workspace, q *analysis.Query, name string, limit int) []queryResult {
+	results := []queryResult{}
+	functions, _ := w.store.SearchFunctions(name, searchOptions(limit))
+	for _, function := range functions {
+		results = append(results, w.symbolResult("function", function))
+	}
+	return results
+}
+
+func queryMethods(ctx context.Context, w *workspace, q *analysis.Query, name string, limit int) []queryResult {
+	results := []queryResult{}
+	var methods []*analysis.Method
+	if scope, keyword := splitMember(name); scope == "" {
+		methods, _ = w.store.SearchMethods("", name, searchOptions(limit))
+	} else if keyword == "" {
+		methods = w.store.GetAllMethods(scope)
+	} else {
+		methods, _ = w.store.SearchMethods(scope, keyword, searchOptions(limit))
+	}
+	for _, method := range methods {
+		results = append(results, w.symbolResult("method", method))
+	}
+	return results
+}
+
+// queryDefinition finds the declarations of the FQN, the name is the
+// same as in PHP with () after the functions and methods
+func queryDefinition(ctx context.Context, w *workspace, q *analysis.Query, name string, limit int) []queryResult {
+	results := []queryResult{}
+	scope, member := splitMember(name)
+	if scope != "" {
+		isMethod := strings.HasSuffix(member, "()")
+		member = strings.TrimSuffix(member, "()")
+		if strings.HasPrefix(member, "$") {
+			for _, prop := range q.GetProps(scope, member) {
+				results = append(results, w.symbolResult("property", prop))
+			}
+			return results
+		}
+		if !isMethod {
+			for _, classConst := range q.GetClassConsts(scope, member) {
+				results = append(results, w.symbolResult("classConst", classConst))
+			}
+		}
+		for _, method := range q.GetMethods(scope, member) {
+			results = append(results, w.symbolResult("method", method))
+		}
+		return results
+	}
+	if strings.HasSuffix(member, "()") {
+		member = strings.TrimSuffix(member, "()")
+		for _, function := range q.GetFunctions(member) {
+			results = append(results, w.symbolResult("function", function))
+		}
+		return results
+	}
+	for _, class := range q.GetClasses(member) {
+		results = append(results, w.symbolResult("class", class))
+	}
+	for _, theInterface := range q.GetInterfaces(member) {
+		results = append(results, w.symbolResult("interface", theInterface))
+	}
+	for _, trait := range q.GetTraits(member) {
+		results = append(results, w.symbolResult("trait", trait))
+	}
+	for _, function := range q.GetFunctions(member) {
+		results = append(results, w.symbolResult("function", function))
+	}
+	for _, constant := range q.GetConsts(member) {
+		results = append(results, w.symbolResult("const", constant))
+	}
+	for _, define := range q.GetDefines(member) {
+		results = append(results, w.symbolResult("define", define))
+	}
+	return results
+}
+
+// queryReferences finds the references of the FQN, the members are
+// indexed by their names so the references are filtered by their scopes
+func queryReferences(ctx context.Context, w *workspace, q *analysis.Query, name string, limit int) []queryResult {
+	results := []queryResult{}
+	scope, member := splitMember(name)
+	ref := member
+	if scope != "" {
+		ref = "." + member
+	}
+	for _, location := range w.store.GetReferences(ref) {
+		if scope != "" && !q.IsMemberReferenceOf(ctx, location, scope) {
+			continue
+		}
+		results = append(results, w.newQueryResult("reference", name, location))
+	}
+	return results
+}
+
+// splitMember splits the name into the FQN of the class and the member,
+// the FQN is empty if there is no member, e.g. \Foo::bar() or \Foo
+func splitMember(name string) (string, string) {
+	if index := strings.Index(name, "::"); index >= 0 {
+		scope := name[:index]
+		if scope != "" && !strings.HasPrefix(scope, "\\") {
+			scope = "\\" + scope
+		}
+		return scope, name[index+2:]
+	}
+	if !strings.HasPrefix(name, "\\") {
+		name = "\\" + name
+	}
+	return "", name
+}
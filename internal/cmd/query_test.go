@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/stretchr/testify/assert"
+)
+
+func runQueryJSON(t *testing.T, args ...string) []queryResult {
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), append([]string{"query", "-format", "json"}, args...), &stdout, &stderr)
+	assert.Equal(t, exitOK, code, stderr.String())
+	var results []queryResult
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+	return results
+}
+
+func TestIndexAndQuery(t *testing.T) {
+	homedir.DisableCache = true
+	defer func() {
+		homedir.DisableCache = false
+	}()
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	copyCase(t, dir, "../../cases/argumentDiagnostic.php", "src/mailer.php")
+	copyCase(t, dir, "../../cases/enum.php", "src/enum.php")
+	copyCase(t, dir, "../../cases/point.php", "src/point.php")
+	ctx := context.Background()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, Run(ctx, []string{"index", dir}, &stdout, &stderr), stderr.String())
+	assert.True(t, strings.HasPrefix(stdout.String(), "Indexed 3 files of "+dir), stdout.String())
+	entries, err := os.ReadDir(filepath.Join(os.Getenv("HOME"), ".phpintel", "data"))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	assert.Equal(t, []queryResult{
+		{Kind: "class", Name: "\\App\\Mailer", File: "src/mailer.php", Line: 9, Column: 1},
+	}, runQueryJSON(t, "-root", dir, "-no-index", "definition", "\\App\\Mailer"))
+	assert.Equal(t, []queryResult{
+		{Kind: "method", Name: "\\App\\Mailer::send()", File: "src/mailer.php", Line: 12, Column: 5},
+	}, runQueryJSON(t, "-root", dir, "-no-index", "definition", "App\\Mailer::send()"))
+	assert.Equal(t, []queryResult{
+		{Kind: "function", Name: "\\App\\greet()", File: "src/mailer.php", Line: 5, Column: 1},
+	}, runQueryJSON(t, "-root", dir, "-no-index", "definition", "\\App\\greet()"))
+
+	references := runQueryJSON(t, "-root", dir, "-no-index", "references", "\\App\\greet()")
+	assert.Len(t, references, 10)
+	for _, reference := range references {
+		assert.Equal(t, "src/mailer.php", reference.File)
+	}
+
+	names := []string{}
+	for _, result := range runQueryJSON(t, "-root", dir, "-no-index", "classes", "Mailer") {
+		if strings.HasPrefix(result.Name, "\\App\\") {
+			names = append(names, result.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{"\\App\\Mailer", "\\App\\SmtpMailer"}, names)
+	assert.Len(t, runQueryJSON(t, "-root", dir, "-no-index", "methods", "\\App\\Mailer::"), 3)
+	assert.Equal(t, []queryResult{
+		{Kind: "enum", Name: "\\App\\Enums\\Suit", File: "src/enum.php", Line: 25, Column: 1},
+	}, runQueryJSON(t, "-root", dir, "-no-index", "classes", "Sui"))
+
+	lines := []int{}
+	for _, reference := range runQueryJSON(t, "-root", dir, "-no-index", "references", "\\App\\Point::$x") {
+		assert.Equal(t, "src/point.php", reference.File)
+		lines = append(lines, reference.Line)
+	}
+	assert.ElementsMatch(t, []int{6, 20, 21, 22}, lines)
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, Run(ctx, []string{"query", "-root", dir, "definition", "\\App\\legacy()"}, &stdout, &stderr))
+	assert.Equal(t, "src/mailer.php:7:1: function \\App\\legacy()\n", stdout.String())
+	assert.Equal(t, exitFailure, Run(ctx, []string{"query", "-root", dir, "-no-index", "definition", "\\App\\Missing"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, Run(ctx, []string{"query", "-root", dir, "callers", "\\App\\greet()"}, &stdout, &stderr))
+}
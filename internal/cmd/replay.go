@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/john-nguyen09/phpintel/internal/jsonrpc2"
+	"github.com/john-nguyen09/phpintel/internal/lsp"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// runReplay replays a session which was recorded with -record against a new
+// language server and reports the responses which differ
+func runReplay(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	root := flags.String("root", "", "replace the root folder of the recording with `folder`")
+	verbose := flags.Bool("verbose", false, "print the logs of the language server to stderr")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: phpintel replay [flags] recording")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	if !*verbose {
+		defer log.SetOutput(log.Writer())
+		log.SetOutput(ioutil.Discard)
+	}
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel replay: %v\n", err)
+		return exitFailure
+	}
+	messages, err := jsonrpc2.ReadRecording(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel replay: %v\n", err)
+		return exitFailure
+	}
+	if *root != "" {
+		rootPath, err := filepath.Abs(*root)
+		if err != nil {
+			fmt.Fprintf(stderr, "phpintel replay: %v\n", err)
+			return exitFailure
+		}
+		rewriteRootURI(messages, util.PathToURI(rootPath))
+	}
+	result, err := lsp.Replay(ctx, messages)
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel replay: %v\n", err)
+		return exitFailure
+	}
+	for _, mismatch := range result.Mismatches {
+		fmt.Fprintf(stdout, "%s (id %s)\n  recorded: %s\n  replayed: %s\n",
+			mismatch.Method, mismatch.ID, mismatch.Expected, mismatch.Actual)
+	}
+	fmt.Fprintf(stdout, "Replayed %d messages, %d of %d responses differ\n",
+		result.Messages, len(result.Mismatches), result.Requests)
+	if len(result.Mismatches) > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// rewriteRootURI replaces the root URI of the initialize request of the
+// recording with rootURI in every message
+func rewriteRootURI(messages []jsonrpc2.RecordedMessage, rootURI string) {
+	recordedRoot := ""
+	for _, recorded := range messages {
+		var message struct {
+			Method string `json:"method"`
+			Params struct {
+				RootURI          string `json:"rootUri"`
+				WorkspaceFolders []struct {
+					URI string `json:"uri"`
+				} `json:"workspaceFolders"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(recorded.Message, &message); err != nil || message.Method != "initialize" {
+			continue
+		}
+		recordedRoot = message.Params.RootURI
+		if len(message.Params.WorkspaceFolders) > 0 {
+			recordedRoot = message.Params.WorkspaceFolders[0].URI
+		}
+		break
+	}
+	if recordedRoot == "" {
+		return
+	}
+	from, _ := json.Marshal(strings.TrimSuffix(recordedRoot, "/"))
+	to, _ := json.Marshal(strings.TrimSuffix(rootURI, "/"))
+	from, to = bytes.Trim(from, `"`), bytes.Trim(to, `"`)
+	for i := range messages {
+		messages[i].Message = bytes.ReplaceAll(messages[i].Message, from, to)
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplay(t *testing.T) {
+	dir := t.TempDir()
+	copyCase(t, dir, "../../cases/argumentDiagnostic.php", "src/mailer.php")
+	ctx := context.Background()
+
+	var stdout, stderr bytes.Buffer
+	code := Run(ctx, []string{"replay", "-root", dir, "../../cases/replay/definition.jsonl"}, &stdout, &stderr)
+	assert.Equal(t, exitOK, code, stderr.String())
+	assert.Equal(t, "Replayed 5 messages, 0 of 3 responses differ\n", stdout.String())
+
+	recording, err := ioutil.ReadFile("../../cases/replay/definition.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := filepath.Join(t.TempDir(), "changed.jsonl")
+	recording = bytes.Replace(recording, []byte(`"end":{"line":20,"character":5}`), []byte(`"end":{"line":20,"character":6}`), 1)
+	if err := ioutil.WriteFile(changed, recording, 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	code = Run(ctx, []string{"replay", "-root", dir, changed}, &stdout, &stderr)
+	assert.Equal(t, exitFailure, code, stderr.String())
+	assert.True(t, strings.HasPrefix(stdout.String(), "textDocument/hover (id 3)\n"), stdout.String())
+	assert.True(t, strings.HasSuffix(stdout.String(), "Replayed 5 messages, 1 of 3 responses differ\n"), stdout.String())
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+type reportWriter func(w io.Writer, report checkReport) error
+
+var reportWriters = map[string]reportWriter{
+	"text":       writeTextReport,
+	"json":       writeJSONReport,
+	"checkstyle": writeCheckstyleReport,
+	"sarif":      writeSARIFReport,
+}
+
+// writeTextReport writes a line per diagnostic, e.g.
+// src/Foo.php:12:5: warning: Undefined variable $x. [undefined-variable]
+func writeTextReport(w io.Writer, report checkReport) error {
+	counts := map[protocol.DiagnosticSeverity]int{}
+	for _, file := range report.files {
+		for _, diagnostic := range file.diagnostics {
+			counts[diagnostic.Severity]++
+			start := diagnostic.Range.Start
+			line := fmt.Sprintf("%s:%d:%d: %s: %s", file.path, start.Line+1, start.Character+1,
+				analysis.SeverityName(diagnostic.Severity), diagnostic.Message)
+			if code := analysis.DiagnosticCode(diagnostic); code != "" {
+				line += " [" + code + "]"
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d errors, %d warnings, %d information, %d hints in %d files\n",
+		counts[protocol.SeverityError], counts[protocol.SeverityWarning],
+		counts[protocol.SeverityInformation], counts[protocol.SeverityHint], len(report.files))
+	return err
+}
+
+type jsonDiagnostic struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	Severity  string `json:"severity"`
+	Code      string `json:"code,omitempty"`
+	Message   string `json:"message"`
+}
+
+// writeJSONReport writes the diagnostics as an array with 1-based positions
+func writeJSONReport(w io.Writer, report checkReport) error {
+	results := []jsonDiagnostic{}
+	for _, file := range report.files {
+		for _, diagnostic := range file.diagnostics {
+			r := diagnostic.Range
+			results = append(results, jsonDiagnostic{
+				File:      file.path,
+				Line:      int(r.Start.Line) + 1,
+				Column:    int(r.Start.Character) + 1,
+				EndLine:   int(r.End.Line) + 1,
+				EndColumn: int(r.End.Character) + 1,
+				Severity:  analysis.SeverityName(diagnostic.Severity),
+				Code:      analysis.DiagnosticCode(diagnostic),
+				Message:   diagnostic.Message,
+			})
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+var checkstyleSeverities = map[protocol.DiagnosticSeverity]string{
+	protocol.SeverityError:       "error",
+	protocol.SeverityWarning:     "warning",
+	protocol.SeverityInformation: "info",
+	protocol.SeverityHint:        "info",
+}
+
+// writeCheckstyleReport writes the files with diagnostics in the Checkstyle XML format
+func writeCheckstyleReport(w io.Writer, report checkReport) error {
+	result := checkstyleReport{Version: "4.3"}
+	for _, file := range report.files {
+		if len(file.diagnostics) == 0 {
+			continue
+		}
+		errors := []checkstyleError{}
+		for _, diagnostic := range file.diagnostics {
+			source := "phpintel"
+			if code := analysis.DiagnosticCode(diagnostic); code != "" {
+				source += "." + code
+			}
+			errors = append(errors, checkstyleError{
+				Line:     int(diagnostic.Range.Start.Line) + 1,
+				Column:   int(diagnostic.Range.Start.Character) + 1,
+				Severity: checkstyleSeverities[diagnostic.Severity],
+				Message:  diagnostic.Message,
+				Source:   source,
+			})
+		}
+		result.Files = append(result.Files, checkstyleFile{file.path, errors})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+var sarifLevels = map[protocol.DiagnosticSeverity]string{
+	protocol.SeverityError:       "error",
+	protocol.SeverityWarning:     "warning",
+	protocol.SeverityInformation: "note",
+	protocol.SeverityHint:        "note",
+}
+
+// writeSARIFReport writes the diagnostics as a SARIF 2.1.0 log, the locations
+// are relative to %SRCROOT% which is the checked folder
+func writeSARIFReport(w io.Writer, report checkReport) error {
+	rules := map[string]struct{}{}
+	results := []sarifResult{}
+	for _, file := range report.files {
+		for _, diagnostic := range file.diagnostics {
+			code := analysis.DiagnosticCode(diagnostic)
+			if code != "" {
+				rules[code] = struct{}{}
+			}
+			r := diagnostic.Range
+			results = append(results, sarifResult{
+				RuleID:  code,
+				Level:   sarifLevels[diagnostic.Severity],
+				Message: sarifMessage{diagnostic.Message},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{file.path, "%SRCROOT%"},
+						Region: sarifRegion{
+							StartLine:   int(r.Start.Line) + 1,
+							StartColumn: int(r.Start.Character) + 1,
+							EndLine:     int(r.End.Line) + 1,
+							EndColumn:   int(r.End.Character) + 1,
+						},
+					},
+				}},
+			})
+		}
+	}
+	driver := sarifDriver{
+		Name:           "phpintel",
+		Version:        report.version,
+		InformationURI: "https://github.com/john-nguyen09/phpintel",
+		Rules:          []sarifRule{},
+	}
+	for id := range rules {
+		rule := sarifRule{ID: id}
+		if r, ok := analysis.GetRule(id); ok {
+			rule.ShortDescription = &sarifMessage{r.Description}
+		}
+		driver.Rules = append(driver.Rules, rule)
+	}
+	sort.Slice(driver.Rules, func(i, j int) bool {
+		return driver.Rules[i].ID < driver.Rules[j].ID
+	})
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{driver},
+			Results: results,
+		}},
+	})
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// TagsFileName is the default name of the file of phpintel tags
+const TagsFileName = "tags"
+
+// runTags writes a ctags file of the folder for the editors without a
+// language server, only the entries of the changed files are regenerated
+// unless -full is given
+func runTags(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tags", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	output := flags.String("output", "", "write the tags to `file`, defaults to "+TagsFileName+" in the folder")
+	full := flags.Bool("full", false, "regenerate the entries of every file instead of only the changed ones")
+	noCache := flags.Bool("no-cache", false, "index into a temporary storage instead of the language server's cache")
+	includeVendor := flags.Bool("include-vendor", false, "also write the symbols of the files in vendor")
+	verbose := flags.Bool("verbose", false, "print the logs of indexing to stderr")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: phpintel tags [flags] [folder]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if !*verbose {
+		log.SetOutput(ioutil.Discard)
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	path := *output
+	if path == "" {
+		path = filepath.Join(root, TagsFileName)
+	}
+	w, err := openWorkspace(ctx, root, *noCache)
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel tags: %v\n", err)
+		return exitFailure
+	}
+	defer w.close()
+	if _, err := w.index(ctx); err != nil {
+		fmt.Fprintf(stderr, "phpintel tags: %v\n", err)
+		return exitFailure
+	}
+	var previous io.Reader
+	if !*full {
+		if f, err := os.Open(path); err == nil {
+			defer f.Close()
+			previous = f
+		}
+	}
+	// The tags are written to a temporary file first because the previous
+	// file is read while writing
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tags")
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel tags: %v\n", err)
+		return exitFailure
+	}
+	defer os.Remove(tmp.Name())
+	result, err := w.store.WriteTags(tmp, previous, *includeVendor)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "phpintel tags: %v\n", err)
+		return exitFailure
+	}
+	fmt.Fprintf(stdout, "Wrote the tags of %d files (%d unchanged) to %s\n", result.Written+result.Reused, result.Reused, path)
+	return exitOK
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	homedir.DisableCache = true
+	defer func() {
+		homedir.DisableCache = false
+	}()
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	copyCase(t, dir, "../../cases/argumentDiagnostic.php", "src/mailer.php")
+	copyCase(t, dir, "../../cases/duplicate/workspace.php", "src/workspace.php")
+	copyCase(t, dir, "../../cases/enum.php", "src/enums.php")
+	copyCase(t, dir, "../../cases/duplicate/vendor.php", "vendor/acme/collection/vendor.php")
+	ctx := context.Background()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, Run(ctx, []string{"tags", dir}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, "Wrote the tags of 3 files (0 unchanged) to "+filepath.Join(dir, "tags")+"\n", stdout.String())
+	data, err := ioutil.ReadFile(filepath.Join(dir, "tags"))
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	assert.Contains(t, lines, "Mailer\tsrc/mailer.php\t9;\"\tkind:class\tnamespace:App")
+	assert.Contains(t, lines, "greet\tsrc/mailer.php\t5;\"\tkind:function\tnamespace:App\tsignature:(string $name, string $greeting = 'Hello')")
+	assert.Contains(t, lines, "send\tsrc/mailer.php\t12;\"\tkind:function\tclass:App\\\\Mailer\tnamespace:App\taccess:public\tsignature:(string $to, string $subject, string $body = '')")
+	assert.Contains(t, lines, "sum\tsrc/mailer.php\t6;\"\tkind:function\tnamespace:App\tsignature:(int ...$numbers)")
+	assert.Contains(t, lines, "Arrayable\tsrc/workspace.php\t10;\"\tkind:interface")
+	assert.Contains(t, lines, "VERSION\tsrc/workspace.php\t18;\"\tkind:define")
+	assert.Contains(t, lines, "APP_DEBUG\tsrc/workspace.php\t20;\"\tkind:define")
+	assert.Contains(t, lines, "Status\tsrc/enums.php\t12;\"\tkind:enum\tnamespace:App\\\\Enums")
+	assert.Contains(t, lines, "Pending\tsrc/enums.php\t14;\"\tkind:define\tenum:App\\\\Enums\\\\Status\tnamespace:App\\\\Enums\taccess:public")
+	assert.Contains(t, lines, "color\tsrc/enums.php\t30;\"\tkind:function\tenum:App\\\\Enums\\\\Suit\tnamespace:App\\\\Enums\taccess:public\tsignature:()")
+	for _, line := range lines {
+		assert.NotContains(t, line, "vendor/")
+	}
+	assert.True(t, sortedStrings(lines), "the tags must be sorted")
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "src/workspace.php"), []byte("<?php\n\nclass Renamed\n{\n}\n"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	assert.Equal(t, exitOK, Run(ctx, []string{"tags", dir}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, "Wrote the tags of 3 files (2 unchanged) to "+filepath.Join(dir, "tags")+"\n", stdout.String())
+	data, err = ioutil.ReadFile(filepath.Join(dir, "tags"))
+	assert.NoError(t, err)
+	lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	assert.Contains(t, lines, "Mailer\tsrc/mailer.php\t9;\"\tkind:class\tnamespace:App")
+	assert.Contains(t, lines, "Renamed\tsrc/workspace.php\t3;\"\tkind:class")
+	assert.NotContains(t, lines, "Arrayable\tsrc/workspace.php\t10;\"\tkind:interface")
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, Run(ctx, []string{"tags", "-full", "-include-vendor", dir}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, "Wrote the tags of 4 files (0 unchanged) to "+filepath.Join(dir, "tags")+"\n", stdout.String())
+}
+
+func sortedStrings(lines []string) bool {
+	for i := 1; i < len(lines); i++ {
+		if lines[i-1] > lines[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -11,6 +11,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
 )
@@ -58,6 +59,20 @@ func NewErrorf(code int64, format string, args ...interface{}) *Error {
 	}
 }
 
+// PanicError is passed to the Error method of the handlers when the
+// handling of a request panics, the request is replied with an internal
+// error instead of crashing the process.
+type PanicError struct {
+	Method string
+	Params *json.RawMessage
+	Value  interface{}
+	Stack  []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("method %q panicked: %v", e.Method, e.Value)
+}
+
 // NewConn creates a new connection object around the supplied stream.
 // You must call Run for the connection to be active.
 func NewConn(s Stream) *Conn {
@@ -355,6 +370,20 @@ func (c *Conn) Run(runCtx context.Context) error {
 				<-thisRequest
 				req.state = requestSerial
 				defer func() {
+					if value := recover(); value != nil {
+						panicErr := &PanicError{
+							Method: req.Method,
+							Params: req.Params,
+							Value:  value,
+							Stack:  debug.Stack(),
+						}
+						for _, h := range c.handlers {
+							h.Error(reqCtx, panicErr)
+						}
+						if !req.IsNotify() && req.state < requestReplied {
+							req.Reply(reqCtx, nil, NewErrorf(CodeInternalError, "%s", panicErr))
+						}
+					}
 					c.setHandling(req, false)
 					if !req.IsNotify() && req.state < requestReplied {
 						req.Reply(reqCtx, nil, NewErrorf(CodeInternalError, "method %q did not reply", req.Method))
@@ -5,6 +5,7 @@
 package jsonrpc2_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"flag"
@@ -92,6 +93,56 @@ func TestHeaderCall(t *testing.T) {
 	}
 }
 
+func TestPanicCall(t *testing.T) {
+	ctx := context.Background()
+	a, _ := prepare(ctx, t, true)
+	var result interface{}
+	err := a.Call(ctx, "panic", nil, &result)
+	rpcErr, ok := err.(*jsonrpc2.Error)
+	if !ok || rpcErr.Code != jsonrpc2.CodeInternalError {
+		t.Fatalf("expected an internal error, got %v", err)
+	}
+	// The connection is still alive after the panic
+	test := callTests[1]
+	results := test.newResults()
+	if err := a.Call(ctx, test.method, test.params, results); err != nil {
+		t.Fatalf("%v:Call failed: %v", test.method, err)
+	}
+	test.verifyResults(t, results)
+}
+
+func TestRecordStream(t *testing.T) {
+	ctx := context.Background()
+	aR, bW := io.Pipe()
+	bR, aW := io.Pipe()
+	var recording bytes.Buffer
+	a := jsonrpc2.NewConn(jsonrpc2.NewRecordStream(jsonrpc2.NewHeaderStream(aR, aW), &recording))
+	a.AddHandler(&handle{})
+	go a.Run(ctx)
+	run(ctx, t, true, bR, bW)
+
+	var result string
+	if err := a.Call(ctx, "one_string", "fish", &result); err != nil {
+		t.Fatalf("one_string:Call failed: %v", err)
+	}
+	messages, err := jsonrpc2.ReadRecording(&recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 recorded messages, got %d", len(messages))
+	}
+	if messages[0].Direction != "send" || messages[1].Direction != "receive" {
+		t.Errorf("unexpected directions %s, %s", messages[0].Direction, messages[1].Direction)
+	}
+	var response struct {
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(messages[1].Message, &response); err != nil || response.Result != "got:fish" {
+		t.Errorf("unexpected response %s", messages[1].Message)
+	}
+}
+
 func prepare(ctx context.Context, t *testing.T, withHeaders bool) (*jsonrpc2.Conn, *jsonrpc2.Conn) {
 	aR, bW := io.Pipe()
 	bR, aW := io.Pipe()
@@ -154,6 +205,9 @@ func (h *handle) Deliver(ctx context.Context, r *jsonrpc2.Request, delivered boo
 			return true
 		}
 		r.Reply(ctx, path.Join(v...), nil)
+	case "panic":
+		var v []string
+		_ = v[len(v)]
 	default:
 		r.Reply(ctx, nil, jsonrpc2.NewErrorf(jsonrpc2.CodeMethodNotFound, "method %q not found", r.Method))
 	}
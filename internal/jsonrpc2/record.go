@@ -0,0 +1,80 @@
+package jsonrpc2
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// RecordedMessage is a message of a session recorded by a record stream.
+// Direction is "receive" for the messages read from the stream, i.e. the
+// messages of the client of a language server, and "send" for the others.
+type RecordedMessage struct {
+	Time      time.Time       `json:"time"`
+	Direction string          `json:"direction"`
+	Message   json.RawMessage `json:"message"`
+}
+
+// NewRecordStream returns a Stream which writes every message read from or
+// written to s to w, one JSON encoded RecordedMessage per line.
+func NewRecordStream(s Stream, w io.Writer) Stream {
+	return &recordStream{
+		Stream: s,
+		w:      w,
+	}
+}
+
+type recordStream struct {
+	Stream
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *recordStream) Read(ctx context.Context) ([]byte, int64, error) {
+	data, n, err := s.Stream.Read(ctx)
+	if err == nil {
+		s.record(Receive, data)
+	}
+	return data, n, err
+}
+
+func (s *recordStream) Write(ctx context.Context, data []byte) (int64, error) {
+	s.record(Send, data)
+	return s.Stream.Write(ctx, data)
+}
+
+func (s *recordStream) record(direction Direction, data []byte) {
+	message := data
+	if !json.Valid(data) {
+		// keep the malformed messages as strings so the line stays valid
+		message, _ = json.Marshal(string(data))
+	}
+	line, err := json.Marshal(RecordedMessage{
+		Time:      time.Now(),
+		Direction: direction.String(),
+		Message:   message,
+	})
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w.Write(append(line, '\n'))
+}
+
+// ReadRecording reads the messages written by a record stream.
+func ReadRecording(r io.Reader) ([]RecordedMessage, error) {
+	messages := []RecordedMessage{}
+	decoder := json.NewDecoder(r)
+	for {
+		var message RecordedMessage
+		if err := decoder.Decode(&message); err == io.EOF {
+			return messages, nil
+		} else if err != nil {
+			return messages, err
+		}
+		messages = append(messages, message)
+	}
+}
@@ -0,0 +1,57 @@
+package lsp
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// loadBaseline reads the baseline at the root of the workspace folder, the
+// previous baseline is dropped if the file does not exist anymore
+func (s *workspaceStore) loadBaseline(store *analysis.Store) {
+	rootPath, err := util.URIToPath(store.GetURI())
+	if err != nil {
+		return
+	}
+	baseline, err := analysis.ReadBaseline(filepath.Join(rootPath, analysis.BaselineFileName))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("loadBaseline: %v", err)
+		}
+		s.baselines.Remove(store.GetURI())
+		return
+	}
+	s.baselines.Set(store.GetURI(), baseline)
+}
+
+// filterBaseline hides the diagnostics of the document which are in the baseline
+// of the workspace folder, the stale entries are only reported when the
+// diagnostics are complete otherwise the missing ones would be stale
+func (s *workspaceStore) filterBaseline(store *analysis.Store, document *analysis.Document,
+	diagnostics []protocol.Diagnostic, isComplete bool) []protocol.Diagnostic {
+	value, ok := s.baselines.Get(store.GetURI())
+	if !ok {
+		return diagnostics
+	}
+	rootPath, err := util.URIToPath(store.GetURI())
+	if err != nil {
+		return diagnostics
+	}
+	path, err := util.URIToPath(document.GetURI())
+	if err != nil {
+		return diagnostics
+	}
+	file, err := filepath.Rel(rootPath, path)
+	if err != nil {
+		return diagnostics
+	}
+	diagnostics, stale := value.(*analysis.Baseline).Filter(filepath.ToSlash(file), document, diagnostics)
+	if isComplete {
+		diagnostics = append(diagnostics, analysis.StaleDiagnostics(stale)...)
+	}
+	return diagnostics
+}
@@ -0,0 +1,279 @@
+package lsp
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/jsonrpc2"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+	"github.com/pkg/errors"
+)
+
+// bugReportCommand writes a bug report bundle, its optional argument is
+// {"uri": "...", "redact": true}, the URI defaults to the document of the
+// last panic
+const bugReportCommand = "phpintel.writeBugReport"
+
+// crashReport is a panic which has been recovered while handling a request
+type crashReport struct {
+	Time     time.Time          `json:"time"`
+	Method   string             `json:"method"`
+	URI      string             `json:"uri,omitempty"`
+	Position *protocol.Position `json:"position,omitempty"`
+	Panic    string             `json:"panic"`
+	Stack    string             `json:"stack"`
+	params   *json.RawMessage
+}
+
+// bugReport is the report.json of a bug report bundle
+type bugReport struct {
+	Version   string       `json:"version"`
+	GoVersion string       `json:"goVersion"`
+	OS        string       `json:"os"`
+	Arch      string       `json:"arch"`
+	Time      time.Time    `json:"time"`
+	Uptime    string       `json:"uptime"`
+	URI       string       `json:"uri,omitempty"`
+	Redacted  bool         `json:"redacted"`
+	Crash     *crashReport `json:"crash,omitempty"`
+}
+
+type bugReportFile struct {
+	name    string
+	content []byte
+}
+
+type bugReportArgs struct {
+	URI    string `json:"uri"`
+	Redact bool   `json:"redact"`
+}
+
+// panicHandler records the panics which jsonrpc2 recovers from
+type panicHandler struct {
+	jsonrpc2.EmptyHandler
+	server *Server
+}
+
+func (h *panicHandler) Error(ctx context.Context, err error) {
+	if panicErr, ok := err.(*jsonrpc2.PanicError); ok {
+		h.server.recordCrash(ctx, panicErr)
+	}
+}
+
+func (s *Server) recordCrash(ctx context.Context, panicErr *jsonrpc2.PanicError) {
+	crash := &crashReport{
+		Time:   time.Now(),
+		Method: panicErr.Method,
+		Panic:  fmt.Sprint(panicErr.Value),
+		Stack:  string(panicErr.Stack),
+		params: panicErr.Params,
+	}
+	if panicErr.Params != nil {
+		var params struct {
+			TextDocument protocol.TextDocumentIdentifier `json:"textDocument"`
+			Position     *protocol.Position              `json:"position"`
+		}
+		if err := json.Unmarshal(*panicErr.Params, &params); err == nil {
+			crash.URI = params.TextDocument.URI
+			crash.Position = params.Position
+		}
+	}
+	s.saveCrash(ctx, crash)
+}
+
+// recoverTask records the panic of a task which runs outside of the requests,
+// e.g. indexing or the debounced diagnostics, it must be deferred directly
+func (s *Server) recoverTask(ctx context.Context, task string, uri string) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	crash := &crashReport{
+		Time:   time.Now(),
+		Method: task,
+		URI:    uri,
+		Panic:  fmt.Sprint(v),
+		Stack:  string(debug.Stack()),
+	}
+	if s == nil {
+		log.Printf("Recovered from a panic in %s, uri: %s: %s\n%s", crash.Method, crash.URI, crash.Panic, crash.Stack)
+		return
+	}
+	s.saveCrash(ctx, crash)
+}
+
+func (s *Server) saveCrash(ctx context.Context, crash *crashReport) {
+	position := "-"
+	if crash.Position != nil {
+		position = fmt.Sprintf("%d:%d", crash.Position.Line, crash.Position.Character)
+	}
+	log.Printf("Recovered from a panic in %s, uri: %s, position: %s: %s\n%s",
+		crash.Method, crash.URI, position, crash.Panic, crash.Stack)
+	s.crashMu.Lock()
+	s.lastCrash = crash
+	s.crashMu.Unlock()
+	if s.client == nil {
+		return
+	}
+	s.client.ShowMessage(ctx, &protocol.ShowMessageParams{
+		Type: protocol.Error,
+		Message: fmt.Sprintf("phpintel failed to handle %s, run the %s command to write a bug report",
+			crash.Method, bugReportCommand),
+	})
+}
+
+func (s *Server) executeCommand(ctx context.Context, params *protocol.ExecuteCommandParams) (interface{}, error) {
+	switch params.Command {
+	case bugReportCommand:
+		args := bugReportArgs{}
+		if len(params.Arguments) > 0 {
+			data, err := json.Marshal(params.Arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, &args); err != nil {
+				return nil, err
+			}
+		}
+		return s.writeBugReport(ctx, args)
+	}
+	return nil, jsonrpc2.NewErrorf(jsonrpc2.CodeInvalidParams, "unknown command %q", params.Command)
+}
+
+// writeBugReport writes a zip of the report, the recent log, the request
+// of the last panic and the text of the document to the bug-reports
+// directory of the data directory, and returns the path of the zip. The
+// texts of the request and the document are redacted if args.Redact is
+// set, but log.txt is not redacted
+func (s *Server) writeBugReport(ctx context.Context, args bugReportArgs) (string, error) {
+	s.crashMu.Lock()
+	crash := s.lastCrash
+	s.crashMu.Unlock()
+	report := bugReport{
+		Version:   protocol.GetVersion(ctx),
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Time:      time.Now(),
+		Uptime:    time.Since(s.started).Round(time.Second).String(),
+		URI:       args.URI,
+		Redacted:  args.Redact,
+		Crash:     crash,
+	}
+	if report.URI == "" && crash != nil {
+		report.URI = crash.URI
+	}
+	dir := filepath.Join(getDataDir(), "bug-reports")
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "bug-report-"+report.Time.Format("20060102-150405")+".zip")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	z := zip.NewWriter(f)
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	files := []bugReportFile{
+		{"report.json", reportJSON},
+		{"log.txt", []byte(util.RecentLog.String())},
+	}
+	if crash != nil && crash.params != nil {
+		params := []byte(*crash.params)
+		if args.Redact {
+			params = redactParams(params)
+		}
+		if params != nil {
+			files = append(files, bugReportFile{"request.json", params})
+		}
+	}
+	if text := s.bugReportDocument(ctx, report.URI); text != nil {
+		if args.Redact {
+			text = analysis.RedactText(text)
+		}
+		files = append(files, bugReportFile{"document.php", text})
+	}
+	for _, file := range files {
+		w, err := z.Create(file.name)
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write(file.content); err != nil {
+			return "", err
+		}
+	}
+	if err := z.Close(); err != nil {
+		return "", errors.Wrap(err, "writeBugReport")
+	}
+	if s.client != nil {
+		s.client.ShowMessage(ctx, &protocol.ShowMessageParams{
+			Type:    protocol.Info,
+			Message: "Wrote the bug report to " + path,
+		})
+	}
+	return path, nil
+}
+
+// redactParams redacts the text fields of the params, e.g. the text of
+// didOpen or the content changes of didChange, the params are omitted
+// if they cannot be redacted
+func redactParams(params []byte) []byte {
+	var value interface{}
+	if err := json.Unmarshal(params, &value); err != nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(redactTextFields(value), "", "  ")
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func redactTextFields(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if text, ok := field.(string); ok && (key == "text" || key == "newText") {
+				v[key] = string(analysis.RedactText([]byte(text)))
+				continue
+			}
+			v[key] = redactTextFields(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactTextFields(item)
+		}
+	}
+	return value
+}
+
+func (s *Server) bugReportDocument(ctx context.Context, uri string) []byte {
+	if uri == "" {
+		return nil
+	}
+	store := s.store.getStore(uri)
+	if store == nil {
+		return nil
+	}
+	document := store.GetOrCreateDocument(ctx, uri)
+	if document == nil {
+		return nil
+	}
+	document.Lock()
+	defer document.Unlock()
+	return append([]byte{}, document.GetText()...)
+}
@@ -0,0 +1,15 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactParams(t *testing.T) {
+	params := redactParams([]byte(`{"textDocument":{"uri":"file:///a.php","version":2},` +
+		`"contentChanges":[{"text":"<?php\n$password = 'secret';"}]}`))
+	assert.NotContains(t, string(params), "secret")
+	assert.Contains(t, string(params), "file:///a.php")
+	assert.Nil(t, redactParams([]byte("{")))
+}
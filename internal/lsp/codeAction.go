@@ -0,0 +1,39 @@
+package lsp
+
+import (
+	"context"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+func (s *Server) codeAction(ctx context.Context, params *protocol.CodeActionParams) ([]protocol.CodeAction, error) {
+	actions := []protocol.CodeAction{}
+	uri := params.TextDocument.URI
+	store := s.store.getStore(uri)
+	if store == nil {
+		return actions, nil
+	}
+	document := store.GetOrCreateDocument(ctx, uri)
+	if document == nil {
+		return actions, nil
+	}
+	document.Load()
+	for _, diagnostic := range params.Context.Diagnostics {
+		edit, ok := analysis.SuppressionEdit(document, diagnostic)
+		if !ok {
+			continue
+		}
+		actions = append(actions, protocol.CodeAction{
+			Title:       "Suppress this diagnostic",
+			Kind:        protocol.QuickFix,
+			Diagnostics: []protocol.Diagnostic{diagnostic},
+			Edit: &protocol.WorkspaceEdit{
+				Changes: map[string][]protocol.TextEdit{
+					uri: {edit},
+				},
+			},
+		})
+	}
+	return actions, nil
+}
@@ -83,6 +83,12 @@ func (s *Server) completion(ctx context.Context, params *protocol.CompletionPara
 	nodes := document.NodeSpineAt(document.OffsetAtPosition(pos))
 	completionCtx.token = nodes.Token()
 	// log.Printf("Completion: %s %v %T %s, kind: %v", word, pos, symbol, nodes, params.Context.TriggerKind)
+	if attribute := document.AttributeAt(pos); attribute != nil {
+		if attribute.IsInName(pos) {
+			return attributeCompletion(completionCtx, attribute.Name), nil
+		}
+		return attributeArgumentCompletion(completionCtx, resolveCtx, attribute, symbol, word), nil
+	}
 	parent := nodes.Parent()
 	if parent.Type == phrase.ScopedMemberName {
 		parent = nodes.Parent()
@@ -230,6 +236,12 @@ func nameCompletion(ctx *completionContext, symbol analysis.HasTypes, word strin
 		label, textEdit := importTable.ResolveToQualified(ctx.doc, intf, intf.Name, word)
 		completionList.Items = append(completionList.Items, interfaceToCompletionItem(intf, label, textEdit))
 	}
+	enums, searchResult := store.SearchEnums(word, opts)
+	completionList.IsIncomplete = !searchResult.IsComplete
+	for _, enum := range enums {
+		label, textEdit := importTable.ResolveToQualified(ctx.doc, enum, enum.Name, word)
+		completionList.Items = append(completionList.Items, enumToCompletionItem(enum, label, textEdit))
+	}
 	consts, searchResult := store.SearchConsts(word, opts)
 	completionList.IsIncomplete = !searchResult.IsComplete
 	for _, constant := range consts {
@@ -317,6 +329,51 @@ func classCompletion(ctx *completionContext, word string) *protocol.CompletionLi
 	return completionList
 }
 
+func attributeCompletion(ctx *completionContext, word string) *protocol.CompletionList {
+	completionList := &protocol.CompletionList{
+		IsIncomplete: false,
+	}
+	store := ctx.query.Store()
+	opts := baseSearchOptions().WithPredicate(func(s analysis.Symbol) bool {
+		class, ok := s.(*analysis.Class)
+		return ok && class.IsAttribute()
+	})
+	classes, searchResult := store.SearchClasses(word, opts)
+	completionList.IsIncomplete = !searchResult.IsComplete
+	importTable := ctx.doc.ImportTableAtPos(ctx.pos)
+	for _, class := range classes {
+		label, textEdit := importTable.ResolveToQualified(ctx.doc, class, class.Name, word)
+		completionList.Items = append(completionList.Items, classToCompletionItem(class, label, textEdit))
+	}
+	return completionList
+}
+
+// attributeArgumentCompletion completes the named arguments from the
+// attribute constructors and the names for the values
+func attributeArgumentCompletion(ctx *completionContext, resolveCtx analysis.ResolveContext,
+	attribute *analysis.Attribute, symbol analysis.HasTypes, word string) *protocol.CompletionList {
+	if s, ok := symbol.(*analysis.ScopedConstantAccess); ok && s.Scope != nil {
+		return scopedAccessCompletion(ctx, word, s)
+	}
+	completionList := nameCompletion(ctx, symbol, word)
+	for _, hasParams := range attribute.ResolveToHasParams(resolveCtx) {
+		for _, param := range hasParams.GetParams() {
+			name := strings.TrimPrefix(param.Name, "$")
+			if word != "" && !strings.HasPrefix(name, word) {
+				continue
+			}
+			completionList.Items = append(completionList.Items, protocol.CompletionItem{
+				Kind:          protocol.VariableCompletion,
+				Label:         name + ":",
+				InsertText:    name + ": ",
+				Documentation: descriptionToMarkupContent(param.GetDescription()),
+				Detail:        param.Type.ToString(),
+			})
+		}
+	}
+	return completionList
+}
+
 func scopedAccessCompletion(ctx *completionContext, word string, access analysis.MemberAccess) *protocol.CompletionList {
 	completionList := &protocol.CompletionList{
 		IsIncomplete: false,
@@ -449,6 +506,12 @@ func typeCompletion(ctx *completionContext, word string) *protocol.CompletionLis
 			Detail:              getDetailFromTextEdit(theInterface.Name, textEdit),
 		})
 	}
+	enums, searchResult := store.SearchEnums(word, opts)
+	completionList.IsIncomplete = !searchResult.IsComplete
+	for _, enum := range enums {
+		label, textEdit := importTable.ResolveToQualified(ctx.doc, enum, enum.Name, word)
+		completionList.Items = append(completionList.Items, enumToCompletionItem(enum, label, textEdit))
+	}
 	if analysis.IsFQN(word) {
 		namespaces, _ := store.SearchNamespaces(word, opts)
 		for _, ns := range namespaces {
@@ -510,6 +573,11 @@ func useCompletion(ctx *completionContext, word string) *protocol.CompletionList
 	for _, trait := range traits {
 		completionList.Items = append(completionList.Items, traitToCompletionItem(trait, trait.Name.GetOriginal(), nil))
 	}
+	enums, searchResult := store.SearchEnums(word, opts)
+	completionList.IsIncomplete = !searchResult.IsComplete
+	for _, enum := range enums {
+		completionList.Items = append(completionList.Items, enumToCompletionItem(enum, enum.Name.GetOriginal(), nil))
+	}
 	return completionList
 }
 
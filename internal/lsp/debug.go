@@ -0,0 +1,91 @@
+package lsp
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+// storeStatsResult is the result of phpintel/storeStats
+type storeStatsResult struct {
+	Stores []analysis.StoreStats `json:"stores"`
+	Memory memoryStats           `json:"memory"`
+}
+
+// memoryStats are the memory statistics of the process in bytes
+type memoryStats struct {
+	Alloc      uint64 `json:"alloc"`
+	TotalAlloc uint64 `json:"totalAlloc"`
+	Sys        uint64 `json:"sys"`
+	HeapInuse  uint64 `json:"heapInuse"`
+	NumGC      uint32 `json:"numGC"`
+	Goroutines int    `json:"goroutines"`
+}
+
+func (s *Server) dumpDocument(ctx context.Context, params *protocol.TextDocumentIdentifier) (interface{}, error) {
+	store := s.store.getStore(params.URI)
+	if store == nil {
+		return nil, nil
+	}
+	document := store.GetOrCreateDocument(ctx, params.URI)
+	if document == nil {
+		return nil, nil
+	}
+	document.Lock()
+	defer document.Unlock()
+	document.Load()
+	return analysis.DumpDocument(document), nil
+}
+
+// explainPosition explains how the symbol at the position is resolved,
+// the definitions are looked up with the same query so their lookups
+// are also in the trace
+func (s *Server) explainPosition(ctx context.Context, params *protocol.TextDocumentPositionParams) (interface{}, error) {
+	uri := params.TextDocument.URI
+	store := s.store.getStore(uri)
+	if store == nil {
+		return nil, nil
+	}
+	document := store.GetOrCreateDocument(ctx, uri)
+	if document == nil {
+		return nil, nil
+	}
+	document.Lock()
+	defer document.Unlock()
+	document.Load()
+	q := analysis.NewQuery(store)
+	q.WithTrace()
+	explanation := analysis.ExplainPosition(q, document, params.Position)
+	resolveCtx := analysis.NewResolveContext(q, document)
+	for _, symbol := range definitionSymbols(q, resolveCtx, document, params.Position, document.HasTypesAtPos(params.Position)) {
+		explanation.Definitions = append(explanation.Definitions, analysis.TraceSymbol{
+			Name:     analysis.SymbolFQN(symbol),
+			Location: symbol.GetLocation(),
+		})
+	}
+	return explanation, nil
+}
+
+func (s *Server) storeStats(ctx context.Context) (interface{}, error) {
+	result := storeStatsResult{Stores: []analysis.StoreStats{}}
+	for _, store := range s.store.stores {
+		result.Stores = append(result.Stores, store.Stats())
+	}
+	result.Memory = readMemoryStats()
+	return result, nil
+}
+
+func readMemoryStats() memoryStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return memoryStats{
+		Alloc:      m.Alloc,
+		TotalAlloc: m.TotalAlloc,
+		Sys:        m.Sys,
+		HeapInuse:  m.HeapInuse,
+		NumGC:      m.NumGC,
+		Goroutines: runtime.NumGoroutine(),
+	}
+}
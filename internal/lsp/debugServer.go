@@ -0,0 +1,149 @@
+package lsp
+
+import (
+	"context"
+	"expvar"
+	"html/template"
+	"log"
+	"net/http"
+	_ "net/http/pprof" // registers /debug/pprof on http.DefaultServeMux
+	"sync"
+	"time"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// debugStatus is the content of the status page of the debug server, it is
+// also published as the lsp expvar
+type debugStatus struct {
+	Version        string                   `json:"version"`
+	Uptime         time.Duration            `json:"uptime"`
+	Latencies      []util.HistogramSnapshot `json:"latencies"`
+	InFlight       []inFlightRequest        `json:"inFlight"`
+	PendingCreates int64                    `json:"pendingCreates"`
+	PendingDeletes int64                    `json:"pendingDeletes"`
+	Stores         []analysis.StorageStats  `json:"stores"`
+	Timings        []util.HistogramSnapshot `json:"timings"`
+	Memory         memoryStats              `json:"memory"`
+}
+
+var publishDebugStatus sync.Once
+
+func (s *Server) debugStatus(ctx context.Context) debugStatus {
+	status := debugStatus{
+		Version:   protocol.GetVersion(ctx),
+		Uptime:    time.Since(s.started).Round(time.Second),
+		Latencies: s.metrics.latencies.Snapshot(),
+		InFlight:  s.metrics.inFlightRequests(),
+		Stores:    []analysis.StorageStats{},
+		Timings:   util.Timings.Snapshot(),
+		Memory:    readMemoryStats(),
+	}
+	status.PendingCreates, status.PendingDeletes = s.store.queueDepth()
+	for _, store := range s.store.stores {
+		status.Stores = append(status.Stores, store.StorageStats())
+	}
+	return status
+}
+
+// ServeDebug serves pprof and expvar under /debug/ and a status page of
+// the server on /, it returns when the context is done or the listener
+// fails
+func ServeDebug(ctx context.Context, addr string, s *Server) error {
+	publishDebugStatus.Do(func() {
+		expvar.Publish("lsp", expvar.Func(func() interface{} {
+			return s.debugStatus(ctx)
+		}))
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/debug/", http.DefaultServeMux)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := statusTemplate.Execute(w, s.debugStatus(ctx)); err != nil {
+			log.Printf("ServeDebug: %v", err)
+		}
+	})
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	log.Printf("Serving debug information on http://%s", addr)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func bucketLabels() []string {
+	labels := []string{}
+	for _, bucket := range util.LatencyBuckets {
+		labels = append(labels, "≤"+bucket.String())
+	}
+	return append(labels, ">"+util.LatencyBuckets[len(util.LatencyBuckets)-1].String())
+}
+
+var statusTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"buckets": bucketLabels,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>phpintel</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 2px 6px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+pre { background: #f4f4f4; padding: 4px; }
+</style>
+</head>
+<body>
+<h1>phpintel {{.Version}}</h1>
+<p>Up for {{.Uptime}}, {{.Memory.Goroutines}} goroutines, {{.Memory.HeapInuse}} bytes of heap in use.
+<a href="/debug/pprof/">pprof</a> <a href="/debug/vars">expvar</a></p>
+
+<h2>Indexing queue</h2>
+<p>{{.PendingCreates}} pending creates, {{.PendingDeletes}} pending deletes</p>
+
+<h2>In-flight requests</h2>
+<table>
+<tr><th>Method</th><th>ID</th><th>Elapsed</th></tr>
+{{range .InFlight}}<tr><td>{{.Method}}</td><td>{{.ID}}</td><td>{{.Elapsed}}</td></tr>
+{{end}}</table>
+
+{{define "histograms"}}<table>
+<tr><th>Name</th><th>Count</th><th>Mean (ms)</th><th>Max (ms)</th>{{range buckets}}<th>{{.}}</th>{{end}}</tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.Count}}</td><td>{{printf "%.2f" .MeanMs}}</td><td>{{printf "%.2f" .MaxMs}}</td>{{range .Counts}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>{{end}}
+<h2>Request latencies</h2>
+{{template "histograms" .Latencies}}
+
+<h2>Timings</h2>
+{{template "histograms" .Timings}}
+
+<h2>Stores</h2>
+{{range .Stores}}<h3>{{.URI}}</h3>
+<p>{{.OpenDocuments}} open documents</p>
+<table>
+<tr><th>pogreb</th><th></th></tr>
+<tr><td>Entries</td><td>{{.DocumentCount}}</td></tr>
+<tr><td>File size</td><td>{{.DocumentFileSize}}</td></tr>
+<tr><td>Puts</td><td>{{.DocumentPuts}}</td></tr>
+<tr><td>Gets</td><td>{{.DocumentGets}}</td></tr>
+<tr><td>Dels</td><td>{{.DocumentDels}}</td></tr>
+<tr><td>Hash collisions</td><td>{{.HashCollisions}}</td></tr>
+</table>
+<h4>Symbols leveldb</h4>
+<pre>{{.SymbolsLevelDB}}</pre>
+<h4>Completion leveldb</h4>
+<pre>{{.CompletionLevelDB}}</pre>
+{{end}}
+</body>
+</html>
+`))
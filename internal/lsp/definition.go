@@ -23,50 +23,60 @@ func (s *Server) definition(ctx context.Context, params *protocol.DefinitionPara
 	q := analysis.NewQuery(store)
 	resolveCtx := analysis.NewResolveContext(q, document)
 	pos := params.TextDocumentPositionParams.Position
-	symbol := document.HasTypesAtPos(pos)
+	for _, symbol := range definitionSymbols(q, resolveCtx, document, pos, document.HasTypesAtPos(pos)) {
+		if location := symbol.GetLocation(); util.IsURINavigatable(location.URI) {
+			locations = append(locations, location)
+		}
+	}
+	return locations, nil
+}
 
+// definitionSymbols returns the declarations of the symbol at the position
+func definitionSymbols(q *analysis.Query, resolveCtx analysis.ResolveContext, document *analysis.Document,
+	pos protocol.Position, symbol analysis.HasTypes) []analysis.Symbol {
+	symbols := []analysis.Symbol{}
 	switch v := symbol.(type) {
 	case *analysis.ClassTypeDesignator:
 		for _, typeString := range v.Type.Resolve() {
 			for _, theClass := range q.GetClasses(document.ImportTableAtPos(pos).GetClassReferenceFQN(typeString)) {
 				constructor := q.GetClassConstructor(theClass)
 				if constructor.Method == nil || constructor.Method.GetScope() != theClass.Name.GetFQN() {
-					locations = append(locations, theClass.GetLocation())
+					symbols = append(symbols, theClass)
 				} else {
-					locations = append(locations, constructor.Method.GetLocation())
+					symbols = append(symbols, constructor.Method)
 				}
 			}
 		}
-	case *analysis.ClassAccess:
-		for _, typeString := range v.Type.Resolve() {
+	case *analysis.ClassAccess, *analysis.Attribute:
+		for _, typeString := range v.GetTypes().Resolve() {
 			for _, theClass := range q.GetClasses(document.ImportTableAtPos(pos).GetClassReferenceFQN(typeString)) {
-				locations = append(locations, theClass.GetLocation())
+				symbols = append(symbols, theClass)
 			}
 		}
 	case *analysis.InterfaceAccess:
 		for _, typeString := range v.Type.Resolve() {
 			for _, theInterface := range q.GetInterfaces(document.ImportTableAtPos(pos).GetClassReferenceFQN(typeString)) {
-				locations = append(locations, theInterface.GetLocation())
+				symbols = append(symbols, theInterface)
 			}
 		}
 	case *analysis.TraitAccess:
 		for _, typeString := range v.GetTypes().Resolve() {
 			for _, trait := range q.GetTraits(typeString.GetFQN()) {
-				locations = append(locations, trait.GetLocation())
+				symbols = append(symbols, trait)
 			}
 		}
 	case *analysis.ConstantAccess:
 		name := analysis.NewTypeString(v.Name)
 		for _, theConst := range q.GetConsts(document.ImportTableAtPos(pos).GetConstReferenceFQN(q, name)) {
-			locations = append(locations, theConst.GetLocation())
+			symbols = append(symbols, theConst)
 		}
 		for _, define := range q.GetDefines(document.ImportTableAtPos(pos).GetConstReferenceFQN(q, name)) {
-			locations = append(locations, define.GetLocation())
+			symbols = append(symbols, define)
 		}
 	case *analysis.FunctionCall:
 		name := analysis.NewTypeString(v.Name)
 		for _, function := range q.GetFunctions(document.ImportTableAtPos(pos).GetFunctionReferenceFQN(q, name)) {
-			locations = append(locations, function.GetLocation())
+			symbols = append(symbols, function)
 		}
 	case *analysis.ScopedConstantAccess:
 		currentClass := document.GetClassScopeAtSymbol(v)
@@ -82,7 +92,7 @@ func (s *Server) definition(ctx context.Context, params *protocol.DefinitionPara
 			classConsts = analysis.MergeClassConstWithScope(classConsts, ccs.ReduceStatic(currentClass, v))
 		}
 		for _, c := range classConsts {
-			locations = append(locations, c.Const.GetLocation())
+			symbols = append(symbols, c.Const)
 		}
 	case *analysis.ScopedMethodAccess:
 		currentClass := document.GetClassScopeAtSymbol(v)
@@ -94,8 +104,11 @@ func (s *Server) definition(ctx context.Context, params *protocol.DefinitionPara
 			}
 			methods = analysis.MergeMethodWithScope(methods, ms.ReduceStatic(currentClass, v))
 		}
+		if len(methods) == 0 {
+			methods = q.GetMagicMethods(v.ResolveAndGetScope(resolveCtx), v.MagicMethodName())
+		}
 		for _, m := range methods {
-			locations = append(locations, m.Method.GetLocation())
+			symbols = append(symbols, m.Method)
 		}
 	case *analysis.ScopedPropertyAccess:
 		currentClass := document.GetClassScopeAtSymbol(v.Scope)
@@ -108,7 +121,7 @@ func (s *Server) definition(ctx context.Context, params *protocol.DefinitionPara
 			props = analysis.MergePropWithScope(props, ps.ReduceStatic(currentClass, v))
 		}
 		for _, p := range props {
-			locations = append(locations, p.Prop.GetLocation())
+			symbols = append(symbols, p.Prop)
 		}
 	case *analysis.PropertyAccess:
 		currentClass := document.GetClassScopeAtSymbol(v.Scope)
@@ -121,7 +134,12 @@ func (s *Server) definition(ctx context.Context, params *protocol.DefinitionPara
 			props = analysis.MergePropWithScope(props, ps.ReduceAccess(currentClass, v))
 		}
 		for _, p := range props {
-			locations = append(locations, p.Prop.GetLocation())
+			symbols = append(symbols, p.Prop)
+		}
+		if len(props) == 0 {
+			for _, m := range q.GetMagicMethods(v.ResolveAndGetScope(resolveCtx), analysis.MagicGet) {
+				symbols = append(symbols, m.Method)
+			}
 		}
 	case *analysis.MethodAccess:
 		currentClass := document.GetClassScopeAtSymbol(v.Scope)
@@ -139,26 +157,23 @@ func (s *Server) definition(ctx context.Context, params *protocol.DefinitionPara
 			}
 			methods = analysis.MergeMethodWithScope(methods, ms.ReduceAccess(currentClass, v))
 		}
+		if len(methods) == 0 {
+			methods = q.GetMagicMethods(v.ResolveAndGetScope(resolveCtx), analysis.MagicCall)
+		}
 		for _, m := range methods {
-			locations = append(locations, m.Method.GetLocation())
+			symbols = append(symbols, m.Method)
 		}
 	case *analysis.TypeDeclaration:
 		for _, typeString := range v.Type.Resolve() {
 			classes := q.GetClasses(typeString.GetFQN())
 			for _, class := range classes {
-				locations = append(locations, class.GetLocation())
+				symbols = append(symbols, class)
 			}
 			interfaces := q.GetInterfaces(typeString.GetFQN())
 			for _, theInterface := range interfaces {
-				locations = append(locations, theInterface.GetLocation())
+				symbols = append(symbols, theInterface)
 			}
 		}
 	}
-	filteredLocations := locations[:0]
-	for _, location := range locations {
-		if util.IsURINavigatable(location.URI) {
-			filteredLocations = append(filteredLocations, location)
-		}
-	}
-	return filteredLocations, nil
+	return symbols
 }
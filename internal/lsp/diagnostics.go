@@ -2,6 +2,7 @@ package lsp
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 
 	"github.com/john-nguyen09/phpintel/analysis"
@@ -10,14 +11,15 @@ import (
 )
 
 func (s *Server) provideDiagnostics(ctx context.Context, store *analysis.Store, document *analysis.Document) {
-	diagnostics := analysis.GetParserDiagnostics(document)
-	diagnostics = append(diagnostics, analysis.UnusedDiagnostics(document)...)
+	diagnostics := analysis.DocumentDiagnostics(document)
 	store.DebouncedDeprecation(func() {
 		ctx = xcontext.Detach(ctx)
+		defer s.recoverTask(ctx, "textDocument/publishDiagnostics", document.GetURI())
+		resolveCtx := analysis.NewResolveContext(analysis.NewQuery(store), document)
+		diagnostics := s.filterDiagnostics(append(diagnostics, analysis.ResolvedDiagnostics(resolveCtx)...))
 		params := &protocol.PublishDiagnosticsParams{
-			URI: document.GetURI(),
-			Diagnostics: append(diagnostics,
-				analysis.DeprecatedDiagnostics(analysis.NewResolveContext(analysis.NewQuery(store), document))...),
+			URI:         document.GetURI(),
+			Diagnostics: s.store.filterBaseline(store, document, diagnostics, true),
 		}
 		err := s.client.PublishDiagnostics(ctx, params)
 		if err != nil {
@@ -26,10 +28,106 @@ func (s *Server) provideDiagnostics(ctx context.Context, store *analysis.Store,
 	})
 	params := &protocol.PublishDiagnosticsParams{
 		URI:         document.GetURI(),
-		Diagnostics: diagnostics,
+		Diagnostics: s.store.filterBaseline(store, document, s.filterDiagnostics(diagnostics), false),
 	}
 	err := s.client.PublishDiagnostics(ctx, params)
 	if err != nil {
 		log.Println(err)
 	}
 }
+
+// severityOff is the severity of the rules which are turned off
+const severityOff protocol.DiagnosticSeverity = 0
+
+// diagnosticSettings are the settings of diagnostics from the initialization
+// options or the configuration, the rules are keyed by the config keys of the
+// rules and set to off or a severity, e.g. {"diagnostics": {"rules": {"undefinedVariable": "error"}}},
+// disabled is an alias of turning the rules off, e.g. {"diagnostics": {"disabled": ["non-static-call"]}}
+type diagnosticSettings struct {
+	Diagnostics struct {
+		Disabled []string          `json:"disabled"`
+		Rules    map[string]string `json:"rules"`
+	} `json:"diagnostics"`
+}
+
+// applySettings reads the diagnostic settings either at the top level
+// or under the phpintel section
+func (s *Server) applySettings(settings interface{}) {
+	if settings == nil {
+		return
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	var config struct {
+		diagnosticSettings
+		PHPIntel *diagnosticSettings `json:"phpintel"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		log.Println(err)
+		return
+	}
+	disabled := config.Diagnostics.Disabled
+	ruleSettings := []map[string]string{config.Diagnostics.Rules}
+	if config.PHPIntel != nil {
+		disabled = append(disabled, config.PHPIntel.Diagnostics.Disabled...)
+		ruleSettings = append(ruleSettings, config.PHPIntel.Diagnostics.Rules)
+	}
+	disabledRules := map[string]string{}
+	for _, code := range disabled {
+		rule, ok := analysis.GetRule(code)
+		if !ok {
+			log.Printf("Unknown diagnostic code %q", code)
+			continue
+		}
+		disabledRules[rule.ConfigKey] = "off"
+	}
+	ruleSettings = append([]map[string]string{disabledRules}, ruleSettings...)
+	diagnosticSeverities := map[string]protocol.DiagnosticSeverity{}
+	for _, rules := range ruleSettings {
+		for key, value := range rules {
+			rule, ok := analysis.GetRuleByConfigKey(key)
+			if !ok {
+				log.Printf("Unknown diagnostic rule %q", key)
+				continue
+			}
+			if value == "off" {
+				diagnosticSeverities[rule.Code] = severityOff
+				continue
+			}
+			severity, ok := analysis.ParseSeverity(value)
+			if !ok {
+				log.Printf("Unknown severity %q of diagnostic rule %q", value, key)
+				continue
+			}
+			diagnosticSeverities[rule.Code] = severity
+		}
+	}
+	s.stateMu.Lock()
+	s.diagnosticSeverities = diagnosticSeverities
+	s.stateMu.Unlock()
+}
+
+// filterDiagnostics applies the severities of the rules in the settings
+// and removes the diagnostics of which rules are turned off
+func (s *Server) filterDiagnostics(diagnostics []protocol.Diagnostic) []protocol.Diagnostic {
+	s.stateMu.Lock()
+	diagnosticSeverities := s.diagnosticSeverities
+	s.stateMu.Unlock()
+	if len(diagnosticSeverities) == 0 {
+		return diagnostics
+	}
+	results := []protocol.Diagnostic{}
+	for _, diagnostic := range diagnostics {
+		if severity, ok := diagnosticSeverities[analysis.DiagnosticCode(diagnostic)]; ok {
+			if severity == severityOff {
+				continue
+			}
+			diagnostic.Severity = severity
+		}
+		results = append(results, diagnostic)
+	}
+	return results
+}
@@ -0,0 +1,61 @@
+package lsp
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+// crashingClient panics when the debounced diagnostics are published
+type crashingClient struct {
+	protocol.Client
+	published int32
+	messages  chan string
+}
+
+func (c *crashingClient) PublishDiagnostics(ctx context.Context, params *protocol.PublishDiagnosticsParams) error {
+	if atomic.AddInt32(&c.published, 1) > 1 {
+		panic("cannot publish the diagnostics")
+	}
+	return nil
+}
+
+func (c *crashingClient) ShowMessage(ctx context.Context, params *protocol.ShowMessageParams) error {
+	c.messages <- params.Message
+	return nil
+}
+
+func TestPanicInDiagnostics(t *testing.T) {
+	ctx := context.Background()
+	client := &crashingClient{messages: make(chan string, 1)}
+	s := &Server{client: client}
+	s.store = newWorkspaceStore(ctx, s)
+	store, err := analysis.NewStore(protocol.NewFileFS(), "file:///project", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	store.DebouncedDeprecation = func(f func()) {
+		go f()
+	}
+	document := analysis.NewDocument("file:///project/index.php", []byte("<?php\necho $undefined;\n"))
+	document.Load()
+	s.provideDiagnostics(ctx, store, document)
+
+	select {
+	case message := <-client.messages:
+		assert.Contains(t, message, "textDocument/publishDiagnostics")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the panic is not recorded")
+	}
+	s.crashMu.Lock()
+	crash := s.lastCrash
+	s.crashMu.Unlock()
+	assert.Equal(t, "file:///project/index.php", crash.URI)
+	assert.Equal(t, "cannot publish the diagnostics", crash.Panic)
+}
@@ -33,6 +33,7 @@ func (s *Server) initialize(ctx context.Context, params *protocol.InitializePara
 	s.state = serverInitializing
 	s.fileExtensionsSupported = params.Capabilities.XContentProvider && params.Capabilities.XFilesProvider
 	s.stateMu.Unlock()
+	s.applySettings(params.InitializationOptions)
 
 	s.pendingFolders = params.WorkspaceFolders
 	if len(s.pendingFolders) == 0 {
@@ -57,7 +58,13 @@ func (s *Server) initialize(ctx context.Context, params *protocol.InitializePara
 					"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z",
 				},
 			},
-			DefinitionProvider:     true,
+			CodeActionProvider: &protocol.CodeActionOptions{
+				CodeActionKinds: []protocol.CodeActionKind{protocol.QuickFix},
+			},
+			DefinitionProvider: true,
+			ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
+				Commands: []string{bugReportCommand},
+			},
 			DocumentSymbolProvider: true,
 			HoverProvider:          true,
 			ReferencesProvider:     true,
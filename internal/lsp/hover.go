@@ -44,6 +44,14 @@ func (s *Server) hover(ctx context.Context, params *protocol.HoverParams) (*prot
 		} else if len(classes) > 0 {
 			hover = classesToHover(v, classes)
 		}
+	case *analysis.Attribute:
+		classes := []*analysis.Class{}
+		for _, typeString := range v.GetTypes().Resolve() {
+			classes = append(classes, q.GetClasses(typeString.GetFQN())...)
+		}
+		if len(classes) > 0 {
+			hover = classesToHover(v, classes)
+		}
 	case *analysis.ClassAccess:
 		classes := []*analysis.Class{}
 		interfaces := []*analysis.Interface{}
@@ -132,6 +140,8 @@ func (s *Server) hover(ctx context.Context, params *protocol.HoverParams) (*prot
 		}
 		if len(methods) > 0 {
 			hover = methodsToHover(symbol, methods)
+		} else if magicMethods := q.GetMagicMethods(v.ResolveAndGetScope(resolveCtx), v.MagicMethodName()); len(magicMethods) > 0 {
+			hover = magicMethodsToHover(v, v.MemberName(), magicMethods)
 		}
 	case *analysis.ScopedPropertyAccess:
 		currentClass := document.GetClassScopeAtSymbol(v.Scope)
@@ -161,6 +171,8 @@ func (s *Server) hover(ctx context.Context, params *protocol.HoverParams) (*prot
 		}
 		if len(props) > 0 {
 			hover = propertiesToHover(symbol, props)
+		} else if magicMethods := q.GetMagicMethods(v.ResolveAndGetScope(resolveCtx), analysis.MagicGet); len(magicMethods) > 0 {
+			hover = magicMethodsToHover(v, v.MemberName(), magicMethods)
 		}
 	case *analysis.MethodAccess:
 		currentClass := document.GetClassScopeAtSymbol(v.Scope)
@@ -180,6 +192,8 @@ func (s *Server) hover(ctx context.Context, params *protocol.HoverParams) (*prot
 		}
 		if len(methods) > 0 {
 			hover = methodsToHover(symbol, methods)
+		} else if magicMethods := q.GetMagicMethods(v.ResolveAndGetScope(resolveCtx), analysis.MagicCall); len(magicMethods) > 0 {
+			hover = magicMethodsToHover(v, v.MemberName(), magicMethods)
 		}
 	case *analysis.TypeDeclaration:
 		classes := []*analysis.Class{}
@@ -0,0 +1,394 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/john-nguyen09/phpintel/analysis"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+// LSIFVersion is the version of the LSIF specification of the dumps
+const LSIFVersion = "0.4.3"
+
+// lsifElement is a vertex or an edge of an LSIF dump, only the fields of
+// its label are set
+type lsifElement struct {
+	ID    int    `json:"id"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+
+	Version          string             `json:"version,omitempty"`
+	ProjectRoot      string             `json:"projectRoot,omitempty"`
+	PositionEncoding string             `json:"positionEncoding,omitempty"`
+	ToolInfo         *lsifToolInfo      `json:"toolInfo,omitempty"`
+	Kind             string             `json:"kind,omitempty"`
+	URI              string             `json:"uri,omitempty"`
+	LanguageID       string             `json:"languageId,omitempty"`
+	Start            *protocol.Position `json:"start,omitempty"`
+	End              *protocol.Position `json:"end,omitempty"`
+	Result           *lsifHover         `json:"result,omitempty"`
+	Scheme           string             `json:"scheme,omitempty"`
+	Identifier       string             `json:"identifier,omitempty"`
+	Name             string             `json:"name,omitempty"`
+	Manager          string             `json:"manager,omitempty"`
+
+	OutV     int    `json:"outV,omitempty"`
+	InV      int    `json:"inV,omitempty"`
+	InVs     []int  `json:"inVs,omitempty"`
+	Document int    `json:"document,omitempty"`
+	Property string `json:"property,omitempty"`
+}
+
+type lsifToolInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type lsifHover struct {
+	Contents protocol.MarkupContent `json:"contents"`
+}
+
+// lsifResultSet is the result set of the symbols with the same moniker,
+// the ranges are grouped by the IDs of their documents
+type lsifResultSet struct {
+	id          int
+	fqn         string
+	symbol      analysis.Symbol
+	isExported  bool
+	documents   []int
+	definitions map[int][]int
+	references  map[int][]int
+}
+
+func (r *lsifResultSet) add(ranges map[int][]int, document int, rangeID int) {
+	if _, ok := r.definitions[document]; !ok {
+		if _, ok := r.references[document]; !ok {
+			r.documents = append(r.documents, document)
+		}
+	}
+	ranges[document] = append(ranges[document], rangeID)
+}
+
+// composerPackage is a package of composer.json or vendor/composer/installed.json
+type composerPackage struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	InstallPath string `json:"install-path"`
+	dir         string
+}
+
+type lsifExporter struct {
+	ctx        context.Context
+	store      *analysis.Store
+	q          *analysis.Query
+	rootURI    string
+	writer     *bufio.Writer
+	encoder    *json.Encoder
+	err        error
+	lastID     int
+	resultSets map[string]*lsifResultSet
+	order      []*lsifResultSet
+	packages   []composerPackage
+	project    *composerPackage
+	packageIDs map[string]int
+}
+
+// ExportLSIF writes the LSIF dump of the documents of the store to the
+// writer, the symbols are identified by their FQNs, e.g. \App\Foo::bar(),
+// and the ones of vendor have the composer packages when they are installed
+func ExportLSIF(ctx context.Context, store *analysis.Store, rootPath string, uris []string, version string, out io.Writer) error {
+	writer := bufio.NewWriter(out)
+	e := &lsifExporter{
+		ctx:        ctx,
+		store:      store,
+		q:          analysis.NewQuery(store),
+		rootURI:    util.PathToURI(rootPath),
+		writer:     writer,
+		encoder:    json.NewEncoder(writer),
+		resultSets: map[string]*lsifResultSet{},
+		packageIDs: map[string]int{},
+	}
+	e.readComposer(rootPath)
+	e.emit(lsifElement{
+		Type:             "vertex",
+		Label:            "metaData",
+		Version:          LSIFVersion,
+		ProjectRoot:      e.rootURI,
+		PositionEncoding: "utf-16",
+		ToolInfo:         &lsifToolInfo{Name: "phpintel", Version: version},
+	})
+	projectID := e.emit(lsifElement{Type: "vertex", Label: "project", Kind: "php"})
+	documentIDs := []int{}
+	for _, uri := range uris {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if id, ok := e.exportDocument(uri); ok {
+			documentIDs = append(documentIDs, id)
+		}
+	}
+	if len(documentIDs) > 0 {
+		e.emit(lsifElement{Type: "edge", Label: "contains", OutV: projectID, InVs: documentIDs})
+	}
+	for _, resultSet := range e.order {
+		e.exportResultSet(resultSet)
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return writer.Flush()
+}
+
+func (e *lsifExporter) emit(element lsifElement) int {
+	e.lastID++
+	element.ID = e.lastID
+	if e.err == nil {
+		e.err = e.encoder.Encode(element)
+	}
+	return element.ID
+}
+
+func (e *lsifExporter) exportDocument(uri string) (int, bool) {
+	data, err := e.store.FS.ReadFile(e.ctx, uri)
+	if err != nil {
+		return 0, false
+	}
+	document := analysis.NewDocument(uri, data)
+	document.Load()
+	documentID := e.emit(lsifElement{Type: "vertex", Label: "document", URI: uri, LanguageID: "php"})
+	rangeIDs := map[protocol.Range]int{}
+	contains := []int{}
+	rangeOf := func(r protocol.Range) (int, bool) {
+		if id, ok := rangeIDs[r]; ok {
+			return id, false
+		}
+		start, end := r.Start, r.End
+		id := e.emit(lsifElement{Type: "vertex", Label: "range", Start: &start, End: &end})
+		rangeIDs[r] = id
+		contains = append(contains, id)
+		return id, true
+	}
+	analysis.TraverseDocument(document, func(s analysis.Symbol) {
+		fqn := analysis.SymbolFQN(s)
+		if fqn == "" {
+			return
+		}
+		location := s.GetLocation()
+		if ref, ok := s.(analysis.SymbolReference); ok {
+			location = ref.ReferenceLocation()
+		}
+		rangeID, isNew := rangeOf(location.Range)
+		if !isNew {
+			return
+		}
+		resultSet := e.resultSet(fqn, s, true)
+		e.emit(lsifElement{Type: "edge", Label: "next", OutV: rangeID, InV: resultSet.id})
+		resultSet.add(resultSet.definitions, documentID, rangeID)
+	}, nil)
+	resolveCtx := analysis.NewResolveContext(e.q, document)
+	analysis.TraverseDocument(document, func(s analysis.Symbol) {
+		h, ok := s.(analysis.HasTypes)
+		if !ok || analysis.SymbolFQN(s) != "" {
+			return
+		}
+		r := h.GetLocation().Range
+		for _, definition := range definitionSymbols(e.q, resolveCtx, document, r.Start, h) {
+			fqn := analysis.SymbolFQN(definition)
+			if fqn == "" {
+				continue
+			}
+			rangeID, isNew := rangeOf(r)
+			if !isNew {
+				return
+			}
+			resultSet := e.resultSet(fqn, definition, false)
+			e.emit(lsifElement{Type: "edge", Label: "next", OutV: rangeID, InV: resultSet.id})
+			resultSet.add(resultSet.references, documentID, rangeID)
+			return
+		}
+	}, nil)
+	if len(contains) > 0 {
+		e.emit(lsifElement{Type: "edge", Label: "contains", OutV: documentID, InVs: contains})
+	}
+	return documentID, true
+}
+
+// resultSet returns the result set of the FQN, the definitions of the
+// exported documents take precedence over the ones found by references
+func (e *lsifExporter) resultSet(fqn string, symbol analysis.Symbol, isExported bool) *lsifResultSet {
+	if resultSet, ok := e.resultSets[fqn]; ok {
+		if isExported && !resultSet.isExported {
+			resultSet.symbol = symbol
+			resultSet.isExported = true
+		}
+		return resultSet
+	}
+	resultSet := &lsifResultSet{
+		id:          e.emit(lsifElement{Type: "vertex", Label: "resultSet"}),
+		fqn:         fqn,
+		symbol:      symbol,
+		isExported:  isExported,
+		definitions: map[int][]int{},
+		references:  map[int][]int{},
+	}
+	e.resultSets[fqn] = resultSet
+	e.order = append(e.order, resultSet)
+	return resultSet
+}
+
+func (e *lsifExporter) exportResultSet(resultSet *lsifResultSet) {
+	if contents := e.hover(resultSet.symbol); contents != "" {
+		hoverID := e.emit(lsifElement{
+			Type:   "vertex",
+			Label:  "hoverResult",
+			Result: &lsifHover{Contents: protocol.MarkupContent{Kind: protocol.Markdown, Value: contents}},
+		})
+		e.emit(lsifElement{Type: "edge", Label: "textDocument/hover", OutV: resultSet.id, InV: hoverID})
+	}
+	kind := "import"
+	if resultSet.isExported {
+		kind = "export"
+	}
+	monikerID := e.emit(lsifElement{Type: "vertex", Label: "moniker", Scheme: "php", Identifier: resultSet.fqn, Kind: kind})
+	e.emit(lsifElement{Type: "edge", Label: "moniker", OutV: resultSet.id, InV: monikerID})
+	if packageID, ok := e.packageInformation(resultSet.symbol.GetLocation().URI); ok {
+		e.emit(lsifElement{Type: "edge", Label: "packageInformation", OutV: monikerID, InV: packageID})
+	}
+	if len(resultSet.definitions) > 0 {
+		definitionID := e.emit(lsifElement{Type: "vertex", Label: "definitionResult"})
+		e.emit(lsifElement{Type: "edge", Label: "textDocument/definition", OutV: resultSet.id, InV: definitionID})
+		for _, document := range resultSet.documents {
+			if ranges, ok := resultSet.definitions[document]; ok {
+				e.emit(lsifElement{Type: "edge", Label: "item", OutV: definitionID, InVs: ranges, Document: document})
+			}
+		}
+	}
+	referenceID := e.emit(lsifElement{Type: "vertex", Label: "referenceResult"})
+	e.emit(lsifElement{Type: "edge", Label: "textDocument/references", OutV: resultSet.id, InV: referenceID})
+	for _, document := range resultSet.documents {
+		if ranges, ok := resultSet.definitions[document]; ok {
+			e.emit(lsifElement{Type: "edge", Label: "item", OutV: referenceID, InVs: ranges, Document: document, Property: "definitions"})
+		}
+		if ranges, ok := resultSet.references[document]; ok {
+			e.emit(lsifElement{Type: "edge", Label: "item", OutV: referenceID, InVs: ranges, Document: document, Property: "references"})
+		}
+	}
+}
+
+// hover returns the same hover text as the language server
+func (e *lsifExporter) hover(s analysis.Symbol) string {
+	var sb *strings.Builder
+	switch v := s.(type) {
+	case *analysis.Class:
+		sb = formatClasses([]*analysis.Class{v})
+	case *analysis.Interface:
+		sb = formatInterfaces([]*analysis.Interface{v})
+	case *analysis.Trait:
+		sb = formatTraits([]*analysis.Trait{v})
+	case *analysis.Enum:
+		sb = &strings.Builder{}
+		formatEnum(sb, v)
+	case *analysis.Function:
+		sb = formatFunctions([]*analysis.Function{v})
+	case *analysis.Const:
+		sb = formatConsts([]*analysis.Const{v})
+	case *analysis.Define:
+		sb = formatDefines([]*analysis.Define{v})
+	case *analysis.Method:
+		sb = formatMethods([]analysis.MethodWithScope{{Method: v, Scope: e.scope(v.Scope)}})
+	case *analysis.Property:
+		sb = formatProperties([]analysis.PropWithScope{{Prop: v, Scope: e.scope(v.Scope)}})
+	case *analysis.ClassConst:
+		sb = formatClassConsts([]analysis.ClassConstWithScope{{Const: v, Scope: e.scope(v.Scope)}})
+	default:
+		return ""
+	}
+	return sb.String()
+}
+
+func (e *lsifExporter) scope(name analysis.TypeString) analysis.Symbol {
+	for _, class := range e.q.GetClasses(name.GetFQN()) {
+		return class
+	}
+	for _, theInterface := range e.q.GetInterfaces(name.GetFQN()) {
+		return theInterface
+	}
+	for _, trait := range e.q.GetTraits(name.GetFQN()) {
+		return trait
+	}
+	return nil
+}
+
+// readComposer reads the packages of the project and the installed ones,
+// both the composer 1 and composer 2 formats of installed.json are read
+func (e *lsifExporter) readComposer(rootPath string) {
+	if data, err := ioutil.ReadFile(filepath.Join(rootPath, "composer.json")); err == nil {
+		project := composerPackage{}
+		if json.Unmarshal(data, &project) == nil && project.Name != "" {
+			e.project = &project
+		}
+	}
+	data, err := ioutil.ReadFile(filepath.Join(rootPath, "vendor", "composer", "installed.json"))
+	if err != nil {
+		return
+	}
+	installed := struct {
+		Packages []composerPackage `json:"packages"`
+	}{}
+	if json.Unmarshal(data, &installed) != nil {
+		if json.Unmarshal(data, &installed.Packages) != nil {
+			return
+		}
+	}
+	for _, p := range installed.Packages {
+		if p.Name == "" {
+			continue
+		}
+		p.dir = "vendor/" + p.Name
+		if p.InstallPath != "" {
+			p.dir = path.Clean(path.Join("vendor/composer", filepath.ToSlash(p.InstallPath)))
+		}
+		e.packages = append(e.packages, p)
+	}
+	sort.Slice(e.packages, func(i, j int) bool {
+		return len(e.packages[i].dir) > len(e.packages[j].dir)
+	})
+}
+
+// packageInformation emits the package of the URI once and returns its ID,
+// the URIs outside of the root, e.g. the stubs, have no packages
+func (e *lsifExporter) packageInformation(uri string) (int, bool) {
+	if !strings.HasPrefix(uri, e.rootURI+"/") {
+		return 0, false
+	}
+	relative := strings.TrimPrefix(uri, e.rootURI+"/")
+	var p *composerPackage
+	if analysis.IsVendorURI("/" + relative) {
+		for i := range e.packages {
+			if strings.HasPrefix(relative, e.packages[i].dir+"/") {
+				p = &e.packages[i]
+				break
+			}
+		}
+	} else {
+		p = e.project
+	}
+	if p == nil {
+		return 0, false
+	}
+	if id, ok := e.packageIDs[p.Name]; ok {
+		return id, true
+	}
+	id := e.emit(lsifElement{Type: "vertex", Label: "packageInformation", Name: p.Name, Manager: "composer", Version: p.Version})
+	e.packageIDs[p.Name] = id
+	return id, true
+}
@@ -0,0 +1,91 @@
+package lsp
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/john-nguyen09/phpintel/internal/jsonrpc2"
+	"github.com/john-nguyen09/phpintel/util"
+)
+
+type requestKeyType int
+
+const requestKey requestKeyType = 0
+
+// inFlightRequest is a request which has been received but not yet replied
+type inFlightRequest struct {
+	ID      string        `json:"id"`
+	Method  string        `json:"method"`
+	Started time.Time     `json:"started"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// requestMetrics records the latencies of the received requests and
+// notifications by their methods, and the requests which are in flight
+type requestMetrics struct {
+	jsonrpc2.EmptyHandler
+
+	latencies *util.Histograms
+	mu        sync.Mutex
+	nextID    int
+	inFlight  map[int]*inFlightRequest
+}
+
+func newRequestMetrics() *requestMetrics {
+	return &requestMetrics{
+		latencies: util.NewHistograms(),
+		inFlight:  map[int]*inFlightRequest{},
+	}
+}
+
+func (m *requestMetrics) Request(ctx context.Context, conn *jsonrpc2.Conn, direction jsonrpc2.Direction, r *jsonrpc2.WireRequest) context.Context {
+	if direction != jsonrpc2.Receive {
+		return ctx
+	}
+	request := &inFlightRequest{
+		Method:  r.Method,
+		Started: time.Now(),
+	}
+	if r.ID != nil {
+		request.ID = r.ID.String()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	m.inFlight[m.nextID] = request
+	return context.WithValue(ctx, requestKey, m.nextID)
+}
+
+func (m *requestMetrics) Done(ctx context.Context, err error) {
+	key, ok := ctx.Value(requestKey).(int)
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	request, ok := m.inFlight[key]
+	delete(m.inFlight, key)
+	m.mu.Unlock()
+	if ok {
+		m.latencies.Observe(request.Method, time.Since(request.Started))
+	}
+}
+
+// inFlightRequests returns the requests which are being handled, the oldest
+// comes first
+func (m *requestMetrics) inFlightRequests() []inFlightRequest {
+	now := time.Now()
+	m.mu.Lock()
+	requests := make([]inFlightRequest, 0, len(m.inFlight))
+	for _, request := range m.inFlight {
+		r := *request
+		r.Elapsed = now.Sub(r.Started)
+		requests = append(requests, r)
+	}
+	m.mu.Unlock()
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].Started.Before(requests[j].Started)
+	})
+	return requests
+}
@@ -13,6 +13,7 @@ const (
 	versionKey    = contextKey(iota)
 	memprofileKey = contextKey(iota)
 	cpuprofileKey = contextKey(iota)
+	dataDirKey    = contextKey(iota)
 )
 
 func WithClient(ctx context.Context, client Client) context.Context {
@@ -31,6 +32,11 @@ func WithCpuprofile(ctx context.Context, cpuprofile string) context.Context {
 	return context.WithValue(ctx, cpuprofileKey, cpuprofile)
 }
 
+// WithDataDir overrides the directory of the storages of the workspace folders
+func WithDataDir(ctx context.Context, dataDir string) context.Context {
+	return context.WithValue(ctx, dataDirKey, dataDir)
+}
+
 func GetVersion(ctx context.Context) string {
 	value := ctx.Value(versionKey)
 	if version, ok := value.(string); ok {
@@ -54,3 +60,11 @@ func GetCpuprofile(ctx context.Context) string {
 	}
 	return ""
 }
+
+func GetDataDir(ctx context.Context) string {
+	value := ctx.Value(dataDirKey)
+	if dataDir, ok := value.(string); ok {
+		return dataDir
+	}
+	return ""
+}
@@ -58,6 +58,9 @@ type Server interface {
 	PrepareRename(context.Context, *PrepareRenameParams) (*Range, error)
 	ExecuteCommand(context.Context, *ExecuteCommandParams) (interface{}, error)
 	DocumentSignatures(context.Context, *TextDocumentIdentifier) ([]TextEdit, error)
+	DumpDocument(context.Context, *TextDocumentIdentifier) (interface{}, error)
+	ExplainPosition(context.Context, *TextDocumentPositionParams) (interface{}, error)
+	StoreStats(context.Context) (interface{}, error)
 }
 
 func (h serverHandler) Deliver(ctx context.Context, r *jsonrpc2.Request, delivered bool) bool {
@@ -538,6 +541,34 @@ func (h serverHandler) Deliver(ctx context.Context, r *jsonrpc2.Request, deliver
 			handleError(err)
 		}
 		return true
+	case "phpintel/dumpDocument": // req
+		var params TextDocumentIdentifier
+		if err := json.Unmarshal(*r.Params, &params); err != nil {
+			sendParseError(ctx, r, err)
+			return true
+		}
+		resp, err := h.server.DumpDocument(ctx, &params)
+		if err := r.Reply(ctx, resp, err); err != nil {
+			handleError(err)
+		}
+		return true
+	case "phpintel/explainPosition": // req
+		var params TextDocumentPositionParams
+		if err := json.Unmarshal(*r.Params, &params); err != nil {
+			sendParseError(ctx, r, err)
+			return true
+		}
+		resp, err := h.server.ExplainPosition(ctx, &params)
+		if err := r.Reply(ctx, resp, err); err != nil {
+			handleError(err)
+		}
+		return true
+	case "phpintel/storeStats": // req
+		resp, err := h.server.StoreStats(ctx)
+		if err := r.Reply(ctx, resp, err); err != nil {
+			handleError(err)
+		}
+		return true
 
 	default:
 		return false
@@ -19,6 +19,8 @@ func classScopeFQNAt(document *analysis.Document, pos protocol.Position) string
 			scopeFQN = c.Name.GetFQN()
 		case *analysis.Trait:
 			scopeFQN = c.Name.GetFQN()
+		case *analysis.Enum:
+			scopeFQN = c.Name.GetFQN()
 		}
 	}
 	return scopeFQN
@@ -0,0 +1,239 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/john-nguyen09/phpintel/internal/jsonrpc2"
+	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+)
+
+// ReplayTimeout is how long a replayed message may take to be handled
+var ReplayTimeout = time.Minute
+
+// ReplayResult is the outcome of a replayed session
+type ReplayResult struct {
+	Messages   int              `json:"messages"`
+	Requests   int              `json:"requests"`
+	Mismatches []ReplayMismatch `json:"mismatches"`
+}
+
+// ReplayMismatch is a request whose replayed response differs from the
+// recorded one
+type ReplayMismatch struct {
+	ID       string          `json:"id"`
+	Method   string          `json:"method"`
+	Expected json.RawMessage `json:"expected"`
+	Actual   json.RawMessage `json:"actual"`
+}
+
+// replayMessage has the fields of every kind of jsonrpc2 messages
+type replayMessage struct {
+	ID     *json.RawMessage `json:"id,omitempty"`
+	Method string           `json:"method,omitempty"`
+	Result *json.RawMessage `json:"result,omitempty"`
+	Error  *jsonrpc2.Error  `json:"error,omitempty"`
+}
+
+func (m replayMessage) key() string {
+	if m.ID == nil {
+		return ""
+	}
+	var id interface{}
+	if err := json.Unmarshal(*m.ID, &id); err != nil {
+		return string(*m.ID)
+	}
+	return fmt.Sprint(id)
+}
+
+// outcome is the result or the error of a response as canonical JSON
+func (m replayMessage) outcome() json.RawMessage {
+	var value interface{}
+	if m.Error != nil {
+		value = map[string]interface{}{"error": m.Error}
+	} else if m.Result != nil {
+		json.Unmarshal(*m.Result, &value)
+	}
+	data, _ := json.Marshal(value)
+	return data
+}
+
+type replayKeyType int
+
+const replayKey replayKeyType = 0
+
+// replayHandler signals when a message of the replaying client has been
+// handled by the server
+type replayHandler struct {
+	jsonrpc2.EmptyHandler
+	done chan struct{}
+}
+
+func (h *replayHandler) Request(ctx context.Context, conn *jsonrpc2.Conn, direction jsonrpc2.Direction, r *jsonrpc2.WireRequest) context.Context {
+	return context.WithValue(ctx, replayKey, direction == jsonrpc2.Receive)
+}
+
+func (h *replayHandler) Done(ctx context.Context, err error) {
+	if received, _ := ctx.Value(replayKey).(bool); received {
+		h.done <- struct{}{}
+	}
+}
+
+// replayClient answers the requests of the server with the recorded
+// responses of the client and collects the responses of the server
+type replayClient struct {
+	stream jsonrpc2.Stream
+
+	mu        sync.Mutex
+	answers   map[string][]*json.RawMessage
+	responses map[string]chan replayMessage
+}
+
+func (c *replayClient) response(key string) chan replayMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	response, ok := c.responses[key]
+	if !ok {
+		response = make(chan replayMessage, 1)
+		c.responses[key] = response
+	}
+	return response
+}
+
+func (c *replayClient) run(ctx context.Context) {
+	for {
+		data, _, err := c.stream.Read(ctx)
+		if err != nil {
+			return
+		}
+		var message replayMessage
+		if err := json.Unmarshal(data, &message); err != nil || message.ID == nil {
+			continue
+		}
+		if message.Method == "" {
+			c.response(message.key()) <- message
+			continue
+		}
+		c.mu.Lock()
+		var result *json.RawMessage
+		if answers := c.answers[message.Method]; len(answers) > 0 {
+			result, c.answers[message.Method] = answers[0], answers[1:]
+		}
+		c.mu.Unlock()
+		reply, _ := json.Marshal(struct {
+			VersionTag jsonrpc2.VersionTag `json:"jsonrpc"`
+			ID         *json.RawMessage    `json:"id"`
+			Result     *json.RawMessage    `json:"result"`
+		}{ID: message.ID, Result: result})
+		c.stream.Write(ctx, reply)
+	}
+}
+
+// Replay drives a new server with the messages which the recorded server
+// received, one at a time, and compares its responses with the recorded
+// ones. The requests of the server are answered with the recorded answers
+// of the client, and the indexing is waited for after every message so the
+// replay does not depend on the timing of the recorded session. Only the
+// responses are compared, the notifications of the server are not, so the
+// debounced textDocument/publishDiagnostics are not waited for. The storage
+// of the server is temporary.
+func Replay(ctx context.Context, messages []jsonrpc2.RecordedMessage) (ReplayResult, error) {
+	result := ReplayResult{Mismatches: []ReplayMismatch{}}
+	dataDir, err := ioutil.TempDir("", "phpintel-replay")
+	if err != nil {
+		return result, err
+	}
+	defer os.RemoveAll(dataDir)
+	ctx, cancel := context.WithCancel(protocol.WithDataDir(ctx, dataDir))
+	defer cancel()
+
+	serverRead, clientWrite := io.Pipe()
+	clientRead, serverWrite := io.Pipe()
+	defer clientWrite.Close()
+	defer serverWrite.Close()
+	client := &replayClient{
+		stream:    jsonrpc2.NewStream(clientRead, clientWrite),
+		answers:   map[string][]*json.RawMessage{},
+		responses: map[string]chan replayMessage{},
+	}
+	expected := map[string]replayMessage{}
+	serverRequests := map[string]string{}
+	for _, recorded := range messages {
+		var message replayMessage
+		if err := json.Unmarshal(recorded.Message, &message); err != nil || message.ID == nil {
+			continue
+		}
+		switch {
+		case recorded.Direction == jsonrpc2.Send.String() && message.Method != "":
+			serverRequests[message.key()] = message.Method
+		case recorded.Direction == jsonrpc2.Send.String():
+			expected[message.key()] = message
+		case message.Method == "":
+			if method, ok := serverRequests[message.key()]; ok {
+				client.answers[method] = append(client.answers[method], message.Result)
+			}
+		}
+	}
+
+	ctx, srv := NewServer(ctx, jsonrpc2.NewStream(serverRead, serverWrite))
+	handler := &replayHandler{done: make(chan struct{}, 1)}
+	srv.Conn.AddHandler(handler)
+	go srv.Run(ctx)
+	go client.run(ctx)
+	shutdown := false
+	for _, recorded := range messages {
+		if recorded.Direction != jsonrpc2.Receive.String() {
+			continue
+		}
+		var message replayMessage
+		if err := json.Unmarshal(recorded.Message, &message); err != nil || message.Method == "" {
+			continue
+		}
+		if message.Method == "exit" {
+			break
+		}
+		shutdown = shutdown || message.Method == "shutdown"
+		result.Messages++
+		if _, err := client.stream.Write(ctx, recorded.Message); err != nil {
+			return result, err
+		}
+		select {
+		case <-handler.done:
+		case <-time.After(ReplayTimeout):
+			return result, fmt.Errorf("timed out handling %s", message.Method)
+		}
+		srv.store.indexing.Wait()
+		if message.ID == nil {
+			continue
+		}
+		want, ok := expected[message.key()]
+		if !ok {
+			continue
+		}
+		result.Requests++
+		var got replayMessage
+		select {
+		case got = <-client.response(message.key()):
+		case <-time.After(ReplayTimeout):
+			return result, fmt.Errorf("timed out waiting for the response of %s", message.Method)
+		}
+		if string(want.outcome()) != string(got.outcome()) {
+			result.Mismatches = append(result.Mismatches, ReplayMismatch{
+				ID:       message.key(),
+				Method:   message.Method,
+				Expected: want.outcome(),
+				Actual:   got.outcome(),
+			})
+		}
+	}
+	if !shutdown {
+		srv.store.close()
+	}
+	return result, nil
+}
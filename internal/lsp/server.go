@@ -10,6 +10,7 @@ import (
 	"net"
 	"runtime/pprof"
 	"sync"
+	"time"
 
 	"github.com/john-nguyen09/phpintel/analysis"
 	"github.com/john-nguyen09/phpintel/internal/jsonrpc2"
@@ -19,10 +20,15 @@ import (
 // NewServer starts an LSP server on the supplied stream, and waits until the
 // stream is closed.
 func NewServer(ctx context.Context, stream jsonrpc2.Stream) (context.Context, *Server) {
-	s := &Server{}
+	s := &Server{
+		started: time.Now(),
+		metrics: newRequestMetrics(),
+	}
 	store := newWorkspaceStore(ctx, s)
 	s.store = store
 	ctx, s.Conn, s.client = protocol.NewServer(ctx, stream, s)
+	s.Conn.AddHandler(s.metrics)
+	s.Conn.AddHandler(&panicHandler{server: s})
 	return ctx, s
 }
 
@@ -68,9 +74,15 @@ type Server struct {
 	stateMu sync.Mutex
 	state   serverState
 	store   *workspaceStore
+	started time.Time
+	metrics *requestMetrics
+
+	crashMu   sync.Mutex
+	lastCrash *crashReport
 
 	pendingFolders          []protocol.WorkspaceFolder
 	fileExtensionsSupported bool
+	diagnosticSeverities    map[string]protocol.DiagnosticSeverity
 }
 
 func baseSearchOptions() analysis.SearchOptions {
@@ -110,8 +122,9 @@ func (s *Server) DidChangeWorkspaceFolders(ctx context.Context, params *protocol
 	return nil
 }
 
-func (s *Server) DidChangeConfiguration(context.Context, *protocol.DidChangeConfigurationParams) error {
-	return notImplemented("DidChangeConfiguration")
+func (s *Server) DidChangeConfiguration(ctx context.Context, params *protocol.DidChangeConfigurationParams) error {
+	s.applySettings(params.Settings)
+	return nil
 }
 
 func (s *Server) DidChangeWatchedFiles(ctx context.Context, params *protocol.DidChangeWatchedFilesParams) error {
@@ -123,7 +136,7 @@ func (s *Server) Symbol(ctx context.Context, params *protocol.WorkspaceSymbolPar
 }
 
 func (s *Server) ExecuteCommand(ctx context.Context, params *protocol.ExecuteCommandParams) (interface{}, error) {
-	return nil, notImplemented("ExecuteCommand")
+	return s.executeCommand(ctx, params)
 }
 
 // Text Synchronization
@@ -195,7 +208,7 @@ func (s *Server) DocumentSymbol(ctx context.Context, params *protocol.DocumentSy
 }
 
 func (s *Server) CodeAction(ctx context.Context, params *protocol.CodeActionParams) ([]protocol.CodeAction, error) {
-	return nil, notImplemented("CodeAction")
+	return s.codeAction(ctx, params)
 }
 
 func (s *Server) CodeLens(context.Context, *protocol.CodeLensParams) ([]protocol.CodeLens, error) {
@@ -271,6 +284,18 @@ func (s *Server) DocumentSignatures(ctx context.Context, params *protocol.TextDo
 	return s.documentSignatures(ctx, params)
 }
 
+func (s *Server) DumpDocument(ctx context.Context, params *protocol.TextDocumentIdentifier) (interface{}, error) {
+	return s.dumpDocument(ctx, params)
+}
+
+func (s *Server) ExplainPosition(ctx context.Context, params *protocol.TextDocumentPositionParams) (interface{}, error) {
+	return s.explainPosition(ctx, params)
+}
+
+func (s *Server) StoreStats(ctx context.Context) (interface{}, error) {
+	return s.storeStats(ctx)
+}
+
 func notImplemented(method string) *jsonrpc2.Error {
 	return jsonrpc2.NewErrorf(jsonrpc2.CodeMethodNotFound, "method %q not yet implemented", method)
 }
@@ -98,9 +98,14 @@ func hasParamToSignatureInformation(hasParam analysis.HasParams) protocol.Signat
 	for _, param := range hasParam.GetParams() {
 		label := ""
 		if !param.Type.IsEmpty() {
-			label += param.Type.ToString() + " "
+			label += param.Type.ToString()
+		}
+		if param.Name != "" {
+			if label != "" {
+				label += " "
+			}
+			label += param.Name
 		}
-		label += param.Name
 		if param.Value != "" {
 			label += " = " + param.Value
 		}
@@ -70,8 +70,12 @@ func symbolToProtocolDocumentSymbol(symbol analysis.Symbol) (protocol.DocumentSy
 			SelectionRange: v.GetLocation().Range,
 		}, ""
 	case *analysis.ClassConst:
+		kind := protocol.Constant
+		if v.IsEnumCase() {
+			kind = protocol.EnumMember
+		}
 		return protocol.DocumentSymbol{
-			Kind:           protocol.Constant,
+			Kind:           kind,
 			Name:           v.GetName(),
 			Detail:         v.GetDescription(),
 			Range:          v.GetLocation().Range,
@@ -125,6 +129,14 @@ func symbolToProtocolDocumentSymbol(symbol analysis.Symbol) (protocol.DocumentSy
 			Range:          v.GetLocation().Range,
 			SelectionRange: v.GetLocation().Range,
 		}, v.Name.GetFQN()
+	case *analysis.Enum:
+		return protocol.DocumentSymbol{
+			Kind:           protocol.Enum,
+			Name:           v.GetName(),
+			Detail:         v.GetDescription(),
+			Range:          v.GetLocation().Range,
+			SelectionRange: v.GetLocation().Range,
+		}, v.Name.GetFQN()
 	}
 	return protocol.DocumentSymbol{}, ""
 }
@@ -209,6 +221,14 @@ func (s *Server) workspaceSymbol(ctx context.Context, params *protocol.Workspace
 					Location: trait.GetLocation(),
 				})
 			}
+			enums, _ := store.SearchEnums(params.Query, opts)
+			for _, enum := range enums {
+				symbols = append(symbols, protocol.SymbolInformation{
+					Kind:     protocol.Enum,
+					Name:     enum.Name.GetFQN(),
+					Location: enum.GetLocation(),
+				})
+			}
 		}
 	}
 	return symbols, nil
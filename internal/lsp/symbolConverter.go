@@ -74,6 +74,10 @@ func concatParams(sb *strings.Builder, params []*analysis.Parameter) {
 func formatClasses(classes []*analysis.Class) *strings.Builder {
 	sb := &strings.Builder{}
 	for _, class := range classes {
+		if enum := class.Enum(); enum != nil {
+			formatEnum(sb, enum)
+			continue
+		}
 		wrapPHPCode(sb, func(sb *strings.Builder) {
 			sb.WriteString("class ")
 			sb.WriteString(class.Name.GetOriginal())
@@ -96,6 +100,27 @@ func formatClasses(classes []*analysis.Class) *strings.Builder {
 	return sb
 }
 
+func formatEnum(sb *strings.Builder, enum *analysis.Enum) {
+	wrapPHPCode(sb, func(sb *strings.Builder) {
+		sb.WriteString("enum ")
+		sb.WriteString(enum.Name.GetOriginal())
+		if enum.IsBacked() {
+			sb.WriteString(": ")
+			sb.WriteString(enum.BackedType.GetOriginal())
+		}
+		if len(enum.Interfaces) > 0 {
+			implements := []string{}
+			for _, implement := range enum.Interfaces {
+				implements = append(implements, implement.GetOriginal())
+			}
+			sb.WriteString(" implements ")
+			sb.WriteString(strings.Join(implements, ", "))
+		}
+	})
+	concatDescriptionIfAvailable(sb, enum.GetDescription(), true)
+	writeHorLine(sb)
+}
+
 func classesToHover(ref analysis.HasTypes, classes []*analysis.Class) *protocol.Hover {
 	sb := formatClasses(classes)
 	theRange := ref.GetLocation().Range
@@ -179,7 +204,7 @@ func formatDefines(defines []*analysis.Define) *strings.Builder {
 	return sb
 }
 
-func functionsToHover(ref analysis.HasTypes, functions []*analysis.Function) *protocol.Hover {
+func formatFunctions(functions []*analysis.Function) *strings.Builder {
 	sb := &strings.Builder{}
 	for _, fn := range functions {
 		wrapPHPCode(sb, func(sb *strings.Builder) {
@@ -196,6 +221,11 @@ func functionsToHover(ref analysis.HasTypes, functions []*analysis.Function) *pr
 		concatDescriptionIfAvailable(sb, fn.GetDescription(), true)
 		writeHorLine(sb)
 	}
+	return sb
+}
+
+func functionsToHover(ref analysis.HasTypes, functions []*analysis.Function) *protocol.Hover {
+	sb := formatFunctions(functions)
 	theRange := ref.GetLocation().Range
 	return &protocol.Hover{
 		Contents: protocol.MarkupContent{
@@ -206,7 +236,7 @@ func functionsToHover(ref analysis.HasTypes, functions []*analysis.Function) *pr
 	}
 }
 
-func traitsToHover(ref analysis.HasTypes, traits []*analysis.Trait) *protocol.Hover {
+func formatTraits(traits []*analysis.Trait) *strings.Builder {
 	sb := &strings.Builder{}
 	for _, trait := range traits {
 		wrapPHPCode(sb, func(sb *strings.Builder) {
@@ -216,6 +246,11 @@ func traitsToHover(ref analysis.HasTypes, traits []*analysis.Trait) *protocol.Ho
 		concatDescriptionIfAvailable(sb, trait.GetDescription(), true)
 		writeHorLine(sb)
 	}
+	return sb
+}
+
+func traitsToHover(ref analysis.HasTypes, traits []*analysis.Trait) *protocol.Hover {
+	sb := formatTraits(traits)
 	theRange := ref.GetLocation().Range
 	return &protocol.Hover{
 		Contents: protocol.MarkupContent{
@@ -226,14 +261,18 @@ func traitsToHover(ref analysis.HasTypes, traits []*analysis.Trait) *protocol.Ho
 	}
 }
 
-func classConstsToHover(ref analysis.HasTypes, classConsts []analysis.ClassConstWithScope) *protocol.Hover {
+func formatClassConsts(classConsts []analysis.ClassConstWithScope) *strings.Builder {
 	sb := &strings.Builder{}
 	for _, c := range classConsts {
 		if c.Const == nil {
 			continue
 		}
 		wrapPHPCode(sb, func(sb *strings.Builder) {
-			sb.WriteString("const ")
+			if c.Const.IsEnumCase() {
+				sb.WriteString("case ")
+			} else {
+				sb.WriteString("const ")
+			}
 			sb.WriteString(c.Const.Name)
 			if len(c.Const.Value) > 0 {
 				sb.WriteString(" = ")
@@ -244,6 +283,11 @@ func classConstsToHover(ref analysis.HasTypes, classConsts []analysis.ClassConst
 		concatDescriptionIfAvailable(sb, c.Const.GetDescription(), true)
 		writeHorLine(sb)
 	}
+	return sb
+}
+
+func classConstsToHover(ref analysis.HasTypes, classConsts []analysis.ClassConstWithScope) *protocol.Hover {
+	sb := formatClassConsts(classConsts)
 	theRange := ref.GetLocation().Range
 	return &protocol.Hover{
 		Contents: protocol.MarkupContent{
@@ -262,6 +306,9 @@ func concatScopeIfAvailable(sb *strings.Builder, scope analysis.Symbol, writeLin
 	switch v := scope.(type) {
 	case *analysis.Class:
 		scopeKind = "class"
+		if v.Enum() != nil {
+			scopeKind = "enum"
+		}
 		scopeName = v.Name.GetFQN()
 	case *analysis.Interface:
 		scopeKind = "interface"
@@ -288,7 +335,7 @@ func concatVisibility(sb *strings.Builder, visibility analysis.VisibilityModifie
 	}
 }
 
-func methodsToHover(ref analysis.HasTypes, methods []analysis.MethodWithScope) *protocol.Hover {
+func formatMethods(methods []analysis.MethodWithScope) *strings.Builder {
 	sb := &strings.Builder{}
 	for _, m := range methods {
 		method := m.Method
@@ -314,6 +361,11 @@ func methodsToHover(ref analysis.HasTypes, methods []analysis.MethodWithScope) *
 		concatDescriptionIfAvailable(sb, method.GetDescription(), true)
 		writeHorLine(sb)
 	}
+	return sb
+}
+
+func methodsToHover(ref analysis.HasTypes, methods []analysis.MethodWithScope) *protocol.Hover {
+	sb := formatMethods(methods)
 	theRange := ref.GetLocation().Range
 	return &protocol.Hover{
 		Contents: protocol.MarkupContent{
@@ -324,7 +376,36 @@ func methodsToHover(ref analysis.HasTypes, methods []analysis.MethodWithScope) *
 	}
 }
 
-func propertiesToHover(ref analysis.HasTypes, properties []analysis.PropWithScope) *protocol.Hover {
+// magicMethodsToHover shows the member which is handled by magic methods,
+// e.g. __get or __call
+func magicMethodsToHover(ref analysis.HasTypes, memberName string, magicMethods []analysis.MethodWithScope) *protocol.Hover {
+	sb := &strings.Builder{}
+	for _, m := range magicMethods {
+		wrapPHPCode(sb, func(sb *strings.Builder) {
+			sb.WriteString(memberName)
+			if !m.Method.GetReturnTypes().IsEmpty() {
+				sb.WriteString(": ")
+				sb.WriteString(m.Method.GetReturnTypes().ToString())
+			}
+		})
+		writeHorLine(sb)
+		sb.WriteString("via `")
+		sb.WriteString(m.Method.GetName())
+		sb.WriteString("`")
+		concatScopeIfAvailable(sb, m.Scope, false)
+		writeHorLine(sb)
+	}
+	theRange := ref.GetLocation().Range
+	return &protocol.Hover{
+		Contents: protocol.MarkupContent{
+			Kind:  protocol.Markdown,
+			Value: sb.String(),
+		},
+		Range: &theRange,
+	}
+}
+
+func formatProperties(properties []analysis.PropWithScope) *strings.Builder {
 	sb := &strings.Builder{}
 	for _, p := range properties {
 		property := p.Prop
@@ -333,6 +414,9 @@ func propertiesToHover(ref analysis.HasTypes, properties []analysis.PropWithScop
 			if property.IsStatic() {
 				sb.WriteString(" static")
 			}
+			if property.IsReadonly() {
+				sb.WriteString(" readonly")
+			}
 			sb.WriteString(" ")
 			sb.WriteString(property.GetName())
 			if !property.Types.IsEmpty() {
@@ -344,6 +428,11 @@ func propertiesToHover(ref analysis.HasTypes, properties []analysis.PropWithScop
 		concatDescriptionIfAvailable(sb, property.GetDescription(), true)
 		writeHorLine(sb)
 	}
+	return sb
+}
+
+func propertiesToHover(ref analysis.HasTypes, properties []analysis.PropWithScope) *protocol.Hover {
+	sb := formatProperties(properties)
 	theRange := ref.GetLocation().Range
 	return &protocol.Hover{
 		Contents: protocol.MarkupContent{
@@ -473,6 +562,20 @@ func classToCompletionItem(class *analysis.Class, label string, textEdit *protoc
 	}
 }
 
+func enumToCompletionItem(enum *analysis.Enum, label string, textEdit *protocol.TextEdit) protocol.CompletionItem {
+	textEdits := []protocol.TextEdit{}
+	if textEdit != nil {
+		textEdits = append(textEdits, *textEdit)
+	}
+	return protocol.CompletionItem{
+		Kind:                protocol.EnumCompletion,
+		Label:               label,
+		Documentation:       descriptionToMarkupContent(enum.GetDescription()),
+		AdditionalTextEdits: textEdits,
+		Detail:              getDetailFromTextEdit(enum.Name, textEdit),
+	}
+}
+
 func interfaceToCompletionItem(intf *analysis.Interface, label string, textEdit *protocol.TextEdit) protocol.CompletionItem {
 	textEdits := []protocol.TextEdit{}
 	if textEdit != nil {
@@ -532,8 +635,12 @@ func propToCompletionItem(p analysis.PropWithScope) protocol.CompletionItem {
 
 func classConstToCompletionItem(c analysis.ClassConstWithScope) protocol.CompletionItem {
 	classConst := c.Const
+	kind := protocol.ConstantCompletion
+	if classConst.IsEnumCase() {
+		kind = protocol.EnumMemberCompletion
+	}
 	return protocol.CompletionItem{
-		Kind:          protocol.ConstantCompletion,
+		Kind:          kind,
 		Label:         classConst.GetName(),
 		Documentation: descriptionToMarkupContent(classConst.GetDescription()),
 	}
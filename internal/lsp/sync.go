@@ -5,10 +5,12 @@ import (
 	"io/fs"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
 
+	"github.com/john-nguyen09/phpintel/analysis"
 	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
 	"github.com/john-nguyen09/phpintel/util"
 )
@@ -41,12 +43,20 @@ func (s *Server) didClose(ctx context.Context, params *protocol.DidCloseTextDocu
 }
 
 func (s *Server) didChangeWatchedFiles(ctx context.Context, params *protocol.DidChangeWatchedFilesParams) error {
+	s.store.indexing.Add(1)
 	go func() {
+		defer s.store.indexing.Done()
 		var wg sync.WaitGroup
 		changes := append(params.Changes[:0:0], params.Changes...)
 		for _, change := range changes {
+			if path.Base(change.URI) == analysis.BaselineFileName {
+				if store := s.store.getStore(change.URI); store != nil {
+					s.store.loadBaseline(store)
+				}
+				continue
+			}
 			if change.Type == protocol.Deleted {
-				s.store.deleteJobs <- change.URI
+				s.store.queueDeleteJob(change.URI)
 				continue
 			}
 
@@ -65,11 +75,11 @@ func (s *Server) didChangeWatchedFiles(ctx context.Context, params *protocol.Did
 
 			if matched && !stats.IsDir() {
 				wg.Add(1)
-				s.store.createJobs <- creatorJob{
+				s.store.queueCreateJob(creatorJob{
 					uri:       change.URI,
 					ctx:       ctx,
 					waitGroup: &wg,
-				}
+				})
 				continue
 			}
 
@@ -83,11 +93,11 @@ func (s *Server) didChangeWatchedFiles(ctx context.Context, params *protocol.Did
 				}
 				if !d.IsDir() && strings.HasSuffix(path, ".php") {
 					wg.Add(1)
-					s.store.createJobs <- creatorJob{
+					s.store.queueCreateJob(creatorJob{
 						uri:       util.PathToURI(path),
 						ctx:       ctx,
 						waitGroup: &wg,
-					}
+					})
 				}
 				return nil
 			})
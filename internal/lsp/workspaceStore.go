@@ -7,11 +7,13 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/john-nguyen09/phpintel/analysis"
 	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
 	"github.com/john-nguyen09/phpintel/util"
+	cmap "github.com/orcaman/concurrent-map"
 )
 
 const numCreators int = 2
@@ -29,6 +31,16 @@ type workspaceStore struct {
 	stores     []*analysis.Store
 	createJobs chan creatorJob
 	deleteJobs chan string
+	baselines  cmap.ConcurrentMap
+
+	// pendingCreates and pendingDeletes are the numbers of the jobs which
+	// are waiting for a creator or a deletor
+	pendingCreates int64
+	pendingDeletes int64
+
+	// indexing waits for the folders and the watched files which are being
+	// indexed in the background
+	indexing sync.WaitGroup
 }
 
 func newWorkspaceStore(ctx context.Context, server *Server) *workspaceStore {
@@ -38,6 +50,7 @@ func newWorkspaceStore(ctx context.Context, server *Server) *workspaceStore {
 		stores:     []*analysis.Store{},
 		createJobs: make(chan creatorJob),
 		deleteJobs: make(chan string),
+		baselines:  cmap.New(),
 	}
 	for i := 0; i < numCreators; i++ {
 		go workspaceStore.newCreator(i)
@@ -50,32 +63,38 @@ func newWorkspaceStore(ctx context.Context, server *Server) *workspaceStore {
 
 func (s *workspaceStore) newCreator(id int) {
 	for job := range s.createJobs {
-		uri, err := util.DecodeURIFromQuery(job.uri)
-		if err != nil {
-			log.Printf("workspaceStore.getStore cannot DecodeURIFromQuery %s, err: %v", job.uri, err)
-			continue
-		}
-		store := s.getStore(uri)
-		if store == nil {
-			if job.waitGroup != nil {
-				job.waitGroup.Done()
-			}
-			log.Printf("workspaceStore.newCreator store not found: %s", uri)
-			log.Printf("Stores:")
-			for _, store := range s.stores {
-				log.Println(store.GetURI())
-			}
-			continue
-		}
-		store.CompareAndIndexDocument(job.ctx, uri)
-		if job.waitGroup != nil {
-			job.waitGroup.Done()
+		atomic.AddInt64(&s.pendingCreates, -1)
+		s.create(job)
+	}
+}
+
+// create indexes the document of the job, a panic only fails the job
+// so the creator can take the next one
+func (s *workspaceStore) create(job creatorJob) {
+	if job.waitGroup != nil {
+		defer job.waitGroup.Done()
+	}
+	defer s.server.recoverTask(s.ctx, "index", job.uri)
+	uri, err := util.DecodeURIFromQuery(job.uri)
+	if err != nil {
+		log.Printf("workspaceStore.getStore cannot DecodeURIFromQuery %s, err: %v", job.uri, err)
+		return
+	}
+	store := s.getStore(uri)
+	if store == nil {
+		log.Printf("workspaceStore.newCreator store not found: %s", uri)
+		log.Printf("Stores:")
+		for _, store := range s.stores {
+			log.Println(store.GetURI())
 		}
+		return
 	}
+	store.CompareAndIndexDocument(job.ctx, uri)
 }
 
 func (s *workspaceStore) newDeletor(id int) {
 	for uri := range s.deleteJobs {
+		atomic.AddInt64(&s.pendingDeletes, -1)
 		var err error
 		uri, err = util.DecodeURIFromQuery(uri)
 		if err != nil {
@@ -90,6 +109,25 @@ func (s *workspaceStore) newDeletor(id int) {
 	}
 }
 
+// queueCreateJob sends the job to the creators and blocks until one of
+// them receives it
+func (s *workspaceStore) queueCreateJob(job creatorJob) {
+	atomic.AddInt64(&s.pendingCreates, 1)
+	s.createJobs <- job
+}
+
+// queueDeleteJob sends the URI to the deletors and blocks until one of
+// them receives it
+func (s *workspaceStore) queueDeleteJob(uri string) {
+	atomic.AddInt64(&s.pendingDeletes, 1)
+	s.deleteJobs <- uri
+}
+
+// queueDepth returns the numbers of the pending create and delete jobs
+func (s *workspaceStore) queueDepth() (int64, int64) {
+	return atomic.LoadInt64(&s.pendingCreates), atomic.LoadInt64(&s.pendingDeletes)
+}
+
 func (s *workspaceStore) close() {
 	for _, store := range s.stores {
 		store.Close()
@@ -122,7 +160,10 @@ func (s *workspaceStore) addView(ctx context.Context, server *Server, uri protoc
 		log.Printf("No FS found for: %s", uri)
 		return
 	}
-	storagePath := filepath.Join(getDataDir(), "data", util.GetURIID(uri))
+	storagePath := StoragePath(uri)
+	if dataDir := protocol.GetDataDir(ctx); dataDir != "" {
+		storagePath = filepath.Join(dataDir, "data", util.GetURIID(uri))
+	}
 	store, err := analysis.NewStore(fs, uri, storagePath)
 	if err != nil {
 		log.Printf("%s: %v", uri, err)
@@ -131,6 +172,7 @@ func (s *workspaceStore) addView(ctx context.Context, server *Server, uri protoc
 	store.Migrate(protocol.GetVersion(ctx))
 	store.LoadStubs()
 	s.stores = append(s.stores, store)
+	s.loadBaseline(store)
 	if err != nil {
 		log.Printf("addView error: %v", err)
 		return
@@ -183,37 +225,71 @@ func (s *workspaceStore) unregisterFileWatcher(ctx context.Context, path string,
 	})
 }
 
+// StoragePath returns the path of the disk storage of the workspace folder
+func StoragePath(uri protocol.DocumentURI) string {
+	return filepath.Join(getDataDir(), "data", util.GetURIID(uri))
+}
+
+// IndexFolder indexes all the PHP files of the folder with the same creators
+// as the language server, and blocks until the indexing is finished
+func IndexFolder(ctx context.Context, store *analysis.Store, rootPath string) (int, error) {
+	s := &workspaceStore{
+		ctx:        ctx,
+		stores:     []*analysis.Store{store},
+		createJobs: make(chan creatorJob),
+	}
+	for i := 0; i < numCreators; i++ {
+		go s.newCreator(i)
+	}
+	defer close(s.createJobs)
+	store.PrepareForIndexing()
+	return s.indexFiles(ctx, store, rootPath)
+}
+
 func (s *workspaceStore) indexFolder(ctx context.Context, store *analysis.Store, rootPath string) {
-	var waitGroup sync.WaitGroup
 	store.PrepareForIndexing()
+	s.indexing.Add(1)
 	go func() {
+		defer s.indexing.Done()
+		defer s.server.recoverTask(ctx, "indexFolder", store.GetURI())
 		log.Println("Start indexing")
 		start := time.Now()
-		count := 0
-		if docs, err := store.FS.ListFiles(ctx, rootPath); err == nil {
-			for _, doc := range docs {
-				if strings.HasSuffix(doc.URI, ".php") {
-					count++
-					waitGroup.Add(1)
-					s.createJobs <- creatorJob{
-						uri:       store.FS.ConvertToURI(doc.URI),
-						ctx:       ctx,
-						waitGroup: &waitGroup,
-					}
-				}
-			}
-		} else {
+		count, err := s.indexFiles(ctx, store, rootPath)
+		if err != nil {
 			log.Printf("indexFolder: %v", err)
 			return
 		}
-		waitGroup.Wait()
-		store.FinishIndexing()
 		elapsed := time.Since(start)
 		log.Printf("Finished indexing %d files in %s", count, elapsed)
 		util.PrintMemUsage()
 	}()
 }
 
+// indexFiles sends the PHP files of the folder to the creators and waits
+// for all of them to be indexed
+func (s *workspaceStore) indexFiles(ctx context.Context, store *analysis.Store, rootPath string) (int, error) {
+	var waitGroup sync.WaitGroup
+	count := 0
+	docs, err := store.FS.ListFiles(ctx, rootPath)
+	if err != nil {
+		return 0, err
+	}
+	for _, doc := range docs {
+		if strings.HasSuffix(doc.URI, ".php") {
+			count++
+			waitGroup.Add(1)
+			s.queueCreateJob(creatorJob{
+				uri:       store.FS.ConvertToURI(doc.URI),
+				ctx:       ctx,
+				waitGroup: &waitGroup,
+			})
+		}
+	}
+	waitGroup.Wait()
+	store.FinishIndexing()
+	return count, nil
+}
+
 func (s *workspaceStore) getStore(uri protocol.DocumentURI) *analysis.Store {
 	for _, store := range s.stores {
 		if strings.HasPrefix(uri, store.GetURI()) {
@@ -4,14 +4,16 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
-	_ "net/http/pprof"
 	"os"
 	"runtime/pprof"
 
+	"github.com/john-nguyen09/phpintel/internal/cmd"
 	"github.com/john-nguyen09/phpintel/internal/jsonrpc2"
 	"github.com/john-nguyen09/phpintel/internal/lsp"
 	"github.com/john-nguyen09/phpintel/internal/lsp/protocol"
+	"github.com/john-nguyen09/phpintel/util"
 	pl "github.com/virtuald/go-paniclog"
 )
 
@@ -21,6 +23,8 @@ var (
 	memprofile string
 	cpuprofile string
 	panicLog   string
+	debugAddr  string
+	record     string
 )
 
 func main() {
@@ -28,12 +32,18 @@ func main() {
 	flag.StringVar(&memprofile, "memprofile", "", "write mem profile to `file`")
 	flag.StringVar(&cpuprofile, "cpuprofile", "", "write cpu profile to `file`")
 	flag.StringVar(&panicLog, "paniclog", "", "write panic log to `file` (Windows only)")
+	flag.StringVar(&record, "record", "", "record every message of the session with timestamps to `file`, see phpintel replay")
+	flag.StringVar(&debugAddr, "debug-addr", "", "serve pprof, expvar and a status page on `address`, e.g. localhost:6060")
 	flag.Parse()
 
 	if flgVersion {
 		fmt.Println(version)
 		return
 	}
+	if flag.NArg() > 0 {
+		ctx := protocol.WithVersion(context.Background(), version)
+		os.Exit(cmd.Run(ctx, flag.Args(), os.Stdout, os.Stderr))
+	}
 
 	if panicLog != "" {
 		f, err := os.OpenFile(panicLog, os.O_CREATE, os.ModePerm)
@@ -51,11 +61,28 @@ func main() {
 			log.Fatal("could not start CPU profile: ", err)
 		}
 	}
-	stream := jsonrpc2.NewHeaderStream(os.Stdin, os.Stdout)
+	// keep the recent log for the bug reports
+	log.SetOutput(io.MultiWriter(os.Stderr, util.RecentLog))
+	var stream jsonrpc2.Stream = jsonrpc2.NewHeaderStream(os.Stdin, os.Stdout)
+	if record != "" {
+		f, err := os.Create(record)
+		if err != nil {
+			log.Fatal("could not create the recording: ", err)
+		}
+		defer f.Close()
+		stream = jsonrpc2.NewRecordStream(stream, f)
+	}
 	ctx := context.Background()
 	ctx = protocol.WithVersion(ctx, version)
 	ctx = protocol.WithMemprofile(ctx, memprofile)
 	ctx = protocol.WithCpuprofile(ctx, cpuprofile)
 	ctx, srv := lsp.NewServer(ctx, stream)
+	if debugAddr != "" {
+		go func() {
+			if err := lsp.ServeDebug(ctx, debugAddr, srv); err != nil {
+				log.Printf("debug server: %v", err)
+			}
+		}()
+	}
 	srv.Run(ctx)
 }
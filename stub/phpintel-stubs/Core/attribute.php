@@ -0,0 +1,27 @@
+<?php
+
+/**
+ * Attributes offer the ability to add structured, machine-readable metadata information
+ * on declarations in code, every attribute class is declared with <b>#[Attribute]</b>.
+ * @link https://www.php.net/manual/en/class.attribute.php
+ * @since 8.0
+ */
+#[Attribute(Attribute::TARGET_CLASS)]
+final class Attribute {
+    const TARGET_CLASS = 1;
+    const TARGET_FUNCTION = 2;
+    const TARGET_METHOD = 4;
+    const TARGET_PROPERTY = 8;
+    const TARGET_CLASS_CONSTANT = 16;
+    const TARGET_PARAMETER = 32;
+    const TARGET_ALL = 63;
+    const IS_REPEATABLE = 64;
+
+    /** @var int */
+    public $flags;
+
+    /**
+     * @param int $flags The targets of the attribute and whether it is repeatable.
+     */
+    public function __construct($flags = Attribute::TARGET_ALL) {}
+}
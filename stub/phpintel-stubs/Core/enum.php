@@ -0,0 +1,40 @@
+<?php
+
+/**
+ * Every enum implements <b>UnitEnum</b> implicitly.
+ * @link https://www.php.net/manual/en/class.unitenum.php
+ * @since 8.1
+ */
+interface UnitEnum {
+
+    /**
+     * Generates a list of cases on an enum
+     * @link https://www.php.net/manual/en/unitenum.cases.php
+     * @return static[]
+     */
+    public static function cases(): array;
+}
+
+/**
+ * Every backed enum implements <b>BackedEnum</b> implicitly.
+ * @link https://www.php.net/manual/en/class.backedenum.php
+ * @since 8.1
+ */
+interface BackedEnum extends UnitEnum {
+
+    /**
+     * Maps a scalar to an enum instance, a case instance of this enumeration is returned
+     * @link https://www.php.net/manual/en/backedenum.from.php
+     * @param int|string $value The scalar value to map to an enum case.
+     * @return static
+     */
+    public static function from($value);
+
+    /**
+     * Maps a scalar to an enum instance or null if not found
+     * @link https://www.php.net/manual/en/backedenum.tryfrom.php
+     * @param int|string $value The scalar value to map to an enum case.
+     * @return static|null
+     */
+    public static function tryFrom($value);
+}
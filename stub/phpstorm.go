@@ -7,27 +7,43 @@ import (
 	rice "github.com/GeertJohan/go.rice"
 )
 
-type phpStormStubber struct {
-	box *rice.Box
+// boxStubber walks the stubs of a rice box
+type boxStubber struct {
+	name string
+	box  *rice.Box
 }
 
-var _ Stubber = (*phpStormStubber)(nil)
+var _ Stubber = (*boxStubber)(nil)
 
 func newPHPStormStub() (Stubber, error) {
 	box, err := rice.FindBox("phpstorm-stubs")
 	if err != nil {
 		return nil, err
 	}
-	return &phpStormStubber{
-		box: box,
+	return &boxStubber{
+		name: "phpstorm-stubs",
+		box:  box,
 	}, nil
 }
 
-func (s *phpStormStubber) Name() string {
-	return "phpstorm-stubs"
+// newPHPIntelStub returns the stubs which are maintained by phpintel for
+// the declarations missing from phpstorm-stubs
+func newPHPIntelStub() (Stubber, error) {
+	box, err := rice.FindBox("phpintel-stubs")
+	if err != nil {
+		return nil, err
+	}
+	return &boxStubber{
+		name: "phpintel-stubs",
+		box:  box,
+	}, nil
+}
+
+func (s *boxStubber) Name() string {
+	return s.name
 }
 
-func (s *phpStormStubber) Walk(fn WalkFunc) {
+func (s *boxStubber) Walk(fn WalkFunc) {
 	if s.box == nil {
 		return
 	}
@@ -43,6 +59,6 @@ func (s *phpStormStubber) Walk(fn WalkFunc) {
 	})
 }
 
-func (s *phpStormStubber) GetURI(path string) string {
+func (s *boxStubber) GetURI(path string) string {
 	return s.Name() + "://" + strings.ReplaceAll(path, "\\", "/")
 }
@@ -24,11 +24,13 @@ var stubberPrefixes []string = nil
 // GetStubbers initialises stubbers and returns them
 func GetStubbers() []Stubber {
 	if stubbers == nil {
-		phpStorm, err := newPHPStormStub()
-		if err == nil {
-			stubbers = append(stubbers, phpStorm)
-		} else {
-			log.Println(err)
+		for _, newStubber := range []func() (Stubber, error){newPHPStormStub, newPHPIntelStub} {
+			stubber, err := newStubber()
+			if err == nil {
+				stubbers = append(stubbers, stubber)
+			} else {
+				log.Println(err)
+			}
 		}
 	}
 	return stubbers
@@ -0,0 +1,125 @@
+package util
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the buckets of the histograms,
+// the durations above the last bound are counted in an extra bucket
+var LatencyBuckets = []time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// Histogram counts the durations by LatencyBuckets
+type Histogram struct {
+	mu     sync.Mutex
+	counts []int64
+	count  int64
+	total  time.Duration
+	max    time.Duration
+}
+
+// HistogramSnapshot is a copy of a histogram, the durations are in milliseconds
+type HistogramSnapshot struct {
+	Name   string  `json:"name"`
+	Counts []int64 `json:"counts"`
+	Count  int64   `json:"count"`
+	MeanMs float64 `json:"meanMs"`
+	MaxMs  float64 `json:"maxMs"`
+}
+
+// NewHistogram creates an empty histogram
+func NewHistogram() *Histogram {
+	return &Histogram{counts: make([]int64, len(LatencyBuckets)+1)}
+}
+
+// Observe adds the duration to the histogram
+func (h *Histogram) Observe(d time.Duration) {
+	index := sort.Search(len(LatencyBuckets), func(i int) bool {
+		return d <= LatencyBuckets[i]
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[index]++
+	h.count++
+	h.total += d
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// Snapshot copies the histogram
+func (h *Histogram) Snapshot(name string) HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	snapshot := HistogramSnapshot{
+		Name:   name,
+		Counts: append([]int64{}, h.counts...),
+		Count:  h.count,
+		MaxMs:  durationMs(h.max),
+	}
+	if h.count > 0 {
+		snapshot.MeanMs = durationMs(h.total) / float64(h.count)
+	}
+	return snapshot
+}
+
+// Histograms are the histograms of the durations by their names, e.g. the
+// latencies of the LSP methods
+type Histograms struct {
+	mu         sync.Mutex
+	histograms map[string]*Histogram
+}
+
+// NewHistograms creates an empty set of histograms
+func NewHistograms() *Histograms {
+	return &Histograms{histograms: map[string]*Histogram{}}
+}
+
+// Observe adds the duration to the histogram of the name
+func (h *Histograms) Observe(name string, d time.Duration) {
+	h.mu.Lock()
+	histogram, ok := h.histograms[name]
+	if !ok {
+		histogram = NewHistogram()
+		h.histograms[name] = histogram
+	}
+	h.mu.Unlock()
+	histogram.Observe(d)
+}
+
+// Snapshot copies the histograms sorted by their names
+func (h *Histograms) Snapshot() []HistogramSnapshot {
+	h.mu.Lock()
+	names := make([]string, 0, len(h.histograms))
+	for name := range h.histograms {
+		names = append(names, name)
+	}
+	h.mu.Unlock()
+	sort.Strings(names)
+	snapshots := make([]HistogramSnapshot, 0, len(names))
+	for _, name := range names {
+		h.mu.Lock()
+		histogram := h.histograms[name]
+		h.mu.Unlock()
+		snapshots = append(snapshots, histogram.Snapshot(name))
+	}
+	return snapshots
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,28 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistograms(t *testing.T) {
+	h := NewHistograms()
+	h.Observe("textDocument/completion", 500*time.Microsecond)
+	h.Observe("textDocument/completion", time.Millisecond)
+	h.Observe("textDocument/completion", 30*time.Millisecond)
+	h.Observe("textDocument/completion", 10*time.Second)
+	h.Observe("textDocument/hover", 3*time.Millisecond)
+
+	snapshots := h.Snapshot()
+	assert.Len(t, snapshots, 2)
+	completion := snapshots[0]
+	assert.Equal(t, "textDocument/completion", completion.Name)
+	assert.Equal(t, int64(4), completion.Count)
+	assert.Equal(t, []int64{2, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 1}, completion.Counts)
+	assert.Equal(t, 10000.0, completion.MaxMs)
+	assert.InDelta(t, 2507.875, completion.MeanMs, 0.001)
+	assert.Equal(t, "textDocument/hover", snapshots[1].Name)
+	assert.Equal(t, int64(1), snapshots[1].Counts[2])
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"strings"
+	"sync"
+)
+
+// LogBuffer keeps the last lines written to it, it is added as an output of
+// the log package so the bug reports include the recent log
+type LogBuffer struct {
+	mu      sync.Mutex
+	lines   []string
+	next    int
+	full    bool
+	partial string
+}
+
+// RecentLog is the log of the language server
+var RecentLog = NewLogBuffer(1000)
+
+// NewLogBuffer creates a buffer of the last size lines
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{lines: make([]string, size)}
+}
+
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	text := b.partial + string(p)
+	lines := strings.Split(text, "\n")
+	b.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		b.lines[b.next] = line
+		b.next = (b.next + 1) % len(b.lines)
+		if b.next == 0 {
+			b.full = true
+		}
+	}
+	return len(p), nil
+}
+
+// String returns the lines from the oldest to the newest
+func (b *LogBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lines := b.lines[:b.next]
+	if b.full {
+		lines = append(append([]string{}, b.lines[b.next:]...), lines...)
+	}
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString(b.partial)
+	return sb.String()
+}
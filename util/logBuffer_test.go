@@ -0,0 +1,25 @@
+package util
+
+import (
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogBuffer(t *testing.T) {
+	b := NewLogBuffer(3)
+	logger := log.New(b, "", 0)
+	logger.Println("first")
+	assert.Equal(t, "first\n", b.String())
+	for i := 0; i < 4; i++ {
+		logger.Printf("line %d", i)
+	}
+	assert.Equal(t, "line 1\nline 2\nline 3\n", b.String())
+
+	fmt.Fprint(b, "partial")
+	assert.Equal(t, "line 1\nline 2\nline 3\npartial", b.String())
+	fmt.Fprint(b, " line\nnext\n")
+	assert.Equal(t, "line 3\npartial line\nnext\n", b.String())
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"expvar"
 	"log"
 	"runtime"
 	"time"
@@ -9,13 +10,24 @@ import (
 // ShowTimeTrack controls if TimeTrack should be logged
 var ShowTimeTrack = false
 
-// TimeTrack tracks the time
+// Timings are the histograms of the durations tracked by TimeTrack, they
+// are published as the timings expvar
+var Timings = NewHistograms()
+
+func init() {
+	expvar.Publish("timings", expvar.Func(func() interface{} {
+		return Timings.Snapshot()
+	}))
+}
+
+// TimeTrack tracks the time into Timings and logs it if ShowTimeTrack is set
 // Example: `defer util.TimeTrack(time.Now(), "An example")`
 func TimeTrack(start time.Time, name string) {
+	elapsed := time.Since(start)
+	Timings.Observe(name, elapsed)
 	if !ShowTimeTrack {
 		return
 	}
-	elapsed := time.Since(start)
 	log.Printf("%s took %s", name, elapsed)
 }
 
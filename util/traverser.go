@@ -140,6 +140,11 @@ func (s *NodeStack) SetParents(parents []*phrase.Phrase) *NodeStack {
 	return s
 }
 
+// Parents returns the parents of the stack from the root
+func (s NodeStack) Parents() []*phrase.Phrase {
+	return s.parents
+}
+
 // SetToken sets the token to the stack
 func (s *NodeStack) SetToken(token *lexer.Token) *NodeStack {
 	s.token = token